@@ -0,0 +1,196 @@
+// goo-config 配置加密命令行工具
+//
+// 用法:
+//
+//	goo-config genkey  [-kid v1]
+//	goo-config encrypt [-key-file path] [-kid v1] [value]
+//	goo-config decrypt [-key-file path] [value]
+//	goo-config rotate  [-key-file path] [value]
+//
+// 未指定 -key-file 时按 GOO_CONFIG_KEYS、GOO_CONFIG_KEY_FILE 环境变量加载密钥，
+// 未指定 value 时从标准输入读取。
+package main
+
+import (
+	"bufio"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	gooconfig "v2.googo.io/goo-config"
+)
+
+func main() {
+	if len(os.Args) < 2 {
+		usage()
+		os.Exit(2)
+	}
+
+	var err error
+	switch os.Args[1] {
+	case "genkey":
+		err = genkey(os.Args[2:])
+	case "encrypt":
+		err = encrypt(os.Args[2:])
+	case "decrypt":
+		err = decrypt(os.Args[2:])
+	case "rotate":
+		err = rotate(os.Args[2:])
+	case "-h", "-help", "--help", "help":
+		usage()
+		return
+	default:
+		usage()
+		os.Exit(2)
+	}
+
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "goo-config:", err)
+		os.Exit(1)
+	}
+}
+
+func usage() {
+	fmt.Fprintln(os.Stderr, `usage:
+  goo-config genkey  [-kid v1]                         生成密钥（kid=base64key）
+  goo-config encrypt [-key-file path] [-kid v1] [value] 加密，输出 ENC(...)
+  goo-config decrypt [-key-file path] [value]          解密 ENC(...)
+  goo-config rotate  [-key-file path] [value]          使用主密钥重新加密 ENC(...)
+
+未指定 -key-file 时按 GOO_CONFIG_KEYS、GOO_CONFIG_KEY_FILE 环境变量加载密钥
+未指定 value 时从标准输入读取`)
+}
+
+func genkey(args []string) error {
+	fs := flag.NewFlagSet("genkey", flag.ExitOnError)
+	kid := fs.String("kid", "v1", "密钥 id")
+	fs.Parse(args)
+
+	key, err := gooconfig.GenerateKey()
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("%s=%s\n", *kid, key)
+	return nil
+}
+
+func encrypt(args []string) error {
+	fs := flag.NewFlagSet("encrypt", flag.ExitOnError)
+	keyFile := fs.String("key-file", "", "密钥文件路径")
+	kid := fs.String("kid", "", "加密使用的密钥 id，默认为主密钥")
+	fs.Parse(args)
+
+	keyring, err := loadKeyring(*keyFile)
+	if err != nil {
+		return err
+	}
+
+	value, err := readValue(fs.Args())
+	if err != nil {
+		return err
+	}
+
+	if *kid == "" {
+		*kid = keyring.Primary()
+	}
+
+	encrypted, err := keyring.EncryptWithKey(*kid, value)
+	if err != nil {
+		return err
+	}
+
+	fmt.Println(encrypted)
+	return nil
+}
+
+func decrypt(args []string) error {
+	fs := flag.NewFlagSet("decrypt", flag.ExitOnError)
+	keyFile := fs.String("key-file", "", "密钥文件路径")
+	fs.Parse(args)
+
+	keyring, err := loadKeyring(*keyFile)
+	if err != nil {
+		return err
+	}
+
+	value, err := readValue(fs.Args())
+	if err != nil {
+		return err
+	}
+
+	if !gooconfig.IsEncrypted(value) {
+		return errors.New("value is not in ENC(...) format")
+	}
+
+	plaintext, err := keyring.Decrypt(value)
+	if err != nil {
+		return err
+	}
+
+	fmt.Println(plaintext)
+	return nil
+}
+
+func rotate(args []string) error {
+	fs := flag.NewFlagSet("rotate", flag.ExitOnError)
+	keyFile := fs.String("key-file", "", "密钥文件路径")
+	fs.Parse(args)
+
+	keyring, err := loadKeyring(*keyFile)
+	if err != nil {
+		return err
+	}
+
+	value, err := readValue(fs.Args())
+	if err != nil {
+		return err
+	}
+
+	if !gooconfig.IsEncrypted(value) {
+		return errors.New("value is not in ENC(...) format")
+	}
+
+	// 已经使用主密钥加密的值保持不变
+	if gooconfig.KeyId(value) == keyring.Primary() {
+		fmt.Println(value)
+		return nil
+	}
+
+	plaintext, err := keyring.Decrypt(value)
+	if err != nil {
+		return err
+	}
+
+	encrypted, err := keyring.Encrypt(plaintext)
+	if err != nil {
+		return err
+	}
+
+	fmt.Println(encrypted)
+	return nil
+}
+
+func loadKeyring(keyFile string) (*gooconfig.Keyring, error) {
+	if keyFile != "" {
+		return gooconfig.LoadKeyringFile(keyFile)
+	}
+	return gooconfig.LoadKeyring()
+}
+
+// readValue 从参数或标准输入读取值（去除末尾换行）
+func readValue(args []string) (string, error) {
+	if len(args) > 0 {
+		return args[0], nil
+	}
+
+	data, err := io.ReadAll(bufio.NewReader(os.Stdin))
+	if err != nil {
+		return "", err
+	}
+
+	return strings.TrimRight(string(data), "\r\n"), nil
+}
@@ -0,0 +1,77 @@
+package gooconfig
+
+import (
+	"time"
+)
+
+// Format 配置内容格式
+type Format string
+
+const (
+	// FormatJSON JSON 格式
+	FormatJSON Format = "json"
+	// FormatYAML YAML 格式
+	FormatYAML Format = "yaml"
+	// FormatFlat 扁平键格式（前缀下每个键对应一个字段，如 app/redis/addr）
+	FormatFlat Format = "flat"
+)
+
+// Config 配置提供者配置
+type Config struct {
+	// 配置键（单键模式）或键前缀（前缀模式）
+	Key string
+
+	// 是否为前缀模式，默认 false
+	Prefix bool
+
+	// 内容格式，默认 json
+	Format Format
+
+	// 本地快照文件路径，为空时不写快照
+	SnapshotFile string
+
+	// 单次加载超时时间，默认 5 秒
+	LoadTimeout time.Duration
+
+	// 重连最小退避时间，默认 1 秒
+	MinBackoff time.Duration
+
+	// 重连最大退避时间，默认 30 秒
+	MaxBackoff time.Duration
+
+	// 密钥环，用于解密 ENC(...) 值，为空时遇到加密值返回错误
+	Keyring *Keyring
+}
+
+// DefaultConfig 返回默认配置
+func DefaultConfig(opts ...FuncOption) *Config {
+	c := &Config{
+		Format:      FormatJSON,
+		LoadTimeout: 5 * time.Second,
+		MinBackoff:  1 * time.Second,
+		MaxBackoff:  30 * time.Second,
+	}
+	for _, opt := range opts {
+		opt.Apply(c)
+	}
+	return c
+}
+
+// normalize 设置默认值
+func (c *Config) normalize() {
+	if c.Format == "" {
+		c.Format = FormatJSON
+	}
+	if c.LoadTimeout == 0 {
+		c.LoadTimeout = 5 * time.Second
+	}
+	if c.MinBackoff == 0 {
+		c.MinBackoff = 1 * time.Second
+	}
+	if c.MaxBackoff == 0 {
+		c.MaxBackoff = 30 * time.Second
+	}
+	if c.MaxBackoff < c.MinBackoff {
+		c.MaxBackoff = c.MinBackoff
+	}
+}
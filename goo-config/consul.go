@@ -0,0 +1,101 @@
+package gooconfig
+
+import (
+	"context"
+	"time"
+
+	"github.com/hashicorp/consul/api"
+
+	gooconsul "v2.googo.io/goo-consul"
+)
+
+// ConsulSource 基于 goo-consul 的配置源
+type ConsulSource struct {
+	client   *gooconsul.Client
+	waitTime time.Duration
+}
+
+// NewConsulSource 创建 consul 配置源
+func NewConsulSource(client *gooconsul.Client) *ConsulSource {
+	return &ConsulSource{
+		client:   client,
+		waitTime: 30 * time.Second,
+	}
+}
+
+// Name 配置源名称
+func (s *ConsulSource) Name() string {
+	return "consul:" + s.client.Name()
+}
+
+// Load 加载配置
+func (s *ConsulSource) Load(ctx context.Context, key string, prefix bool) ([]*KeyValue, error) {
+	q := (&api.QueryOptions{}).WithContext(ctx)
+
+	if !prefix {
+		pair, _, err := s.client.GetKV(key, q)
+		if err != nil {
+			return nil, err
+		}
+		if pair == nil {
+			return nil, ErrKeyNotFound
+		}
+		return []*KeyValue{{Key: pair.Key, Value: pair.Value}}, nil
+	}
+
+	pairs, _, err := s.client.ListKV(key, q)
+	if err != nil {
+		return nil, err
+	}
+
+	kvs := make([]*KeyValue, 0, len(pairs))
+	for _, pair := range pairs {
+		kvs = append(kvs, &KeyValue{Key: pair.Key, Value: pair.Value})
+	}
+
+	return kvs, nil
+}
+
+// Watch 使用阻塞查询监听配置变化
+func (s *ConsulSource) Watch(ctx context.Context, key string, prefix bool, notify func()) error {
+	var lastIndex uint64
+
+	for {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		q := (&api.QueryOptions{
+			WaitTime:  s.waitTime,
+			WaitIndex: lastIndex,
+		}).WithContext(ctx)
+
+		var (
+			meta *api.QueryMeta
+			err  error
+		)
+		if prefix {
+			_, meta, err = s.client.ListKV(key, q)
+		} else {
+			_, meta, err = s.client.GetKV(key, q)
+		}
+		if err != nil {
+			return err
+		}
+
+		// 首次查询（lastIndex 为 0）或索引变化时通知重新加载
+		if lastIndex == 0 || meta.LastIndex != lastIndex {
+			notify()
+		}
+
+		// 索引回退时（如 consul 重建快照）重置为 0，索引为 0 时按 1 处理，避免空转
+		switch {
+		case meta.LastIndex < lastIndex:
+			lastIndex = 0
+		case meta.LastIndex == 0:
+			lastIndex = 1
+		default:
+			lastIndex = meta.LastIndex
+		}
+	}
+}
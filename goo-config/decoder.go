@@ -0,0 +1,255 @@
+package gooconfig
+
+import (
+	"bytes"
+	"encoding"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"github.com/goccy/go-yaml"
+)
+
+// decode 将配置源中的键值解码为规范化的 JSON 内容
+// 规范化后的内容用于变更比较、写入快照以及解码到目标结构体（使用 json 标签）
+func decode(format Format, key string, prefix bool, kvs []*KeyValue) ([]byte, error) {
+	var tree any
+
+	if !prefix {
+		if len(kvs) == 0 {
+			return nil, ErrKeyNotFound
+		}
+
+		value, err := decodeValue(format, kvs[0].Value)
+		if err != nil {
+			return nil, fmt.Errorf("decode key %s: %w", kvs[0].Key, err)
+		}
+		tree = value
+	} else {
+		root := map[string]any{}
+		for _, kv := range kvs {
+			value, err := decodeValue(format, kv.Value)
+			if err != nil {
+				return nil, fmt.Errorf("decode key %s: %w", kv.Key, err)
+			}
+			setPath(root, splitPath(key, kv.Key), value)
+		}
+		tree = root
+	}
+
+	return json.Marshal(tree)
+}
+
+// decodeValue 按格式解码单个值
+func decodeValue(format Format, data []byte) (any, error) {
+	var value any
+
+	switch format {
+	case FormatJSON:
+		d := json.NewDecoder(bytes.NewReader(data))
+		d.UseNumber()
+		if err := d.Decode(&value); err != nil {
+			return nil, err
+		}
+	case FormatYAML:
+		if err := yaml.Unmarshal(data, &value); err != nil {
+			return nil, err
+		}
+	case FormatFlat:
+		// 扁平键的值保留为字符串，解码时按目标字段类型转换（见 unmarshal）
+		value = string(data)
+	default:
+		return nil, ErrUnsupportedFormat
+	}
+
+	return value, nil
+}
+
+// unmarshal 将规范化的 JSON 内容解码到 v
+// 扁平键格式的值均为字符串，按目标字段类型转换：数字、布尔字段解析字符串，
+// 结构体、map、切片字段的字符串按 JSON 解析，字符串字段保持原样（如 "123456"、"true"、"007"）
+func unmarshal(format Format, data []byte, v any) error {
+	if format != FormatFlat {
+		return json.Unmarshal(data, v)
+	}
+
+	var tree any
+	d := json.NewDecoder(bytes.NewReader(data))
+	d.UseNumber()
+	if err := d.Decode(&tree); err != nil {
+		return err
+	}
+
+	converted, err := json.Marshal(weakConvert(tree, reflect.TypeOf(v)))
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(converted, v)
+}
+
+var (
+	jsonUnmarshalerType = reflect.TypeFor[json.Unmarshaler]()
+	textUnmarshalerType = reflect.TypeFor[encoding.TextUnmarshaler]()
+)
+
+// weakConvert 按目标类型转换扁平键的值，无法转换时保持原值，由 json.Unmarshal 报错
+func weakConvert(value any, t reflect.Type) any {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+
+	// 自定义解码的类型（如 time.Time）保持原值
+	if reflect.PointerTo(t).Implements(jsonUnmarshalerType) || reflect.PointerTo(t).Implements(textUnmarshalerType) {
+		return value
+	}
+
+	if s, ok := value.(string); ok && t.Kind() != reflect.String && t.Kind() != reflect.Interface {
+		trimmed := strings.TrimSpace(s)
+		if trimmed == "null" {
+			return nil
+		}
+
+		switch t.Kind() {
+		case reflect.Bool:
+			if b, err := strconv.ParseBool(trimmed); err == nil {
+				return b
+			}
+			return value
+		case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+			reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+			reflect.Float32, reflect.Float64:
+			if _, err := strconv.ParseFloat(trimmed, 64); err == nil {
+				return json.Number(trimmed)
+			}
+			return value
+		case reflect.Struct, reflect.Map, reflect.Slice, reflect.Array:
+			d := json.NewDecoder(strings.NewReader(trimmed))
+			d.UseNumber()
+			var parsed any
+			if err := d.Decode(&parsed); err != nil {
+				return value
+			}
+			value = parsed
+		default:
+			return value
+		}
+	}
+
+	switch v := value.(type) {
+	case map[string]any:
+		switch t.Kind() {
+		case reflect.Struct:
+			for key, child := range v {
+				if field, ok := findJSONField(t, key); ok {
+					v[key] = weakConvert(child, field.Type)
+				}
+			}
+		case reflect.Map:
+			for key, child := range v {
+				v[key] = weakConvert(child, t.Elem())
+			}
+		}
+	case []any:
+		if t.Kind() == reflect.Slice || t.Kind() == reflect.Array {
+			for i, child := range v {
+				v[i] = weakConvert(child, t.Elem())
+			}
+		}
+	case json.Number:
+		// 旧快照中按 JSON 解析的数字、布尔值解码到字符串字段
+		if t.Kind() == reflect.String {
+			return v.String()
+		}
+	case bool:
+		if t.Kind() == reflect.String {
+			return strconv.FormatBool(v)
+		}
+	}
+
+	return value
+}
+
+// findJSONField 按 encoding/json 的规则查找键对应的字段：优先完全匹配，其次不区分大小写
+func findJSONField(t reflect.Type, key string) (reflect.StructField, bool) {
+	var folded *reflect.StructField
+	for _, field := range jsonFields(t) {
+		name := field.Name
+		if tag, _, _ := strings.Cut(field.Tag.Get("json"), ","); tag != "" {
+			name = tag
+		}
+		if name == key {
+			return field, true
+		}
+		if folded == nil && strings.EqualFold(name, key) {
+			folded = &field
+		}
+	}
+	if folded != nil {
+		return *folded, true
+	}
+	return reflect.StructField{}, false
+}
+
+// jsonFields 参与 JSON 解码的字段，展开没有 json 标签的嵌入结构体
+func jsonFields(t reflect.Type) []reflect.StructField {
+	var fields []reflect.StructField
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		tag := field.Tag.Get("json")
+		if tag == "-" {
+			continue
+		}
+
+		if field.Anonymous && tag == "" {
+			ft := field.Type
+			if ft.Kind() == reflect.Pointer {
+				ft = ft.Elem()
+			}
+			if ft.Kind() == reflect.Struct {
+				fields = append(fields, jsonFields(ft)...)
+				continue
+			}
+		}
+
+		if field.IsExported() {
+			fields = append(fields, field)
+		}
+	}
+	return fields
+}
+
+// splitPath 去除前缀后按 "/" 切分键路径
+func splitPath(prefix, key string) []string {
+	rel := strings.Trim(strings.TrimPrefix(key, prefix), "/")
+	if rel == "" {
+		return nil
+	}
+	return strings.Split(rel, "/")
+}
+
+// setPath 将值写入嵌套 map 的指定路径
+// 路径为空（前缀键本身）且值为对象时合并到根节点
+func setPath(root map[string]any, path []string, value any) {
+	if len(path) == 0 {
+		if m, ok := value.(map[string]any); ok {
+			for k, v := range m {
+				root[k] = v
+			}
+		}
+		return
+	}
+
+	node := root
+	for _, p := range path[:len(path)-1] {
+		child, ok := node[p].(map[string]any)
+		if !ok {
+			child = map[string]any{}
+			node[p] = child
+		}
+		node = child
+	}
+
+	node[path[len(path)-1]] = value
+}
@@ -0,0 +1,102 @@
+package gooconfig
+
+import (
+	"testing"
+	"time"
+)
+
+func TestFlatValuesConvertByFieldType(t *testing.T) {
+	type redis struct {
+		Addr     string `json:"addr"`
+		DB       int    `json:"db"`
+		Password string `json:"password"`
+	}
+	type config struct {
+		RateLimit int            `json:"rate_limit"`
+		Ratio     float64        `json:"ratio"`
+		Debug     bool           `json:"debug"`
+		Code      string         `json:"code"`
+		Flag      string         `json:"flag"`
+		Zip       string         `json:"zip"`
+		Hosts     []string       `json:"hosts"`
+		Weights   map[string]int `json:"weights"`
+		Timeout   *int64         `json:"timeout"`
+		Start     time.Time      `json:"start"`
+		Redis     redis          `json:"redis"`
+	}
+
+	kvs := []*KeyValue{
+		{Key: "app/rate_limit", Value: []byte("100")},
+		{Key: "app/ratio", Value: []byte("0.5")},
+		{Key: "app/debug", Value: []byte("true")},
+		{Key: "app/code", Value: []byte("123456")},
+		{Key: "app/flag", Value: []byte("true")},
+		{Key: "app/zip", Value: []byte("007")},
+		{Key: "app/hosts", Value: []byte(`["a","b"]`)},
+		{Key: "app/weights/a", Value: []byte("3")},
+		{Key: "app/timeout", Value: []byte("30")},
+		{Key: "app/start", Value: []byte("2024-06-01T00:00:00Z")},
+		{Key: "app/redis/addr", Value: []byte("127.0.0.1:6379")},
+		{Key: "app/redis/db", Value: []byte("1")},
+		{Key: "app/redis/password", Value: []byte("000123")},
+	}
+
+	raw, err := decode(FormatFlat, "app/", true, kvs)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	v := new(config)
+	if err := unmarshal(FormatFlat, raw, v); err != nil {
+		t.Fatal(err)
+	}
+
+	if v.RateLimit != 100 || v.Ratio != 0.5 || !v.Debug {
+		t.Fatalf("numbers and bools = %d %v %v", v.RateLimit, v.Ratio, v.Debug)
+	}
+	if v.Code != "123456" || v.Flag != "true" || v.Zip != "007" || v.Redis.Password != "000123" {
+		t.Fatalf("strings = %q %q %q %q", v.Code, v.Flag, v.Zip, v.Redis.Password)
+	}
+	if len(v.Hosts) != 2 || v.Hosts[1] != "b" || v.Weights["a"] != 3 {
+		t.Fatalf("hosts = %v, weights = %v", v.Hosts, v.Weights)
+	}
+	if v.Timeout == nil || *v.Timeout != 30 {
+		t.Fatalf("timeout = %v", v.Timeout)
+	}
+	if !v.Start.Equal(time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC)) {
+		t.Fatalf("start = %v", v.Start)
+	}
+	if v.Redis.Addr != "127.0.0.1:6379" || v.Redis.DB != 1 {
+		t.Fatalf("redis = %+v", v.Redis)
+	}
+}
+
+func TestFlatInvalidNumberFails(t *testing.T) {
+	type config struct {
+		Port int `json:"port"`
+	}
+
+	raw, err := decode(FormatFlat, "app/", true, []*KeyValue{{Key: "app/port", Value: []byte("abc")}})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := unmarshal(FormatFlat, raw, new(config)); err == nil {
+		t.Fatal("unmarshal succeeded")
+	}
+}
+
+// 旧快照中按 JSON 解析的数字解码到字符串字段
+func TestFlatSnapshotNumberToString(t *testing.T) {
+	type config struct {
+		Code string `json:"code"`
+	}
+
+	v := new(config)
+	if err := unmarshal(FormatFlat, []byte(`{"code":123456}`), v); err != nil {
+		t.Fatal(err)
+	}
+	if v.Code != "123456" {
+		t.Fatalf("code = %q", v.Code)
+	}
+}
@@ -0,0 +1,24 @@
+package gooconfig
+
+import "errors"
+
+var (
+	// ErrEmptyKey 配置键为空
+	ErrEmptyKey = errors.New("config key is empty")
+	// ErrNilSource 配置源为空
+	ErrNilSource = errors.New("config source is nil")
+	// ErrKeyNotFound 配置键不存在
+	ErrKeyNotFound = errors.New("config key not found")
+	// ErrUnsupportedFormat 不支持的配置格式
+	ErrUnsupportedFormat = errors.New("unsupported config format")
+	// ErrWatcherClosed 监听器已关闭
+	ErrWatcherClosed = errors.New("config watcher closed")
+	// ErrInvalidKeyring 无效的密钥环
+	ErrInvalidKeyring = errors.New("invalid keyring")
+	// ErrNilKeyring 存在加密值但未配置密钥环
+	ErrNilKeyring = errors.New("encrypted value found but keyring is nil")
+	// ErrInvalidCiphertext 无效的密文
+	ErrInvalidCiphertext = errors.New("invalid ciphertext")
+	// ErrDecryptFailed 解密失败
+	ErrDecryptFailed = errors.New("decrypt failed")
+)
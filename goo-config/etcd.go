@@ -0,0 +1,81 @@
+package gooconfig
+
+import (
+	"context"
+	"errors"
+
+	clientv3 "go.etcd.io/etcd/client/v3"
+
+	gooetcd "v2.googo.io/goo-etcd"
+)
+
+// EtcdSource 基于 goo-etcd 的配置源
+type EtcdSource struct {
+	client *gooetcd.Client
+}
+
+// NewEtcdSource 创建 etcd 配置源
+func NewEtcdSource(client *gooetcd.Client) *EtcdSource {
+	return &EtcdSource{client: client}
+}
+
+// Name 配置源名称
+func (s *EtcdSource) Name() string {
+	return "etcd:" + s.client.Name()
+}
+
+// Load 加载配置
+func (s *EtcdSource) Load(ctx context.Context, key string, prefix bool) ([]*KeyValue, error) {
+	var opts []clientv3.OpOption
+	if prefix {
+		opts = append(opts, clientv3.WithPrefix())
+	}
+
+	resp, err := s.client.Get(ctx, key, opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	if !prefix && len(resp.Kvs) == 0 {
+		return nil, ErrKeyNotFound
+	}
+
+	kvs := make([]*KeyValue, 0, len(resp.Kvs))
+	for _, kv := range resp.Kvs {
+		kvs = append(kvs, &KeyValue{Key: string(kv.Key), Value: kv.Value})
+	}
+
+	return kvs, nil
+}
+
+// Watch 监听配置变化
+func (s *EtcdSource) Watch(ctx context.Context, key string, prefix bool, notify func()) error {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	// WithRequireLeader 使网络分区时监听能够及时返回错误
+	opts := []clientv3.OpOption{clientv3.WithCreatedNotify()}
+	if prefix {
+		opts = append(opts, clientv3.WithPrefix())
+	}
+
+	wch := s.client.Watch(clientv3.WithRequireLeader(ctx), key, opts...)
+	for resp := range wch {
+		if err := resp.Err(); err != nil {
+			return err
+		}
+		if resp.Canceled {
+			return errors.New("etcd watch canceled")
+		}
+
+		// 监听建立（Created）或发生变化时通知重新加载
+		if resp.Created || len(resp.Events) > 0 {
+			notify()
+		}
+	}
+
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	return errors.New("etcd watch channel closed")
+}
@@ -0,0 +1,39 @@
+package gooconfig
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// LoadFile 加载本地配置文件并解密 ENC(...) 值
+// 根据扩展名选择格式：.yaml/.yml 为 YAML，其余为 JSON
+func LoadFile[T any](path string, keyring *Keyring) (*T, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	format := FormatJSON
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".yaml", ".yml":
+		format = FormatYAML
+	}
+
+	raw, err := decode(format, path, false, []*KeyValue{{Key: path, Value: data}})
+	if err != nil {
+		return nil, err
+	}
+
+	if raw, err = decryptJSON(keyring, raw); err != nil {
+		return nil, err
+	}
+
+	v := new(T)
+	if err = json.Unmarshal(raw, v); err != nil {
+		return nil, err
+	}
+
+	return v, nil
+}
@@ -0,0 +1,319 @@
+package gooconfig
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"sync"
+)
+
+const (
+	// EnvKeys 主密钥环境变量，格式: kid=base64key[,kid=base64key...]，第一个为当前加密密钥
+	EnvKeys = "GOO_CONFIG_KEYS"
+	// EnvKeyFile 主密钥文件路径环境变量
+	EnvKeyFile = "GOO_CONFIG_KEY_FILE"
+
+	encPrefix = "ENC("
+	encSuffix = ")"
+)
+
+// Keyring 配置加解密密钥环
+// 每个密钥使用 kid 标识，加密使用主密钥，解密根据密文中的 kid 选择密钥，便于密钥轮换
+type Keyring struct {
+	primary string
+	aeads   map[string]cipher.AEAD
+	mu      sync.RWMutex
+}
+
+// NewKeyring 创建密钥环，primary 为加密使用的密钥 id，keys 的值必须为 32 字节（AES-256）
+func NewKeyring(primary string, keys map[string][]byte) (*Keyring, error) {
+	kr := &Keyring{
+		aeads: make(map[string]cipher.AEAD, len(keys)),
+	}
+
+	for kid, key := range keys {
+		if err := kr.add(kid, key); err != nil {
+			return nil, err
+		}
+	}
+
+	if _, ok := kr.aeads[primary]; !ok {
+		return nil, fmt.Errorf("%w: primary key %q not found", ErrInvalidKeyring, primary)
+	}
+	kr.primary = primary
+
+	return kr, nil
+}
+
+// ParseKeyring 解析密钥环文本
+// 每行（或逗号分隔）一个密钥，格式: kid=base64key，空行和 # 开头的行忽略，第一个密钥为主密钥
+func ParseKeyring(data []byte) (*Keyring, error) {
+	var (
+		primary string
+		keys    = map[string][]byte{}
+	)
+
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for scanner.Scan() {
+		for _, item := range strings.Split(scanner.Text(), ",") {
+			item = strings.TrimSpace(item)
+			if item == "" || strings.HasPrefix(item, "#") {
+				continue
+			}
+
+			kid, encoded, ok := strings.Cut(item, "=")
+			kid = strings.TrimSpace(kid)
+			if !ok || kid == "" {
+				return nil, fmt.Errorf("%w: invalid entry", ErrInvalidKeyring)
+			}
+
+			key, err := base64.StdEncoding.DecodeString(strings.TrimSpace(encoded))
+			if err != nil {
+				return nil, fmt.Errorf("%w: key %q is not valid base64", ErrInvalidKeyring, kid)
+			}
+			if _, exists := keys[kid]; exists {
+				return nil, fmt.Errorf("%w: duplicate key %q", ErrInvalidKeyring, kid)
+			}
+
+			keys[kid] = key
+			if primary == "" {
+				primary = kid
+			}
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	if len(keys) == 0 {
+		return nil, fmt.Errorf("%w: no keys", ErrInvalidKeyring)
+	}
+
+	return NewKeyring(primary, keys)
+}
+
+// LoadKeyringFile 从文件加载密钥环
+func LoadKeyringFile(path string) (*Keyring, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return ParseKeyring(data)
+}
+
+// LoadKeyringEnv 从环境变量加载密钥环
+func LoadKeyringEnv(name string) (*Keyring, error) {
+	value := os.Getenv(name)
+	if value == "" {
+		return nil, fmt.Errorf("%w: env %s is empty", ErrInvalidKeyring, name)
+	}
+	return ParseKeyring([]byte(value))
+}
+
+// LoadKeyring 按 GOO_CONFIG_KEYS、GOO_CONFIG_KEY_FILE 的顺序加载密钥环
+func LoadKeyring() (*Keyring, error) {
+	if os.Getenv(EnvKeys) != "" {
+		return LoadKeyringEnv(EnvKeys)
+	}
+	if path := os.Getenv(EnvKeyFile); path != "" {
+		return LoadKeyringFile(path)
+	}
+	return nil, fmt.Errorf("%w: neither %s nor %s is set", ErrInvalidKeyring, EnvKeys, EnvKeyFile)
+}
+
+// GenerateKey 生成一个随机的 32 字节密钥（base64 编码）
+func GenerateKey() (string, error) {
+	key := make([]byte, 32)
+	if _, err := io.ReadFull(rand.Reader, key); err != nil {
+		return "", err
+	}
+	return base64.StdEncoding.EncodeToString(key), nil
+}
+
+// Primary 获取主密钥 id
+func (kr *Keyring) Primary() string {
+	kr.mu.RLock()
+	defer kr.mu.RUnlock()
+	return kr.primary
+}
+
+// SetPrimary 切换主密钥（密钥轮换）
+func (kr *Keyring) SetPrimary(kid string) error {
+	kr.mu.Lock()
+	defer kr.mu.Unlock()
+
+	if _, ok := kr.aeads[kid]; !ok {
+		return fmt.Errorf("%w: key %q not found", ErrInvalidKeyring, kid)
+	}
+	kr.primary = kid
+	return nil
+}
+
+// Add 添加密钥
+func (kr *Keyring) Add(kid string, key []byte) error {
+	kr.mu.Lock()
+	defer kr.mu.Unlock()
+	return kr.add(kid, key)
+}
+
+func (kr *Keyring) add(kid string, key []byte) error {
+	if kid == "" || strings.ContainsAny(kid, ":()") {
+		return fmt.Errorf("%w: invalid key id %q", ErrInvalidKeyring, kid)
+	}
+	if len(key) != 32 {
+		return fmt.Errorf("%w: key %q must be 32 bytes", ErrInvalidKeyring, kid)
+	}
+
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return err
+	}
+
+	aead, err := cipher.NewGCM(block)
+	if err != nil {
+		return err
+	}
+
+	kr.aeads[kid] = aead
+	return nil
+}
+
+// Encrypt 使用主密钥加密，返回 ENC(kid:base64) 格式的值
+func (kr *Keyring) Encrypt(plaintext string) (string, error) {
+	return kr.EncryptWithKey(kr.Primary(), plaintext)
+}
+
+// EncryptWithKey 使用指定密钥加密
+func (kr *Keyring) EncryptWithKey(kid string, plaintext string) (string, error) {
+	kr.mu.RLock()
+	aead, ok := kr.aeads[kid]
+	kr.mu.RUnlock()
+
+	if !ok {
+		return "", fmt.Errorf("%w: key %q not found", ErrInvalidKeyring, kid)
+	}
+
+	nonce := make([]byte, aead.NonceSize())
+	if _, err := io.ReadFull(rand.Reader, nonce); err != nil {
+		return "", err
+	}
+
+	// kid 作为附加数据，防止密文被挪用到其他 kid 下
+	sealed := aead.Seal(nonce, nonce, []byte(plaintext), []byte(kid))
+
+	return encPrefix + kid + ":" + base64.StdEncoding.EncodeToString(sealed) + encSuffix, nil
+}
+
+// Decrypt 解密 ENC(kid:base64) 格式的值，非加密值原样返回
+func (kr *Keyring) Decrypt(value string) (string, error) {
+	if !IsEncrypted(value) {
+		return value, nil
+	}
+
+	kid, encoded, ok := strings.Cut(value[len(encPrefix):len(value)-len(encSuffix)], ":")
+	if !ok {
+		return "", ErrInvalidCiphertext
+	}
+
+	kr.mu.RLock()
+	aead, exists := kr.aeads[kid]
+	kr.mu.RUnlock()
+
+	if !exists {
+		return "", fmt.Errorf("%w: key %q not found", ErrInvalidKeyring, kid)
+	}
+
+	sealed, err := base64.StdEncoding.DecodeString(encoded)
+	if err != nil || len(sealed) < aead.NonceSize() {
+		return "", ErrInvalidCiphertext
+	}
+
+	nonce, ciphertext := sealed[:aead.NonceSize()], sealed[aead.NonceSize():]
+	plaintext, err := aead.Open(nil, nonce, ciphertext, []byte(kid))
+	if err != nil {
+		return "", fmt.Errorf("%w: key %q", ErrDecryptFailed, kid)
+	}
+
+	return string(plaintext), nil
+}
+
+// KeyId 获取加密值使用的密钥 id
+func KeyId(value string) string {
+	if !IsEncrypted(value) {
+		return ""
+	}
+	kid, _, _ := strings.Cut(value[len(encPrefix):len(value)-len(encSuffix)], ":")
+	return kid
+}
+
+// IsEncrypted 判断值是否为 ENC(...) 格式
+func IsEncrypted(value string) bool {
+	return len(value) > len(encPrefix)+len(encSuffix) &&
+		strings.HasPrefix(value, encPrefix) &&
+		strings.HasSuffix(value, encSuffix)
+}
+
+// decryptJSON 解密 JSON 内容中所有 ENC(...) 字符串值
+// 错误信息只包含字段路径，不包含任何明文
+func decryptJSON(kr *Keyring, raw []byte) ([]byte, error) {
+	if !bytes.Contains(raw, []byte(encPrefix)) {
+		return raw, nil
+	}
+
+	var tree any
+	d := json.NewDecoder(bytes.NewReader(raw))
+	d.UseNumber()
+	if err := d.Decode(&tree); err != nil {
+		return nil, err
+	}
+
+	tree, err := decryptTree(kr, tree, "")
+	if err != nil {
+		return nil, err
+	}
+
+	return json.Marshal(tree)
+}
+
+func decryptTree(kr *Keyring, node any, path string) (any, error) {
+	switch v := node.(type) {
+	case map[string]any:
+		for k, child := range v {
+			decrypted, err := decryptTree(kr, child, path+"."+k)
+			if err != nil {
+				return nil, err
+			}
+			v[k] = decrypted
+		}
+	case []any:
+		for i, child := range v {
+			decrypted, err := decryptTree(kr, child, fmt.Sprintf("%s[%d]", path, i))
+			if err != nil {
+				return nil, err
+			}
+			v[i] = decrypted
+		}
+	case string:
+		if !IsEncrypted(v) {
+			return v, nil
+		}
+		if kr == nil {
+			return nil, fmt.Errorf("%w: field %s", ErrNilKeyring, strings.TrimPrefix(path, "."))
+		}
+		plaintext, err := kr.Decrypt(v)
+		if err != nil {
+			return nil, fmt.Errorf("field %s: %w", strings.TrimPrefix(path, "."), err)
+		}
+		return plaintext, nil
+	}
+
+	return node, nil
+}
@@ -0,0 +1,63 @@
+package gooconfig
+
+import (
+	"time"
+)
+
+type FuncOption func(c *Config)
+
+func (o FuncOption) Apply(c *Config) {
+	o(c)
+}
+
+// WithKey 设置配置键（单键模式）
+func WithKey(key string) FuncOption {
+	return func(c *Config) {
+		c.Key = key
+		c.Prefix = false
+	}
+}
+
+// WithPrefix 设置配置键前缀（前缀模式）
+func WithPrefix(prefix string) FuncOption {
+	return func(c *Config) {
+		c.Key = prefix
+		c.Prefix = true
+	}
+}
+
+// WithFormat 设置内容格式
+func WithFormat(format Format) FuncOption {
+	return func(c *Config) {
+		c.Format = format
+	}
+}
+
+// WithSnapshotFile 设置本地快照文件路径
+func WithSnapshotFile(snapshotFile string) FuncOption {
+	return func(c *Config) {
+		c.SnapshotFile = snapshotFile
+	}
+}
+
+// WithLoadTimeout 设置单次加载超时时间
+func WithLoadTimeout(loadTimeout time.Duration) FuncOption {
+	return func(c *Config) {
+		c.LoadTimeout = loadTimeout
+	}
+}
+
+// WithBackoff 设置重连退避时间范围
+func WithBackoff(minBackoff, maxBackoff time.Duration) FuncOption {
+	return func(c *Config) {
+		c.MinBackoff = minBackoff
+		c.MaxBackoff = maxBackoff
+	}
+}
+
+// WithKeyring 设置解密 ENC(...) 值使用的密钥环
+func WithKeyring(keyring *Keyring) FuncOption {
+	return func(c *Config) {
+		c.Keyring = keyring
+	}
+}
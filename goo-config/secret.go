@@ -0,0 +1,41 @@
+package gooconfig
+
+import (
+	"fmt"
+)
+
+const redacted = "******"
+
+// Secret 敏感配置值
+// 打印（%v、%s、%+v、%#v 等）和 JSON 序列化时均输出 ******，通过 Value() 获取明文
+type Secret string
+
+// Value 获取明文
+func (s Secret) Value() string {
+	return string(s)
+}
+
+// String 实现 fmt.Stringer
+func (s Secret) String() string {
+	return redacted
+}
+
+// GoString 实现 fmt.GoStringer
+func (s Secret) GoString() string {
+	return redacted
+}
+
+// Format 实现 fmt.Formatter，所有格式化动词均输出 ******
+func (s Secret) Format(f fmt.State, verb rune) {
+	_, _ = f.Write([]byte(redacted))
+}
+
+// MarshalJSON 序列化时隐藏明文
+func (s Secret) MarshalJSON() ([]byte, error) {
+	return []byte(`"` + redacted + `"`), nil
+}
+
+// MarshalText 序列化时隐藏明文
+func (s Secret) MarshalText() ([]byte, error) {
+	return []byte(redacted), nil
+}
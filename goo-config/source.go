@@ -0,0 +1,24 @@
+package gooconfig
+
+import (
+	"context"
+)
+
+// KeyValue 配置源中的键值对
+type KeyValue struct {
+	Key   string
+	Value []byte
+}
+
+// Source 配置源
+type Source interface {
+	// Name 配置源名称（用于日志）
+	Name() string
+
+	// Load 加载配置，prefix 为 true 时加载前缀下的所有键
+	Load(ctx context.Context, key string, prefix bool) ([]*KeyValue, error)
+
+	// Watch 阻塞监听配置变化，监听建立后及每次变化时调用 notify
+	// 连接断开或出错时返回错误，由调用方负责重连
+	Watch(ctx context.Context, key string, prefix bool, notify func()) error
+}
@@ -0,0 +1,297 @@
+package gooconfig
+
+import (
+	"bytes"
+	"context"
+	"math/rand"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	goolog "v2.googo.io/goo-log"
+)
+
+// ChangeHandler 配置变更回调
+type ChangeHandler[T any] func(old, new *T)
+
+// Watcher 将配置源中的配置解码为 T，并通过监听保持更新
+type Watcher[T any] struct {
+	source   Source
+	config   *Config
+	value    atomic.Pointer[T]
+	raw      []byte
+	handlers []ChangeHandler[T]
+	mu       sync.Mutex // 保护 handlers、closed
+	applyMu  sync.Mutex // 串行化配置替换与回调
+	cancel   context.CancelFunc
+	wg       sync.WaitGroup
+	closed   bool
+}
+
+// NewWatcher 创建配置监听器
+// 启动时从配置源加载配置，配置源不可用时回退到本地快照文件
+func NewWatcher[T any](source Source, config *Config) (*Watcher[T], error) {
+	if source == nil {
+		return nil, ErrNilSource
+	}
+
+	if config == nil {
+		config = DefaultConfig()
+	}
+	config.normalize()
+
+	if config.Key == "" {
+		return nil, ErrEmptyKey
+	}
+
+	w := &Watcher[T]{
+		source: source,
+		config: config,
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	w.cancel = cancel
+
+	if err := w.reload(ctx); err != nil {
+		if config.SnapshotFile == "" {
+			cancel()
+			return nil, err
+		}
+
+		if snapErr := w.loadSnapshot(); snapErr != nil {
+			cancel()
+			return nil, err
+		}
+
+		goolog.WithField("source", source.Name()).
+			WithField("key", config.Key).
+			WithField("error", err.Error()).
+			WarnF("[goo-config] load failed, fallback to snapshot %s", config.SnapshotFile)
+	}
+
+	w.wg.Add(1)
+	go w.run(ctx)
+
+	return w, nil
+}
+
+// Load 从配置源一次性加载配置（不监听）
+func Load[T any](ctx context.Context, source Source, config *Config) (*T, error) {
+	if source == nil {
+		return nil, ErrNilSource
+	}
+
+	if config == nil {
+		config = DefaultConfig()
+	}
+	config.normalize()
+
+	if config.Key == "" {
+		return nil, ErrEmptyKey
+	}
+
+	kvs, err := source.Load(ctx, config.Key, config.Prefix)
+	if err != nil {
+		return nil, err
+	}
+
+	raw, err := decode(config.Format, config.Key, config.Prefix, kvs)
+	if err != nil {
+		return nil, err
+	}
+
+	if raw, err = decryptJSON(config.Keyring, raw); err != nil {
+		return nil, err
+	}
+
+	v := new(T)
+	if err = unmarshal(config.Format, raw, v); err != nil {
+		return nil, err
+	}
+
+	return v, nil
+}
+
+// Get 获取当前配置（返回值只读，不要修改）
+func (w *Watcher[T]) Get() *T {
+	return w.value.Load()
+}
+
+// OnChange 注册配置变更回调
+func (w *Watcher[T]) OnChange(fn ChangeHandler[T]) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.handlers = append(w.handlers, fn)
+}
+
+// Reload 立即从配置源重新加载配置
+func (w *Watcher[T]) Reload(ctx context.Context) error {
+	return w.reload(ctx)
+}
+
+// Close 停止监听
+func (w *Watcher[T]) Close() error {
+	w.mu.Lock()
+	if w.closed {
+		w.mu.Unlock()
+		return nil
+	}
+	w.closed = true
+	w.mu.Unlock()
+
+	w.cancel()
+	w.wg.Wait()
+
+	return nil
+}
+
+// run 监听配置源，断开后按指数退避重连
+func (w *Watcher[T]) run(ctx context.Context) {
+	defer w.wg.Done()
+
+	backoff := w.config.MinBackoff
+
+	for {
+		established := false
+
+		err := w.source.Watch(ctx, w.config.Key, w.config.Prefix, func() {
+			established = true
+
+			if err := w.reload(ctx); err != nil && ctx.Err() == nil {
+				goolog.WithField("source", w.source.Name()).
+					WithField("key", w.config.Key).
+					WithField("error", err.Error()).
+					Warn("[goo-config] reload failed")
+			}
+		})
+
+		if ctx.Err() != nil {
+			return
+		}
+
+		// 监听曾经成功建立，说明连接已恢复，重置退避时间
+		if established {
+			backoff = w.config.MinBackoff
+		}
+
+		goolog.WithField("source", w.source.Name()).
+			WithField("key", w.config.Key).
+			WithField("error", errString(err)).
+			WarnF("[goo-config] watch interrupted, retry in %s", backoff)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(jitter(backoff)):
+		}
+
+		backoff *= 2
+		if backoff > w.config.MaxBackoff {
+			backoff = w.config.MaxBackoff
+		}
+	}
+}
+
+// reload 从配置源加载配置，内容变化时更新并触发回调
+func (w *Watcher[T]) reload(ctx context.Context) error {
+	ctx, cancel := context.WithTimeout(ctx, w.config.LoadTimeout)
+	defer cancel()
+
+	kvs, err := w.source.Load(ctx, w.config.Key, w.config.Prefix)
+	if err != nil {
+		return err
+	}
+
+	raw, err := decode(w.config.Format, w.config.Key, w.config.Prefix, kvs)
+	if err != nil {
+		return err
+	}
+
+	if err = w.apply(raw); err != nil {
+		return err
+	}
+
+	if w.config.SnapshotFile != "" {
+		if err = writeSnapshot(w.config.SnapshotFile, raw); err != nil {
+			goolog.WithField("key", w.config.Key).
+				WithField("error", err.Error()).
+				WarnF("[goo-config] write snapshot %s failed", w.config.SnapshotFile)
+		}
+	}
+
+	return nil
+}
+
+// loadSnapshot 从本地快照文件加载配置
+func (w *Watcher[T]) loadSnapshot() error {
+	raw, err := readSnapshot(w.config.SnapshotFile)
+	if err != nil {
+		return err
+	}
+	return w.apply(raw)
+}
+
+// apply 解码配置内容，内容变化时替换当前配置并触发回调
+// raw 为解密前的内容，快照文件中同样只保存密文
+func (w *Watcher[T]) apply(raw []byte) error {
+	w.applyMu.Lock()
+	defer w.applyMu.Unlock()
+
+	if w.raw != nil && bytes.Equal(w.raw, raw) {
+		return nil
+	}
+
+	plain, err := decryptJSON(w.config.Keyring, raw)
+	if err != nil {
+		return err
+	}
+
+	v := new(T)
+	if err = unmarshal(w.config.Format, plain, v); err != nil {
+		return err
+	}
+
+	old := w.value.Swap(v)
+	w.raw = raw
+
+	// 首次加载不触发回调
+	if old == nil {
+		return nil
+	}
+
+	w.mu.Lock()
+	handlers := append([]ChangeHandler[T](nil), w.handlers...)
+	w.mu.Unlock()
+
+	for _, fn := range handlers {
+		w.callHandler(fn, old, v)
+	}
+
+	return nil
+}
+
+func (w *Watcher[T]) callHandler(fn ChangeHandler[T], old, new *T) {
+	defer func() {
+		if r := recover(); r != nil {
+			goolog.WithField("key", w.config.Key).ErrorF("[goo-config] change handler panic: %v", r)
+		}
+	}()
+
+	fn(old, new)
+}
+
+// jitter 在退避时间上增加 ±20% 的随机抖动，避免多实例同时重连
+func jitter(d time.Duration) time.Duration {
+	if d <= 0 {
+		return d
+	}
+	delta := time.Duration(rand.Int63n(int64(d)/5*2+1)) - d/5
+	return d + delta
+}
+
+func errString(err error) string {
+	if err == nil {
+		return ""
+	}
+	return err.Error()
+}
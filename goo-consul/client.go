@@ -53,9 +53,6 @@ func NewClient(name string, config *Config) (*Client, error) {
 	}
 
 	// 测试连接
-	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
-	defer cancel()
-
 	_, err = client.Agent().Self()
 	if err != nil {
 		return nil, err
@@ -139,7 +136,7 @@ func (c *Client) Service(serviceID string, q *api.QueryOptions) (*api.AgentServi
 
 // Services 获取所有服务
 func (c *Client) Services(q *api.QueryOptions) (map[string]*api.AgentService, error) {
-	return c.agent.ServicesWithFilter("", q)
+	return c.agent.ServicesWithFilterOpts("", q)
 }
 
 // ServiceHealth 获取服务健康状态
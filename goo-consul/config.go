@@ -27,7 +27,16 @@ type Config struct {
 	TLSConfig *api.TLSConfig
 
 	// HTTP 客户端配置
-	HttpClient *api.HttpClientConfig
+	HttpClient *HttpClientConfig
+}
+
+// HttpClientConfig HTTP 客户端配置
+type HttpClientConfig struct {
+	// 请求超时时间
+	Timeout time.Duration
+
+	// TLS 配置，优先于 Config.TLSConfig
+	TLSConfig *api.TLSConfig
 }
 
 // DefaultConfig 返回默认配置
@@ -64,9 +73,7 @@ func (c *Config) toConsulConfig() (*api.Config, error) {
 		cfg.Namespace = c.Namespace
 	}
 
-	if c.Timeout > 0 {
-		cfg.HttpClient.Timeout = c.Timeout
-	}
+	timeout := c.Timeout
 
 	if c.TLSConfig != nil {
 		cfg.TLSConfig = *c.TLSConfig
@@ -74,11 +81,21 @@ func (c *Config) toConsulConfig() (*api.Config, error) {
 
 	if c.HttpClient != nil {
 		if c.HttpClient.Timeout > 0 {
-			cfg.HttpClient.Timeout = c.HttpClient.Timeout
+			timeout = c.HttpClient.Timeout
 		}
 		if c.HttpClient.TLSConfig != nil {
-			cfg.HttpClient.TLSConfig = *c.HttpClient.TLSConfig
+			cfg.TLSConfig = *c.HttpClient.TLSConfig
+		}
+	}
+
+	// 设置超时需要自行创建 HTTP 客户端（api.NewClient 只在 HttpClient 为空时根据 TLSConfig 创建）
+	if timeout > 0 {
+		httpClient, err := api.NewHttpClient(cfg.Transport, cfg.TLSConfig)
+		if err != nil {
+			return nil, err
 		}
+		httpClient.Timeout = timeout
+		cfg.HttpClient = httpClient
 	}
 
 	return cfg, nil
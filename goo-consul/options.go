@@ -55,7 +55,7 @@ func WithTLSConfig(tlsConfig *api.TLSConfig) FuncOption {
 }
 
 // WithHttpClient 设置 HTTP 客户端配置
-func WithHttpClient(httpClient *api.HttpClientConfig) FuncOption {
+func WithHttpClient(httpClient *HttpClientConfig) FuncOption {
 	return func(c *Config) {
 		c.HttpClient = httpClient
 	}
@@ -1,6 +1,7 @@
 package goocos
 
 import (
+	"fmt"
 	"time"
 
 	"github.com/tencentyun/cos-go-sdk-v5"
@@ -114,3 +115,35 @@ func (c *Config) getCredentials() (string, string, string) {
 	return c.SecretID, c.SecretKey, ""
 }
 
+// Format 打印配置时将 SecretKey 替换为 ******，对所有格式化动词生效
+func (c Config) Format(f fmt.State, verb rune) {
+	type plain Config
+	redacted := plain(c)
+	if redacted.SecretKey != "" {
+		redacted.SecretKey = "******"
+	}
+	fmt.Fprintf(f, fmt.FormatString(f, verb), redacted)
+}
+
+// String 实现 fmt.Stringer
+func (c Config) String() string {
+	return fmt.Sprint(c)
+}
+
+// Format 打印配置时将 SecretKey 和 SessionToken 替换为 ******，对所有格式化动词生效
+func (c STSConfig) Format(f fmt.State, verb rune) {
+	type plain STSConfig
+	redacted := plain(c)
+	if redacted.SecretKey != "" {
+		redacted.SecretKey = "******"
+	}
+	if redacted.SessionToken != "" {
+		redacted.SessionToken = "******"
+	}
+	fmt.Fprintf(f, fmt.FormatString(f, verb), redacted)
+}
+
+// String 实现 fmt.Stringer
+func (c STSConfig) String() string {
+	return fmt.Sprint(c)
+}
@@ -0,0 +1,64 @@
+package goocos
+
+import (
+	"context"
+	"io"
+
+	"github.com/tencentyun/cos-go-sdk-v5"
+)
+
+// MultipartUploader 分片上传适配器
+// 实现 goohttp.MultipartUploader，用于将断点续传的上传合并为 COS 对象：
+//
+//	store, _ := goohttp.NewMultipartUploadStore(goocos.NewMultipartUploader(client, nil), nil)
+type MultipartUploader struct {
+	client *Client
+	opt    *cos.InitiateMultipartUploadOptions
+}
+
+// NewMultipartUploader 创建分片上传适配器，opt 在初始化分片上传时使用（如 ACL、Content-Type），可以为 nil
+func NewMultipartUploader(client *Client, opt *cos.InitiateMultipartUploadOptions) *MultipartUploader {
+	return &MultipartUploader{
+		client: client,
+		opt:    opt,
+	}
+}
+
+// InitiateMultipart 初始化分片上传
+func (u *MultipartUploader) InitiateMultipart(ctx context.Context, key string) (string, error) {
+	result, _, err := u.client.InitiateMultipartUpload(ctx, key, u.opt)
+	if err != nil {
+		return "", err
+	}
+	return result.UploadID, nil
+}
+
+// UploadPart 上传分片
+func (u *MultipartUploader) UploadPart(ctx context.Context, key string, uploadId string, partNumber int, r io.Reader, size int64) (string, error) {
+	resp, err := u.client.UploadPart(ctx, key, uploadId, partNumber, r, &cos.ObjectUploadPartOptions{
+		ContentLength: size,
+	})
+	if err != nil {
+		return "", err
+	}
+	return resp.Header.Get("ETag"), nil
+}
+
+// CompleteMultipart 完成分片上传
+func (u *MultipartUploader) CompleteMultipart(ctx context.Context, key string, uploadId string, etags []string) error {
+	opt := &cos.CompleteMultipartUploadOptions{
+		Parts: make([]cos.Object, 0, len(etags)),
+	}
+	for i, etag := range etags {
+		opt.Parts = append(opt.Parts, cos.Object{PartNumber: i + 1, ETag: etag})
+	}
+
+	_, _, err := u.client.CompleteMultipartUpload(ctx, key, uploadId, opt)
+	return err
+}
+
+// AbortMultipart 取消分片上传
+func (u *MultipartUploader) AbortMultipart(ctx context.Context, key string, uploadId string) error {
+	_, err := u.client.AbortMultipartUpload(ctx, key, uploadId)
+	return err
+}
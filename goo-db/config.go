@@ -1,6 +1,7 @@
 package goodb
 
 import (
+	"fmt"
 	"time"
 )
 
@@ -52,3 +53,17 @@ func DefaultConfig(opts ...FuncOption) *Config {
 	return c
 }
 
+// Format 打印配置时将连接字符串（包含密码）替换为 ******，对所有格式化动词生效
+func (c Config) Format(f fmt.State, verb rune) {
+	type plain Config
+	redacted := plain(c)
+	if redacted.DSN != "" {
+		redacted.DSN = "******"
+	}
+	fmt.Fprintf(f, fmt.FormatString(f, verb), redacted)
+}
+
+// String 实现 fmt.Stringer
+func (c Config) String() string {
+	return fmt.Sprint(c)
+}
@@ -1,6 +1,7 @@
 package goodb
 
 import (
+	"sort"
 	"sync"
 
 	"github.com/go-xorm/xorm"
@@ -70,6 +71,20 @@ func SetDefault(name string) {
 	defaultName = name
 }
 
+// Names 获取所有已注册的客户端名称
+func Names() []string {
+	mu.RLock()
+	defer mu.RUnlock()
+
+	names := make([]string, 0, len(clients))
+	for name := range clients {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	return names
+}
+
 // Default 获取默认客户端
 func Default() (*Client, error) {
 	return GetClient(defaultName)
@@ -117,4 +132,3 @@ func Ping() error {
 	}
 	return client.Ping()
 }
-
@@ -7,6 +7,9 @@ import (
 	"time"
 
 	clientv3 "go.etcd.io/etcd/client/v3"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/encoding/gzip"
+	"google.golang.org/grpc/grpclog"
 )
 
 // Config etcd 配置
@@ -92,8 +95,14 @@ func (c *Config) toEtcdConfig() (clientv3.Config, error) {
 		AutoSyncInterval:   c.AutoSyncInterval,
 		MaxCallSendMsgSize: c.MaxCallSendMsgSize,
 		MaxCallRecvMsgSize: c.MaxCallRecvMsgSize,
-		EnableCompression:  c.EnableCompression,
-		EnableGRPCDebugLog: c.EnableGRPCDebugLog,
+	}
+
+	// 压缩和 gRPC 调试日志通过 gRPC 选项设置
+	if c.EnableCompression {
+		cfg.DialOptions = append(cfg.DialOptions, grpc.WithDefaultCallOptions(grpc.UseCompressor(gzip.Name)))
+	}
+	if c.EnableGRPCDebugLog {
+		grpclog.SetLoggerV2(grpclog.NewLoggerV2WithVerbosity(os.Stderr, os.Stderr, os.Stderr, 4))
 	}
 
 	// 设置默认值
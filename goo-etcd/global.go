@@ -2,6 +2,7 @@ package gooetcd
 
 import (
 	"context"
+	"sort"
 	"sync"
 
 	clientv3 "go.etcd.io/etcd/client/v3"
@@ -71,6 +72,20 @@ func SetDefault(name string) {
 	defaultName = name
 }
 
+// Names 获取所有已注册的客户端名称
+func Names() []string {
+	mu.RLock()
+	defer mu.RUnlock()
+
+	names := make([]string, 0, len(clients))
+	for name := range clients {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	return names
+}
+
 // Default 获取默认客户端
 func Default() (*Client, error) {
 	return GetClient(defaultName)
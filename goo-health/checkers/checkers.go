@@ -0,0 +1,53 @@
+package checkers
+
+import (
+	"errors"
+	"sync"
+
+	goohealth "v2.googo.io/goo-health"
+)
+
+// clientRegistry goo 客户端注册表
+type clientRegistry struct {
+	kind    string
+	names   func() []string
+	checker func(name string) goohealth.Checker
+}
+
+var (
+	registries []clientRegistry
+	mu         sync.RWMutex
+)
+
+// Add 添加一种客户端，由各客户端的检查子包在 init 中调用
+// names 返回已注册的客户端名称，checker 按名称创建检查项
+func Add(kind string, names func() []string, checker func(name string) goohealth.Checker) {
+	mu.Lock()
+	defer mu.Unlock()
+
+	registries = append(registries, clientRegistry{kind: kind, names: names, checker: checker})
+}
+
+// RegisterAll 将已导入的检查子包对应的客户端全部注册为检查项，名称为 "类型.客户端名"，如 "redis.default"
+// 应在客户端注册完成后调用，已存在的检查项会跳过
+func RegisterAll(registry *goohealth.Registry, opts ...goohealth.CheckOption) error {
+	if registry == nil {
+		registry = goohealth.Default()
+	}
+
+	mu.RLock()
+	defer mu.RUnlock()
+
+	var errs []error
+
+	for _, r := range registries {
+		for _, name := range r.names() {
+			err := registry.Register(r.kind+"."+name, r.checker(name), opts...)
+			if err != nil && !errors.Is(err, goohealth.ErrDuplicateCheck) {
+				errs = append(errs, err)
+			}
+		}
+	}
+
+	return errors.Join(errs...)
+}
@@ -0,0 +1,25 @@
+package consulchecker
+
+import (
+	"context"
+
+	gooconsul "v2.googo.io/goo-consul"
+	goohealth "v2.googo.io/goo-health"
+	"v2.googo.io/goo-health/checkers"
+)
+
+// 导入即加入 checkers.RegisterAll，检查项名称为 "consul.客户端名"
+func init() {
+	checkers.Add("consul", gooconsul.Names, Checker)
+}
+
+// Checker goo-consul 客户端检查（每次检查时按名称获取客户端，客户端重新注册后仍然有效）
+func Checker(name string) goohealth.Checker {
+	return goohealth.CheckerFunc(func(ctx context.Context) error {
+		client, err := gooconsul.GetClient(name)
+		if err != nil {
+			return err
+		}
+		return client.Ping(ctx)
+	})
+}
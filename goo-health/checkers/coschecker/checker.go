@@ -0,0 +1,25 @@
+package coschecker
+
+import (
+	"context"
+
+	goocos "v2.googo.io/goo-cos"
+	goohealth "v2.googo.io/goo-health"
+	"v2.googo.io/goo-health/checkers"
+)
+
+// 导入即加入 checkers.RegisterAll，检查项名称为 "cos.客户端名"
+func init() {
+	checkers.Add("cos", goocos.Names, Checker)
+}
+
+// Checker goo-cos 客户端检查（每次检查时按名称获取客户端，客户端重新注册后仍然有效）
+func Checker(name string) goohealth.Checker {
+	return goohealth.CheckerFunc(func(ctx context.Context) error {
+		client, err := goocos.GetClient(name)
+		if err != nil {
+			return err
+		}
+		return client.Ping(ctx)
+	})
+}
@@ -0,0 +1,26 @@
+package dbchecker
+
+import (
+	"context"
+
+	goodb "v2.googo.io/goo-db"
+	goohealth "v2.googo.io/goo-health"
+	"v2.googo.io/goo-health/checkers"
+)
+
+// 导入即加入 checkers.RegisterAll，检查项名称为 "db.客户端名"
+func init() {
+	checkers.Add("db", goodb.Names, Checker)
+}
+
+// Checker goo-db 客户端检查（每次检查时按名称获取客户端，客户端重新注册后仍然有效）
+func Checker(name string) goohealth.Checker {
+	return goohealth.CheckerFunc(func(ctx context.Context) error {
+		client, err := goodb.GetClient(name)
+		if err != nil {
+			return err
+		}
+		// goo-db 的 Ping 不支持 ctx，超时由注册表控制
+		return client.Ping()
+	})
+}
@@ -0,0 +1,25 @@
+package etcdchecker
+
+import (
+	"context"
+
+	gooetcd "v2.googo.io/goo-etcd"
+	goohealth "v2.googo.io/goo-health"
+	"v2.googo.io/goo-health/checkers"
+)
+
+// 导入即加入 checkers.RegisterAll，检查项名称为 "etcd.客户端名"
+func init() {
+	checkers.Add("etcd", gooetcd.Names, Checker)
+}
+
+// Checker goo-etcd 客户端检查（每次检查时按名称获取客户端，客户端重新注册后仍然有效）
+func Checker(name string) goohealth.Checker {
+	return goohealth.CheckerFunc(func(ctx context.Context) error {
+		client, err := gooetcd.GetClient(name)
+		if err != nil {
+			return err
+		}
+		return client.Ping(ctx)
+	})
+}
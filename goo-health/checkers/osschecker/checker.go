@@ -0,0 +1,25 @@
+package osschecker
+
+import (
+	"context"
+
+	goohealth "v2.googo.io/goo-health"
+	"v2.googo.io/goo-health/checkers"
+	goooss "v2.googo.io/goo-oss"
+)
+
+// 导入即加入 checkers.RegisterAll，检查项名称为 "oss.客户端名"
+func init() {
+	checkers.Add("oss", goooss.Names, Checker)
+}
+
+// Checker goo-oss 客户端检查（每次检查时按名称获取客户端，客户端重新注册后仍然有效）
+func Checker(name string) goohealth.Checker {
+	return goohealth.CheckerFunc(func(ctx context.Context) error {
+		client, err := goooss.GetClient(name)
+		if err != nil {
+			return err
+		}
+		return client.Ping(ctx)
+	})
+}
@@ -0,0 +1,16 @@
+package goohealth
+
+import "errors"
+
+var (
+	// ErrEmptyName 检查项名称为空
+	ErrEmptyName = errors.New("检查项名称不能为空")
+	// ErrNilChecker 检查函数为空
+	ErrNilChecker = errors.New("检查函数不能为空")
+	// ErrDuplicateCheck 检查项重复注册
+	ErrDuplicateCheck = errors.New("检查项已存在")
+	// ErrCheckTimeout 检查超时
+	ErrCheckTimeout = errors.New("检查超时")
+	// ErrDraining 服务正在下线
+	ErrDraining = errors.New("服务正在下线")
+)
@@ -0,0 +1,47 @@
+package goohealth
+
+import "context"
+
+var (
+	defaultRegistry = NewRegistry()
+)
+
+// Default 获取默认注册表
+func Default() *Registry {
+	return defaultRegistry
+}
+
+// Register 向默认注册表注册检查项
+func Register(name string, checker Checker, opts ...CheckOption) error {
+	return defaultRegistry.Register(name, checker, opts...)
+}
+
+// RegisterFunc 向默认注册表注册函数形式的检查项
+func RegisterFunc(name string, fn func(ctx context.Context) error, opts ...CheckOption) error {
+	return defaultRegistry.RegisterFunc(name, fn, opts...)
+}
+
+// Unregister 从默认注册表注销检查项
+func Unregister(name string) {
+	defaultRegistry.Unregister(name)
+}
+
+// SetDraining 设置默认注册表的下线状态
+func SetDraining(draining bool) {
+	defaultRegistry.SetDraining(draining)
+}
+
+// Check 执行默认注册表的所有检查项
+func Check(ctx context.Context) *Report {
+	return defaultRegistry.Check(ctx)
+}
+
+// Ready 默认注册表的就绪检查
+func Ready(ctx context.Context) *Report {
+	return defaultRegistry.Ready(ctx)
+}
+
+// Live 默认注册表的存活检查
+func Live(ctx context.Context) *Report {
+	return defaultRegistry.Live(ctx)
+}
@@ -0,0 +1,294 @@
+package goohealth
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// Status 检查状态
+type Status string
+
+const (
+	StatusUp       Status = "up"       // 正常
+	StatusDown     Status = "down"     // 异常
+	StatusDegraded Status = "degraded" // 非关键检查项异常，服务仍可用
+)
+
+var (
+	// DefaultCheckTimeout 默认检查超时时间
+	DefaultCheckTimeout = 3 * time.Second
+)
+
+// Checker 健康检查
+type Checker interface {
+	Check(ctx context.Context) error
+}
+
+// CheckerFunc 函数形式的健康检查
+type CheckerFunc func(ctx context.Context) error
+
+func (f CheckerFunc) Check(ctx context.Context) error {
+	return f(ctx)
+}
+
+// CheckConfig 检查项配置
+type CheckConfig struct {
+	Name     string        // 名称
+	Checker  Checker       // 检查函数
+	Timeout  time.Duration // 超时时间（默认 3 秒）
+	Critical bool          // 是否为关键检查项（默认 true）
+	CacheTTL time.Duration // 结果缓存时间（默认不缓存）
+	Liveness bool          // 是否为存活检查项
+}
+
+// CheckResult 单项检查结果
+type CheckResult struct {
+	Status    Status        `json:"status"`
+	Critical  bool          `json:"critical"`
+	Latency   time.Duration `json:"-"`
+	LatencyMs float64       `json:"latency_ms"`
+	Error     string        `json:"error,omitempty"`
+	Cached    bool          `json:"cached,omitempty"`
+	CheckedAt time.Time     `json:"checked_at"`
+}
+
+// Report 检查报告
+type Report struct {
+	Status   Status                  `json:"status"`
+	Draining bool                    `json:"draining,omitempty"`
+	Checks   map[string]*CheckResult `json:"checks,omitempty"`
+}
+
+// Healthy 是否可以对外服务（degraded 也视为可用）
+func (r *Report) Healthy() bool {
+	return r.Status != StatusDown
+}
+
+type entry struct {
+	check CheckConfig
+	last  *CheckResult
+	mu    sync.Mutex
+}
+
+// Registry 健康检查注册表
+type Registry struct {
+	entries  map[string]*entry
+	draining atomic.Bool
+	mu       sync.RWMutex
+}
+
+func NewRegistry() *Registry {
+	return &Registry{
+		entries: make(map[string]*entry),
+	}
+}
+
+// Register 注册检查项，名称重复时返回 ErrDuplicateCheck
+func (r *Registry) Register(name string, checker Checker, opts ...CheckOption) error {
+	if name == "" {
+		return ErrEmptyName
+	}
+	if checker == nil {
+		return ErrNilChecker
+	}
+
+	check := CheckConfig{
+		Name:     name,
+		Checker:  checker,
+		Timeout:  DefaultCheckTimeout,
+		Critical: true,
+	}
+	for _, opt := range opts {
+		opt.Apply(&check)
+	}
+	if check.Timeout <= 0 {
+		check.Timeout = DefaultCheckTimeout
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, exists := r.entries[name]; exists {
+		return fmt.Errorf("%w: %s", ErrDuplicateCheck, name)
+	}
+
+	r.entries[name] = &entry{check: check}
+	return nil
+}
+
+// RegisterFunc 注册函数形式的检查项
+func (r *Registry) RegisterFunc(name string, fn func(ctx context.Context) error, opts ...CheckOption) error {
+	if fn == nil {
+		return ErrNilChecker
+	}
+	return r.Register(name, CheckerFunc(fn), opts...)
+}
+
+// Unregister 注销检查项
+func (r *Registry) Unregister(name string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	delete(r.entries, name)
+}
+
+// Names 获取所有检查项名称
+func (r *Registry) Names() []string {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	names := make([]string, 0, len(r.entries))
+	for name := range r.entries {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	return names
+}
+
+// SetDraining 设置下线状态，下线期间就绪检查返回未就绪，负载均衡会摘除流量
+func (r *Registry) SetDraining(draining bool) {
+	r.draining.Store(draining)
+}
+
+// Draining 是否正在下线
+func (r *Registry) Draining() bool {
+	return r.draining.Load()
+}
+
+// Check 执行所有检查项
+func (r *Registry) Check(ctx context.Context) *Report {
+	return r.run(ctx, func(e *entry) bool {
+		return true
+	})
+}
+
+// Ready 就绪检查：下线期间直接返回未就绪，否则执行所有非存活检查项
+func (r *Registry) Ready(ctx context.Context) *Report {
+	if r.Draining() {
+		return &Report{
+			Status:   StatusDown,
+			Draining: true,
+		}
+	}
+
+	return r.run(ctx, func(e *entry) bool {
+		return !e.check.Liveness
+	})
+}
+
+// Live 存活检查：只执行存活检查项，没有存活检查项时返回正常
+func (r *Registry) Live(ctx context.Context) *Report {
+	return r.run(ctx, func(e *entry) bool {
+		return e.check.Liveness
+	})
+}
+
+func (r *Registry) run(ctx context.Context, filter func(e *entry) bool) *Report {
+	r.mu.RLock()
+	entries := make([]*entry, 0, len(r.entries))
+	for _, e := range r.entries {
+		if filter(e) {
+			entries = append(entries, e)
+		}
+	}
+	r.mu.RUnlock()
+
+	report := &Report{
+		Status:   StatusUp,
+		Draining: r.Draining(),
+		Checks:   make(map[string]*CheckResult, len(entries)),
+	}
+
+	results := make([]*CheckResult, len(entries))
+
+	var wg sync.WaitGroup
+	for i, e := range entries {
+		wg.Add(1)
+		go func(i int, e *entry) {
+			defer wg.Done()
+			results[i] = e.run(ctx)
+		}(i, e)
+	}
+	wg.Wait()
+
+	for i, e := range entries {
+		result := results[i]
+		report.Checks[e.check.Name] = result
+
+		if result.Status != StatusDown {
+			continue
+		}
+		if result.Critical {
+			report.Status = StatusDown
+		} else if report.Status == StatusUp {
+			report.Status = StatusDegraded
+		}
+	}
+
+	return report
+}
+
+func (e *entry) run(ctx context.Context) *CheckResult {
+	e.mu.Lock()
+	if e.last != nil && e.check.CacheTTL > 0 && time.Since(e.last.CheckedAt) < e.check.CacheTTL {
+		cached := *e.last
+		cached.Cached = true
+		e.mu.Unlock()
+		return &cached
+	}
+	e.mu.Unlock()
+
+	start := time.Now()
+	err := runChecker(ctx, e.check.Checker, e.check.Timeout)
+	latency := time.Since(start)
+
+	result := &CheckResult{
+		Status:    StatusUp,
+		Critical:  e.check.Critical,
+		Latency:   latency,
+		LatencyMs: float64(latency.Microseconds()) / 1000,
+		CheckedAt: start,
+	}
+	if err != nil {
+		result.Status = StatusDown
+		result.Error = err.Error()
+	}
+
+	e.mu.Lock()
+	e.last = result
+	e.mu.Unlock()
+
+	copied := *result
+	return &copied
+}
+
+// runChecker 执行检查，检查函数不响应 ctx 时也能按时返回
+func runChecker(ctx context.Context, checker Checker, timeout time.Duration) error {
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	done := make(chan error, 1)
+
+	go func() {
+		defer func() {
+			if r := recover(); r != nil {
+				done <- fmt.Errorf("panic: %v", r)
+			}
+		}()
+		done <- checker.Check(ctx)
+	}()
+
+	select {
+	case err := <-done:
+		return err
+	case <-ctx.Done():
+		if ctx.Err() == context.DeadlineExceeded {
+			return ErrCheckTimeout
+		}
+		return ctx.Err()
+	}
+}
@@ -0,0 +1,38 @@
+package goohealth
+
+import "time"
+
+// CheckOption 检查项配置选项
+type CheckOption func(c *CheckConfig)
+
+func (o CheckOption) Apply(c *CheckConfig) {
+	o(c)
+}
+
+// WithTimeout 设置检查超时时间
+func WithTimeout(timeout time.Duration) CheckOption {
+	return func(c *CheckConfig) {
+		c.Timeout = timeout
+	}
+}
+
+// WithCritical 设置是否为关键检查项（关键检查项失败时服务未就绪）
+func WithCritical(critical bool) CheckOption {
+	return func(c *CheckConfig) {
+		c.Critical = critical
+	}
+}
+
+// WithCacheTTL 设置检查结果缓存时间（避免探针频繁访问下游）
+func WithCacheTTL(ttl time.Duration) CheckOption {
+	return func(c *CheckConfig) {
+		c.CacheTTL = ttl
+	}
+}
+
+// WithLiveness 设置为存活检查项（存活检查失败时容器会被重启，只用于检查进程自身）
+func WithLiveness(liveness bool) CheckOption {
+	return func(c *CheckConfig) {
+		c.Liveness = liveness
+	}
+}
@@ -0,0 +1,378 @@
+package goohttp
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"math/rand/v2"
+	"mime"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	goolog "v2.googo.io/goo-log"
+)
+
+var (
+	DefaultAccessLogConfig = &AccessLogConfig{
+		MaxRequestBody:   4 << 10,
+		MaxResponseBody:  4 << 10,
+		SlowThreshold:    time.Second,
+		SampleRate:       1,
+		SensitiveHeaders: DefaultRecoveryConfig.SensitiveHeaders,
+		SensitiveQuery:   DefaultRecoveryConfig.SensitiveQuery,
+		SensitiveFields:  []string{"password", "passwd", "secret", "token", "access_token", "refresh_token", "id_card", "card_no", "cvv"},
+	}
+)
+
+type AccessLogConfig struct {
+	Logger           *goolog.Logger                    // 日志对象（默认 goolog.Default()）
+	RequestBody      bool                              // 是否记录请求体（仅 JSON、表单、文本、XML）
+	ResponseBody     bool                              // 是否记录响应体（仅 JSON、文本、XML，流式输出不记录）
+	MaxRequestBody   int                               // 请求体最多记录的字节数（默认 4KB），超出部分截断
+	MaxResponseBody  int                               // 响应体最多记录的字节数（默认 4KB），超出部分截断
+	SlowThreshold    time.Duration                     // 慢请求阈值（默认 1 秒），超过时以 WARN 级别记录，小于 0 时不判断
+	SkipRoutes       []string                          // 不记录的路由，如 "GET /ping"、"/ping" 或 "/static/*"（前缀匹配）
+	SampleRate       float64                           // 采样率，0~1（默认 1，全部记录）
+	SampleRoutes     map[string]float64                // 按路由设置采样率，0 表示只记录错误和慢请求
+	Headers          []string                          // 额外记录的请求头（如 "X-AppId"），敏感请求头会脱敏
+	SensitiveHeaders []string                          // 脱敏的请求头
+	SensitiveQuery   []string                          // 脱敏的查询参数
+	SensitiveFields  []string                          // 请求体、响应体中脱敏的字段（JSON 字段、表单参数，不区分大小写）
+	Fields           func(ctx *Context) map[string]any // 自定义字段（如用户ID、租户ID）
+}
+
+// AccessLog 访问日志
+type AccessLog struct {
+	config           *AccessLogConfig
+	logger           *goolog.Logger
+	skipRoutes       map[string]bool
+	sensitiveHeaders map[string]bool
+	sensitiveQuery   map[string]bool
+	sensitiveFields  map[string]bool
+	fieldPattern     *regexp.Regexp // 截断的 JSON 无法解析时，按正则脱敏
+}
+
+func NewAccessLog(config *AccessLogConfig) *AccessLog {
+	if config == nil {
+		config = DefaultAccessLogConfig
+	}
+
+	c := *config
+	if c.MaxRequestBody <= 0 {
+		c.MaxRequestBody = DefaultAccessLogConfig.MaxRequestBody
+	}
+	if c.MaxResponseBody <= 0 {
+		c.MaxResponseBody = DefaultAccessLogConfig.MaxResponseBody
+	}
+	if c.SlowThreshold == 0 {
+		c.SlowThreshold = DefaultAccessLogConfig.SlowThreshold
+	}
+	if c.SampleRate <= 0 || c.SampleRate > 1 {
+		c.SampleRate = DefaultAccessLogConfig.SampleRate
+	}
+	if c.SensitiveHeaders == nil {
+		c.SensitiveHeaders = DefaultAccessLogConfig.SensitiveHeaders
+	}
+	if c.SensitiveQuery == nil {
+		c.SensitiveQuery = DefaultAccessLogConfig.SensitiveQuery
+	}
+	if c.SensitiveFields == nil {
+		c.SensitiveFields = DefaultAccessLogConfig.SensitiveFields
+	}
+
+	a := &AccessLog{
+		config:           &c,
+		logger:           c.Logger,
+		skipRoutes:       routeSet(c.SkipRoutes),
+		sensitiveHeaders: make(map[string]bool, len(c.SensitiveHeaders)),
+		sensitiveQuery:   make(map[string]bool, len(c.SensitiveQuery)),
+		sensitiveFields:  make(map[string]bool, len(c.SensitiveFields)),
+	}
+	if a.logger == nil {
+		a.logger = goolog.Default()
+	}
+
+	for _, name := range c.SensitiveHeaders {
+		a.sensitiveHeaders[http.CanonicalHeaderKey(name)] = true
+	}
+	for _, name := range c.SensitiveQuery {
+		a.sensitiveQuery[strings.ToLower(name)] = true
+	}
+	if len(c.SensitiveFields) > 0 {
+		names := make([]string, 0, len(c.SensitiveFields))
+		for _, name := range c.SensitiveFields {
+			a.sensitiveFields[strings.ToLower(name)] = true
+			names = append(names, regexp.QuoteMeta(name))
+		}
+		a.fieldPattern = regexp.MustCompile(`(?i)"(` + strings.Join(names, "|") + `)"\s*:\s*("(?:[^"\\]|\\.)*"?|[^,}\]\s]+)`)
+	}
+
+	return a
+}
+
+// skip 是否不记录
+func (a *AccessLog) skip(ctx *Context) bool {
+	_, ok := lookupRoute(a.skipRoutes, ctx)
+	return ok
+}
+
+// sampled 是否采样，错误和慢请求始终记录
+func (a *AccessLog) sampled(ctx *Context) bool {
+	rate := a.config.SampleRate
+	if v, ok := lookupRoute(a.config.SampleRoutes, ctx); ok {
+		rate = v
+	}
+	return rate >= 1 || rand.Float64() < rate
+}
+
+// Middleware 访问日志中间件
+func (a *AccessLog) Middleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		ctx := &Context{Context: c}
+		if a.skip(ctx) {
+			c.Next()
+			return
+		}
+
+		start := time.Now()
+
+		var reqBody []byte
+		var reqTruncated bool
+		if a.config.RequestBody && c.Request.Body != nil && c.Request.Body != http.NoBody && isTextBody(c.Request.Header.Get("Content-Type"), true) {
+			reqBody, reqTruncated = a.captureRequestBody(c.Request)
+		}
+
+		var writer *accessLogResponseWriter
+		if a.config.ResponseBody {
+			writer = &accessLogResponseWriter{
+				ResponseWriter: c.Writer,
+				limit:          a.config.MaxResponseBody,
+			}
+			c.Writer = writer
+		}
+
+		c.Next()
+
+		if writer != nil {
+			c.Writer = writer.ResponseWriter
+		}
+
+		latency := time.Since(start)
+		status := c.Writer.Status()
+		slow := a.config.SlowThreshold > 0 && latency >= a.config.SlowThreshold
+
+		if status < http.StatusInternalServerError && !slow && !a.sampled(ctx) {
+			return
+		}
+
+		entry := a.logger.WithField("trace-id", ctx.TraceId()).
+			WithField("method", c.Request.Method).
+			WithField("route", c.FullPath()).
+			WithField("path", sanitizeURL(c.Request.URL, a.sensitiveQuery)).
+			WithField("status", status).
+			WithField("latency", float64(latency.Microseconds())/1000).
+			WithField("ip", ctx.ClientIP()).
+			WithField("user-agent", c.Request.UserAgent()).
+			WithField("request-size", max(c.Request.ContentLength, 0)).
+			WithField("response-size", max(c.Writer.Size(), 0))
+
+		if code, ok := ctx.ResponseCode(); ok {
+			entry = entry.WithField("code", code)
+		}
+
+		for _, name := range a.config.Headers {
+			value := c.Request.Header.Get(name)
+			if value == "" {
+				continue
+			}
+			if a.sensitiveHeaders[http.CanonicalHeaderKey(name)] {
+				value = "***"
+			}
+			entry = entry.WithField(strings.ToLower(name), value)
+		}
+
+		if reqBody != nil {
+			entry = entry.WithField("request-body", a.redactBody(c.Request.Header.Get("Content-Type"), reqBody, reqTruncated))
+		}
+		if writer != nil && writer.buffer.Len() > 0 && !isStreaming(c) && isTextBody(c.Writer.Header().Get("Content-Type"), false) {
+			entry = entry.WithField("response-body", a.redactBody(c.Writer.Header().Get("Content-Type"), writer.buffer.Bytes(), writer.truncated))
+		}
+
+		if len(c.Errors) > 0 {
+			entry = entry.WithField("error", c.Errors.String())
+		}
+
+		if a.config.Fields != nil {
+			for key, value := range a.config.Fields(ctx) {
+				entry = entry.WithField(key, value)
+			}
+		}
+
+		msg := "[goo-http] " + c.Request.Method + " " + c.Request.URL.Path
+		switch {
+		case status >= http.StatusInternalServerError:
+			entry.Error(msg)
+		case slow:
+			entry.Warn(msg + " (slow)")
+		case status >= http.StatusBadRequest:
+			entry.Warn(msg)
+		default:
+			entry.Info(msg)
+		}
+	}
+}
+
+// captureRequestBody 读取请求体的前 MaxRequestBody 字节，并还原请求体供后续处理
+func (a *AccessLog) captureRequestBody(req *http.Request) ([]byte, bool) {
+	limit := a.config.MaxRequestBody
+
+	data, err := io.ReadAll(io.LimitReader(req.Body, int64(limit)+1))
+	req.Body = &readCloser{
+		Reader: io.MultiReader(bytes.NewReader(data), req.Body),
+		Closer: req.Body,
+	}
+	if err != nil {
+		return nil, false
+	}
+
+	if len(data) > limit {
+		return data[:limit], true
+	}
+	return data, false
+}
+
+// redactBody 脱敏，截断的内容追加 "...(truncated)"
+func (a *AccessLog) redactBody(contentType string, data []byte, truncated bool) string {
+	mediaType, _, _ := mime.ParseMediaType(contentType)
+
+	var body string
+	switch {
+	case len(a.sensitiveFields) == 0:
+		body = string(data)
+	case strings.HasSuffix(mediaType, "json"):
+		body = a.redactJSON(data, truncated)
+	case mediaType == "application/x-www-form-urlencoded":
+		body = a.redactForm(data)
+	default:
+		body = string(data)
+	}
+
+	if truncated {
+		body += "...(truncated)"
+	}
+	return body
+}
+
+func (a *AccessLog) redactJSON(data []byte, truncated bool) string {
+	if !truncated {
+		decoder := json.NewDecoder(bytes.NewReader(data))
+		decoder.UseNumber()
+
+		var v any
+		if err := decoder.Decode(&v); err == nil {
+			if redacted, err := json.Marshal(a.redactValue(v)); err == nil {
+				return string(redacted)
+			}
+		}
+	}
+
+	return a.fieldPattern.ReplaceAllString(string(data), `"$1":"***"`)
+}
+
+func (a *AccessLog) redactValue(v any) any {
+	switch value := v.(type) {
+	case map[string]any:
+		for key, item := range value {
+			if a.sensitiveFields[strings.ToLower(key)] {
+				value[key] = "***"
+				continue
+			}
+			value[key] = a.redactValue(item)
+		}
+	case []any:
+		for i, item := range value {
+			value[i] = a.redactValue(item)
+		}
+	}
+	return v
+}
+
+func (a *AccessLog) redactForm(data []byte) string {
+	values, err := url.ParseQuery(string(data))
+	if err != nil {
+		return string(data)
+	}
+
+	for name := range values {
+		if a.sensitiveFields[strings.ToLower(name)] {
+			values[name] = []string{"***"}
+		}
+	}
+	return values.Encode()
+}
+
+// AccessLogMiddleware 访问日志中间件，每个请求通过 goolog 输出一条记录
+func AccessLogMiddleware(config *AccessLogConfig) gin.HandlerFunc {
+	return NewAccessLog(config).Middleware()
+}
+
+// isTextBody 是否为可记录的文本内容，request 为 true 时包含表单
+func isTextBody(contentType string, request bool) bool {
+	if contentType == "" {
+		return false
+	}
+
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return false
+	}
+
+	switch {
+	case strings.HasPrefix(mediaType, "text/") && mediaType != "text/event-stream":
+		return true
+	case strings.HasSuffix(mediaType, "json"), strings.HasSuffix(mediaType, "xml"):
+		return true
+	case request && mediaType == "application/x-www-form-urlencoded":
+		return true
+	}
+	return false
+}
+
+type readCloser struct {
+	io.Reader
+	io.Closer
+}
+
+// 访问日志响应写入器，记录响应体的前 limit 字节
+type accessLogResponseWriter struct {
+	gin.ResponseWriter
+	buffer    bytes.Buffer
+	limit     int
+	truncated bool
+}
+
+func (w *accessLogResponseWriter) Write(data []byte) (int, error) {
+	w.capture(data)
+	return w.ResponseWriter.Write(data)
+}
+
+func (w *accessLogResponseWriter) WriteString(s string) (int, error) {
+	if w.buffer.Len() < w.limit {
+		w.capture([]byte(s))
+	} else if len(s) > 0 {
+		w.truncated = true
+	}
+	return w.ResponseWriter.WriteString(s)
+}
+
+func (w *accessLogResponseWriter) capture(data []byte) {
+	if remain := w.limit - w.buffer.Len(); remain < len(data) {
+		w.truncated = true
+		data = data[:max(remain, 0)]
+	}
+	w.buffer.Write(data)
+}
@@ -0,0 +1,441 @@
+package goohttp
+
+import (
+	"crypto/subtle"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/pprof"
+	"reflect"
+	"runtime"
+	"runtime/debug"
+	rpprof "runtime/pprof"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	goolog "v2.googo.io/goo-log"
+)
+
+// 管理接口错误码
+const (
+	AdminCodeUnauthorized = 4013 // 缺少或错误的管理令牌
+	AdminCodeForbidden    = 4031 // IP 不允许访问管理接口
+	AdminCodeInvalidLevel = 4008 // 日志级别错误
+)
+
+var (
+	ErrAdminAuthRequired = errors.New("管理接口挂载到主服务时必须配置 Token 或 AllowIPs")
+)
+
+var (
+	DefaultAdminConfig = &AdminConfig{
+		Path:        "/debug",
+		TokenHeader: "X-Admin-Token",
+		SensitiveKeys: []string{
+			"password", "passwd", "secret", "token", "credential", "credentials",
+			"private_key", "api_key", "access_key", "secret_key",
+		},
+	}
+)
+
+type AdminConfig struct {
+	Addr          string         `yaml:"addr" json:"addr"`                     // 独立监听地址（如 127.0.0.1:6060），为空时挂载到主服务的 Path 下
+	Path          string         `yaml:"path" json:"path"`                     // 路径前缀（默认 /debug）
+	Token         string         `yaml:"token" json:"-"`                       // 访问令牌，通过 Authorization: Bearer 或 TokenHeader 请求头传递
+	TokenHeader   string         `yaml:"token_header" json:"token_header"`     // 令牌请求头（默认 X-Admin-Token）
+	AllowIPs      []string       `yaml:"allow_ips" json:"allow_ips"`           // 允许访问的 IP 或 CIDR；使用独立端口且 Token 和 AllowIPs 都为空时只允许本机访问
+	SensitiveKeys []string       `yaml:"sensitive_keys" json:"sensitive_keys"` // 配置中需要脱敏的字段名（等于或以其结尾即脱敏，忽略大小写、下划线和连字符）
+	Logger        *goolog.Logger `yaml:"-" json:"-"`                           // 运行时调整级别的日志对象（默认 goolog.Default()）
+}
+
+// Admin 管理接口：pprof、goroutine 堆栈、构建信息、生效配置、路由列表、日志级别
+// 需要令牌或 IP 白名单，使用独立端口且未配置时只允许本机访问
+type Admin struct {
+	server    *Server
+	config    *AdminConfig
+	engine    *gin.Engine // 独立端口的路由，挂载到主服务时为空
+	filter    *IPFilter
+	startTime time.Time
+	levelMu   sync.Mutex
+	restore   *time.Timer  // 临时调整日志级别后恢复
+	original  goolog.Level // 临时调整前的日志级别，restore 不为空时有效
+	traceOrig goolog.Level // 临时调整前的链路日志级别
+}
+
+func newAdmin(server *Server, config *AdminConfig) *Admin {
+	if config == nil {
+		config = DefaultAdminConfig
+	}
+
+	c := *config
+	if c.Path == "" {
+		c.Path = DefaultAdminConfig.Path
+	}
+	c.Path = "/" + strings.Trim(c.Path, "/")
+	if c.TokenHeader == "" {
+		c.TokenHeader = DefaultAdminConfig.TokenHeader
+	}
+	if c.SensitiveKeys == nil {
+		c.SensitiveKeys = DefaultAdminConfig.SensitiveKeys
+	}
+	if c.Logger == nil {
+		c.Logger = goolog.Default()
+	}
+
+	// 挂载到主服务时，同机反向代理转发的请求来源都是本机，不能只靠本机限制
+	if c.Addr == "" && c.Token == "" && len(c.AllowIPs) == 0 {
+		panic(ErrAdminAuthRequired)
+	}
+
+	allow := c.AllowIPs
+	if c.Token == "" && len(allow) == 0 {
+		allow = []string{"127.0.0.1", "::1"}
+	}
+	filter, err := NewIPFilter(&IPFilterConfig{Allow: allow})
+	if err != nil {
+		panic(err)
+	}
+
+	return &Admin{
+		server:    server,
+		config:    &c,
+		filter:    filter,
+		startTime: time.Now(),
+	}
+}
+
+// setupAdmin 注册管理接口
+// 未配置独立端口时在中间件之前注册，不经过日志、限流、认证、加解密等中间件
+func (s *Server) setupAdmin() {
+	s.admin = newAdmin(s, s.config.AdminConfig)
+
+	engine := s.engine
+	if s.admin.config.Addr != "" {
+		engine = gin.New()
+		engine.RemoteIPHeaders = s.engine.RemoteIPHeaders
+		if err := engine.SetTrustedProxies(s.config.TrustedProxies); err != nil {
+			panic(fmt.Errorf("%w: %v", ErrInvalidCIDR, err))
+		}
+		engine.Use(RecoveryMiddleware(s.config.RecoveryConfig))
+		s.admin.engine = engine
+	}
+
+	s.admin.register(engine.Group(s.admin.config.Path))
+}
+
+func (a *Admin) register(group *gin.RouterGroup) {
+	group.Use(a.auth)
+
+	group.GET("/pprof/*name", a.pprof)
+	group.POST("/pprof/symbol", gin.WrapF(pprof.Symbol))
+	group.GET("/goroutines", a.goroutines)
+	group.GET("/info", a.info)
+	group.GET("/config", a.effectiveConfig)
+	group.GET("/routes", a.routes)
+	group.GET("/log/level", a.logLevel)
+	group.PUT("/log/level", a.setLogLevel)
+}
+
+// auth 校验 IP 白名单和令牌
+func (a *Admin) auth(c *gin.Context) {
+	ctx := &Context{Context: c}
+
+	if len(a.config.AllowIPs) > 0 || a.config.Token == "" {
+		if !a.filter.Allowed(ctx.ClientIP()) {
+			ctx.Abort(http.StatusForbidden, AdminCodeForbidden, "IP not allowed")
+			return
+		}
+	}
+
+	if a.config.Token != "" {
+		token := c.GetHeader(a.config.TokenHeader)
+		if token == "" {
+			token = strings.TrimPrefix(c.GetHeader("Authorization"), "Bearer ")
+		}
+		if subtle.ConstantTimeCompare([]byte(token), []byte(a.config.Token)) != 1 {
+			ctx.Abort(http.StatusUnauthorized, AdminCodeUnauthorized, "invalid admin token")
+			return
+		}
+	}
+
+	c.Header("Cache-Control", "no-store")
+	c.Next()
+}
+
+// pprof net/http/pprof 的处理函数，支持任意路径前缀
+func (a *Admin) pprof(c *gin.Context) {
+	name := strings.TrimPrefix(c.Param("name"), "/")
+
+	switch name {
+	case "cmdline":
+		pprof.Cmdline(c.Writer, c.Request)
+	case "profile":
+		pprof.Profile(c.Writer, c.Request)
+	case "symbol":
+		pprof.Symbol(c.Writer, c.Request)
+	case "trace":
+		pprof.Trace(c.Writer, c.Request)
+	default:
+		// pprof.Index 按 /debug/pprof/ 前缀解析 profile 名称
+		c.Request.URL.Path = "/debug/pprof/" + name
+		pprof.Index(c.Writer, c.Request)
+	}
+}
+
+// goroutines 所有 goroutine 的完整堆栈
+func (a *Admin) goroutines(c *gin.Context) {
+	c.Header("Content-Type", "text/plain; charset=utf-8")
+	c.Status(http.StatusOK)
+	rpprof.Lookup("goroutine").WriteTo(c.Writer, 2)
+}
+
+// info 构建信息和运行时状态
+func (a *Admin) info(c *gin.Context) {
+	var mem runtime.MemStats
+	runtime.ReadMemStats(&mem)
+
+	info := gin.H{
+		"go_version": runtime.Version(),
+		"os":         runtime.GOOS,
+		"arch":       runtime.GOARCH,
+		"start_time": a.startTime.Format(time.RFC3339),
+		"uptime":     time.Since(a.startTime).Round(time.Second).String(),
+		"runtime": gin.H{
+			"goroutines":     runtime.NumGoroutine(),
+			"gomaxprocs":     runtime.GOMAXPROCS(0),
+			"num_cpu":        runtime.NumCPU(),
+			"heap_alloc":     mem.HeapAlloc,
+			"heap_inuse":     mem.HeapInuse,
+			"heap_objects":   mem.HeapObjects,
+			"sys":            mem.Sys,
+			"num_gc":         mem.NumGC,
+			"gc_pause_total": time.Duration(mem.PauseTotalNs).String(),
+		},
+	}
+
+	if build, ok := debug.ReadBuildInfo(); ok {
+		settings := make(map[string]string, len(build.Settings))
+		for _, setting := range build.Settings {
+			settings[setting.Key] = setting.Value
+		}
+		deps := make([]string, 0, len(build.Deps))
+		for _, dep := range build.Deps {
+			deps = append(deps, dep.Path+"@"+dep.Version)
+		}
+
+		info["build"] = gin.H{
+			"path":     build.Path,
+			"main":     build.Main.Path,
+			"version":  build.Main.Version,
+			"settings": settings,
+			"deps":     deps,
+		}
+	}
+
+	c.JSON(http.StatusOK, info)
+}
+
+// effectiveConfig 当前生效的配置，敏感字段脱敏，处理函数、对象等不可序列化的字段不输出
+func (a *Admin) effectiveConfig(c *gin.Context) {
+	c.JSON(http.StatusOK, a.redact(reflect.ValueOf(a.server.config)))
+}
+
+// routes 已注册的路由
+func (a *Admin) routes(c *gin.Context) {
+	engine := a.server.engine
+
+	routes := make([]gin.H, 0)
+	for _, route := range engine.Routes() {
+		routes = append(routes, gin.H{
+			"method":  route.Method,
+			"path":    route.Path,
+			"handler": route.Handler,
+		})
+	}
+
+	c.JSON(http.StatusOK, routes)
+}
+
+func (a *Admin) levelResponse() gin.H {
+	logger := a.config.Logger
+	return gin.H{
+		"level":       goolog.LevelText[logger.Level()],
+		"trace_level": goolog.LevelText[logger.TraceLevel()],
+	}
+}
+
+func (a *Admin) logLevel(c *gin.Context) {
+	c.JSON(http.StatusOK, a.levelResponse())
+}
+
+// setLogLevel 调整日志级别，设置 duration 时到期后恢复原级别
+//
+//	PUT /debug/log/level {"level": "debug", "duration": "10m"}
+func (a *Admin) setLogLevel(c *gin.Context) {
+	ctx := &Context{Context: c}
+
+	var req struct {
+		Level      string `json:"level"`
+		TraceLevel string `json:"trace_level"`
+		Duration   string `json:"duration"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		ctx.Abort(http.StatusBadRequest, AdminCodeInvalidLevel, err.Error())
+		return
+	}
+
+	logger := a.config.Logger
+	level, traceLevel := logger.Level(), logger.TraceLevel()
+
+	var err error
+	if req.Level != "" {
+		if level, err = goolog.ParseLevel(req.Level); err != nil {
+			ctx.Abort(http.StatusBadRequest, AdminCodeInvalidLevel, err.Error())
+			return
+		}
+	}
+	if req.TraceLevel != "" {
+		if traceLevel, err = goolog.ParseLevel(req.TraceLevel); err != nil {
+			ctx.Abort(http.StatusBadRequest, AdminCodeInvalidLevel, err.Error())
+			return
+		}
+	}
+
+	var duration time.Duration
+	if req.Duration != "" {
+		if duration, err = time.ParseDuration(req.Duration); err != nil || duration <= 0 {
+			ctx.Abort(http.StatusBadRequest, AdminCodeInvalidLevel, "invalid duration")
+			return
+		}
+	}
+
+	a.levelMu.Lock()
+	defer a.levelMu.Unlock()
+
+	// 新的调整取消未到期的恢复；连续临时调整时恢复到第一次调整前的级别
+	if a.restore == nil {
+		a.original, a.traceOrig = logger.Level(), logger.TraceLevel()
+	} else {
+		a.restore.Stop()
+		a.restore = nil
+	}
+	if duration > 0 {
+		var timer *time.Timer
+		timer = time.AfterFunc(duration, func() {
+			a.levelMu.Lock()
+			defer a.levelMu.Unlock()
+
+			// 已被新的调整取消（Stop 时回调可能已经开始执行）
+			if a.restore != timer {
+				return
+			}
+			a.restore = nil
+			logger.SetLevel(a.original)
+			logger.SetTraceLevel(a.traceOrig)
+		})
+		a.restore = timer
+	}
+
+	logger.SetLevel(level)
+	logger.SetTraceLevel(traceLevel)
+
+	c.JSON(http.StatusOK, a.levelResponse())
+}
+
+// redact 将配置转换为可序列化的结构，按 json 标签命名，跳过 json:"-"、处理函数和通道，敏感字段脱敏
+func (a *Admin) redact(v reflect.Value) any {
+	for v.Kind() == reflect.Pointer || v.Kind() == reflect.Interface {
+		if v.IsNil() {
+			return nil
+		}
+		v = v.Elem()
+	}
+
+	if v.Type() == reflect.TypeOf(time.Duration(0)) {
+		return time.Duration(v.Int()).String()
+	}
+
+	switch v.Kind() {
+	case reflect.Struct:
+		out := make(map[string]any)
+		t := v.Type()
+		for i := 0; i < t.NumField(); i++ {
+			field := t.Field(i)
+			if !field.IsExported() {
+				continue
+			}
+			name := field.Name
+			if tag := field.Tag.Get("json"); tag != "" {
+				if tag == "-" {
+					continue
+				}
+				if n, _, _ := strings.Cut(tag, ","); n != "" {
+					name = n
+				}
+			}
+			switch field.Type.Kind() {
+			case reflect.Func, reflect.Chan, reflect.UnsafePointer:
+				continue
+			}
+
+			value := v.Field(i)
+			if a.sensitive(name) && !value.IsZero() {
+				out[name] = "******"
+				continue
+			}
+			out[name] = a.redact(value)
+		}
+		return out
+	case reflect.Map:
+		if v.IsNil() {
+			return nil
+		}
+		out := make(map[string]any, v.Len())
+		iter := v.MapRange()
+		for iter.Next() {
+			name := fmt.Sprint(iter.Key().Interface())
+			if a.sensitive(name) {
+				out[name] = "******"
+				continue
+			}
+			out[name] = a.redact(iter.Value())
+		}
+		return out
+	case reflect.Slice, reflect.Array:
+		if v.Kind() == reflect.Slice && v.IsNil() {
+			return nil
+		}
+		// 字节切片通常是密钥
+		if v.Type().Elem().Kind() == reflect.Uint8 {
+			return "******"
+		}
+		out := make([]any, v.Len())
+		for i := 0; i < v.Len(); i++ {
+			out[i] = a.redact(v.Index(i))
+		}
+		return out
+	case reflect.Func, reflect.Chan, reflect.UnsafePointer:
+		return nil
+	}
+
+	return v.Interface()
+}
+
+// sensitive 字段名等于或以敏感键结尾时脱敏，如 access_token、clientSecret；
+// 不按包含匹配，避免 token_header、secret_id 等非敏感字段被脱敏
+func (a *Admin) sensitive(name string) bool {
+	name = normalizeSensitiveKey(name)
+	for _, key := range a.config.SensitiveKeys {
+		if key = normalizeSensitiveKey(key); key != "" && strings.HasSuffix(name, key) {
+			return true
+		}
+	}
+	return false
+}
+
+// normalizeSensitiveKey 转为小写并去掉下划线和连字符，api_key、apiKey、X-Api-Key 按同一名称匹配
+func normalizeSensitiveKey(name string) string {
+	return strings.NewReplacer("_", "", "-", "").Replace(strings.ToLower(name))
+}
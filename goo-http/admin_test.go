@@ -0,0 +1,63 @@
+package goohttp
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	goolog "v2.googo.io/goo-log"
+)
+
+// 连续临时调整日志级别，到期后恢复到第一次调整前的级别
+func TestAdminTemporaryLogLevelRestoresOriginal(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	logger := goolog.New()
+	logger.SetLevel(goolog.INFO)
+
+	server := New(
+		WithEnableLog(false),
+		WithEnableAdmin(true),
+		WithAdminConfig(&AdminConfig{Token: "secret", Logger: logger}),
+	)
+
+	setLevel := func(body string) {
+		req := httptest.NewRequest(http.MethodPut, "/debug/log/level", strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("X-Admin-Token", "secret")
+		w := httptest.NewRecorder()
+		server.ServeHTTP(w, req)
+		if w.Code != http.StatusOK {
+			t.Fatalf("status = %d, body = %s", w.Code, w.Body)
+		}
+	}
+
+	setLevel(`{"level":"debug","duration":"50ms"}`)
+	setLevel(`{"level":"error","duration":"50ms"}`)
+	if logger.Level() != goolog.ERROR {
+		t.Fatalf("level = %v, want ERROR", logger.Level())
+	}
+
+	time.Sleep(150 * time.Millisecond)
+	if logger.Level() != goolog.INFO {
+		t.Fatalf("restored level = %v, want INFO", logger.Level())
+	}
+}
+
+func TestAdminSensitiveMatchesSuffix(t *testing.T) {
+	a := &Admin{config: DefaultAdminConfig}
+
+	for _, name := range []string{"password", "access_token", "clientSecret", "X-Api-Key", "secret_key", "Token"} {
+		if !a.sensitive(name) {
+			t.Errorf("sensitive(%q) = false, want true", name)
+		}
+	}
+	for _, name := range []string{"token_header", "secret_id", "sensitive_keys", "access_key_id", "addr"} {
+		if a.sensitive(name) {
+			t.Errorf("sensitive(%q) = true, want false", name)
+		}
+	}
+}
@@ -0,0 +1,272 @@
+package goohttp
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"sync"
+
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+const (
+	// BizErrorDomain gRPC ErrorInfo 中的 domain，用于识别业务错误
+	BizErrorDomain = "googo.io"
+)
+
+// BizError 业务错误，同时携带业务码、消息 key、HTTP 状态码和 gRPC 状态码
+type BizError struct {
+	Code       int        // 业务码（全局唯一）
+	MessageKey string     // 消息 key，按语言从消息目录中获取消息
+	HTTPStatus int        // HTTP 状态码
+	GRPCCode   codes.Code // gRPC 状态码
+	args       []any      // 消息格式化参数
+	message    string     // 远端返回的消息（客户端还原时使用）
+	cause      error      // 原始错误
+}
+
+var (
+	bizErrors   = make(map[int]*BizError)
+	bizErrorsMu sync.RWMutex
+)
+
+// RegisterBizError 注册业务错误，业务码重复时 panic（应在包初始化时注册）
+func RegisterBizError(code int, messageKey string, httpStatus int, grpcCode codes.Code) *BizError {
+	bizErrorsMu.Lock()
+	defer bizErrorsMu.Unlock()
+
+	if exists, ok := bizErrors[code]; ok {
+		panic(fmt.Sprintf("goohttp: 业务码 %d 重复注册（%s / %s）", code, exists.MessageKey, messageKey))
+	}
+
+	err := &BizError{
+		Code:       code,
+		MessageKey: messageKey,
+		HTTPStatus: httpStatus,
+		GRPCCode:   grpcCode,
+	}
+	bizErrors[code] = err
+
+	return err
+}
+
+// LookupBizError 根据业务码查找已注册的业务错误
+func LookupBizError(code int) (*BizError, bool) {
+	bizErrorsMu.RLock()
+	defer bizErrorsMu.RUnlock()
+
+	err, ok := bizErrors[code]
+	return err, ok
+}
+
+func (e *BizError) Error() string {
+	msg := e.Message(DefaultLanguage)
+	if e.cause != nil {
+		return fmt.Sprintf("[%d] %s: %v", e.Code, msg, e.cause)
+	}
+	return fmt.Sprintf("[%d] %s", e.Code, msg)
+}
+
+func (e *BizError) Unwrap() error {
+	return e.cause
+}
+
+// Is 业务码相同即视为同一错误
+func (e *BizError) Is(target error) bool {
+	var t *BizError
+	if errors.As(target, &t) {
+		return e.Code == t.Code
+	}
+	return false
+}
+
+// Wrap 返回包装原始错误的副本
+func (e *BizError) Wrap(cause error) *BizError {
+	err := *e
+	err.cause = cause
+	return &err
+}
+
+// WithArgs 返回附带消息格式化参数的副本，如消息为 "余额不足，还差 %d 元"
+func (e *BizError) WithArgs(args ...any) *BizError {
+	err := *e
+	err.args = args
+	return &err
+}
+
+// Message 获取指定语言的消息
+func (e *BizError) Message(lang string) string {
+	if e.message != "" {
+		return e.message
+	}
+
+	msg := Translate(lang, e.MessageKey)
+	if len(e.args) > 0 {
+		msg = fmt.Sprintf(msg, e.args...)
+	}
+	return msg
+}
+
+// Response 转换为响应
+func (e *BizError) Response(ctx *Context) *Response {
+	return Error(ctx, e.Code, e.Message(ctx.Language()))
+}
+
+// GRPCStatus 转换为 gRPC 状态（使用默认语言），实现该方法后 status.FromError 可直接识别
+func (e *BizError) GRPCStatus() *status.Status {
+	return e.GRPCStatusWithLanguage(DefaultLanguage)
+}
+
+// GRPCStatusWithLanguage 转换为指定语言的 gRPC 状态，details 中携带 ErrorInfo 和 LocalizedMessage
+func (e *BizError) GRPCStatusWithLanguage(lang string) *status.Status {
+	code := e.GRPCCode
+	if code == codes.OK {
+		code = codes.Unknown
+	}
+
+	msg := e.Message(lang)
+	st := status.New(code, msg)
+
+	detailed, err := st.WithDetails(
+		&errdetails.ErrorInfo{
+			Reason: e.MessageKey,
+			Domain: BizErrorDomain,
+			Metadata: map[string]string{
+				"code":        strconv.Itoa(e.Code),
+				"http_status": strconv.Itoa(e.HTTPStatus),
+			},
+		},
+		&errdetails.LocalizedMessage{
+			Locale:  lang,
+			Message: msg,
+		},
+	)
+	if err != nil {
+		return st
+	}
+
+	return detailed
+}
+
+// AsBizError 从错误链中获取业务错误
+func AsBizError(err error) (*BizError, bool) {
+	var bizErr *BizError
+	if errors.As(err, &bizErr) {
+		return bizErr, true
+	}
+	return nil, false
+}
+
+// ToGRPCError gRPC 服务端使用：将业务错误转换为 gRPC 错误，语言取自请求 metadata 的 accept-language
+func ToGRPCError(ctx context.Context, err error) error {
+	bizErr, ok := AsBizError(err)
+	if !ok {
+		return err
+	}
+
+	lang := DefaultLanguage
+	if md, ok := metadata.FromIncomingContext(ctx); ok {
+		if values := md.Get("accept-language"); len(values) > 0 {
+			lang = MatchLanguage(values[0])
+		}
+	}
+
+	return bizErr.GRPCStatusWithLanguage(lang).Err()
+}
+
+// FromGRPCError gRPC 客户端使用：从 gRPC 错误还原业务错误
+// 业务码已注册时返回注册的错误（消息使用服务端返回的本地化消息），否则按 details 构造
+func FromGRPCError(err error) (*BizError, bool) {
+	st, ok := status.FromError(err)
+	if !ok || st.Code() == codes.OK {
+		return nil, false
+	}
+
+	var info *errdetails.ErrorInfo
+	var localized *errdetails.LocalizedMessage
+
+	for _, detail := range st.Details() {
+		switch d := detail.(type) {
+		case *errdetails.ErrorInfo:
+			if d.GetDomain() == BizErrorDomain {
+				info = d
+			}
+		case *errdetails.LocalizedMessage:
+			localized = d
+		}
+	}
+
+	if info == nil {
+		return nil, false
+	}
+
+	code, convErr := strconv.Atoi(info.GetMetadata()["code"])
+	if convErr != nil {
+		return nil, false
+	}
+
+	bizErr := &BizError{
+		Code:       code,
+		MessageKey: info.GetReason(),
+		GRPCCode:   st.Code(),
+	}
+	if registered, ok := LookupBizError(code); ok {
+		clone := *registered
+		bizErr = &clone
+	}
+	if httpStatus, err := strconv.Atoi(info.GetMetadata()["http_status"]); err == nil && bizErr.HTTPStatus == 0 {
+		bizErr.HTTPStatus = httpStatus
+	}
+
+	bizErr.message = st.Message()
+	if localized != nil && localized.GetMessage() != "" {
+		bizErr.message = localized.GetMessage()
+	}
+	bizErr.cause = err
+
+	return bizErr, true
+}
+
+// FromResponse HTTP 客户端使用：从响应还原业务错误，成功响应返回 false
+func FromResponse(resp *Response, httpStatus int) (*BizError, bool) {
+	if resp == nil || resp.IsSuccess() {
+		return nil, false
+	}
+
+	bizErr := &BizError{
+		Code:       resp.Code,
+		HTTPStatus: httpStatus,
+		GRPCCode:   codes.Unknown,
+	}
+	if registered, ok := LookupBizError(resp.Code); ok {
+		clone := *registered
+		bizErr = &clone
+	}
+
+	bizErr.message = resp.Message
+
+	return bizErr, true
+}
+
+// Fail 按错误写入响应并中止请求（错误转换规则见 MapError）
+func (c *Context) Fail(err error) {
+	c.Context.Error(err)
+
+	httpErr := errorMapper(c, err)
+	if httpErr == nil {
+		httpErr = MapError(c, err)
+	}
+
+	status := httpErr.Status
+	if status == 0 {
+		status = http.StatusOK
+	}
+
+	c.writeResponse(status, ErrorWithData(c, httpErr.Code, httpErr.Message, httpErr.Data))
+	c.Context.Abort()
+}
@@ -0,0 +1,522 @@
+package goohttp
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+var (
+	DefaultResponseCacheConfig = &ResponseCacheConfig{
+		TTL:         time.Minute,
+		MaxBodySize: 1 * 1024 * 1024,
+	}
+)
+
+// CacheRule 路由缓存规则
+type CacheRule struct {
+	TTL         time.Duration               // 缓存时间（默认使用 ResponseCacheConfig.TTL）
+	Tags        []string                    // 缓存标签
+	TagFunc     func(ctx *Context) []string // 按请求生成缓存标签，如 "product:" + ctx.Param("id")
+	Private     bool                        // 输出 Cache-Control: private，只允许客户端缓存
+	VaryHeaders []string                    // 参与缓存 key 的请求头（追加到 ResponseCacheConfig.VaryHeaders）
+}
+
+type ResponseCacheConfig struct {
+	Store        ResponseCacheStore            // 缓存存储（默认进程内 LRU，多实例部署使用 RedisCacheStore）
+	MaxEntries   int                           // 默认进程内存储的最大缓存数量（默认 10000）
+	TTL          time.Duration                 // 默认缓存时间（默认 1 分钟）
+	Routes       map[string]CacheRule          // 缓存的路由，key 为 "GET /api/products/:id" 或 "/api/products/:id"，为空时不缓存（只有 Wrap 包装的路由生效）
+	VaryHeaders  []string                      // 参与缓存 key 的请求头，如 Accept-Language
+	KeyFunc      func(ctx *Context) string     // 追加到缓存 key 的内容（如租户ID、用户ID），返回 "-" 时不缓存；携带认证信息的请求只有返回非空值时才缓存
+	MaxBodySize  int                           // 可缓存的最大响应体（默认 1MB）
+	OnStoreError func(ctx *Context, err error) // 缓存存储出错时的回调（如记录日志、告警）
+}
+
+// ResponseCache 响应缓存
+// 只缓存 GET 请求中状态码为 200 且业务状态码为成功的响应，相同 key 的并发请求只执行一次处理函数
+type ResponseCache struct {
+	config *ResponseCacheConfig
+	store  ResponseCacheStore
+	flight cacheFlightGroup
+}
+
+func NewResponseCache(config *ResponseCacheConfig) *ResponseCache {
+	if config == nil {
+		config = DefaultResponseCacheConfig
+	}
+
+	c := *config
+	if c.TTL <= 0 {
+		c.TTL = DefaultResponseCacheConfig.TTL
+	}
+	if c.MaxBodySize <= 0 {
+		c.MaxBodySize = DefaultResponseCacheConfig.MaxBodySize
+	}
+
+	cache := &ResponseCache{
+		config: &c,
+		store:  c.Store,
+	}
+	if cache.store == nil {
+		cache.store = NewMemoryCacheStore(c.MaxEntries)
+	}
+
+	return cache
+}
+
+// Store 获取缓存存储
+func (rc *ResponseCache) Store() ResponseCacheStore {
+	return rc.store
+}
+
+// InvalidateTags 删除带有指定标签的缓存
+func (rc *ResponseCache) InvalidateTags(ctx context.Context, tags ...string) error {
+	if len(tags) == 0 {
+		return nil
+	}
+	return rc.store.InvalidateTags(ctx, tags...)
+}
+
+// Invalidate 写接口处理成功后按标签删除缓存，放在处理函数之后
+//
+//	server.Put("/products/:id", updateProduct, cache.Invalidate(func(ctx *goohttp.Context) []string {
+//		return []string{"product:" + ctx.Param("id")}
+//	}))
+func (rc *ResponseCache) Invalidate(tagFunc func(ctx *Context) []string) HandlerFunc {
+	return func(ctx *Context) {
+		if code, ok := ctx.ResponseCode(); ok && code != SuccessCode {
+			return
+		}
+		if err := rc.InvalidateTags(ctx.Request.Context(), tagFunc(ctx)...); err != nil {
+			rc.storeError(ctx, err)
+		}
+	}
+}
+
+// Wrap 路由级缓存，包装处理函数，不传规则时使用默认缓存时间
+//
+//	server.Get("/products/:id", cache.Wrap(getProduct, goohttp.CacheRule{TTL: 5 * time.Minute}))
+func (rc *ResponseCache) Wrap(handler HandlerFunc, rules ...CacheRule) HandlerFunc {
+	var rule CacheRule
+	if len(rules) > 0 {
+		rule = rules[0]
+	}
+
+	return func(ctx *Context) {
+		rc.serve(ctx, rule, func() {
+			handler(ctx)
+		})
+	}
+}
+
+func (rc *ResponseCache) match(ctx *Context) (CacheRule, bool) {
+	if ctx.Request.Method != http.MethodGet {
+		return CacheRule{}, false
+	}
+
+	route := ctx.FullPath()
+	if rule, ok := rc.config.Routes[http.MethodGet+" "+route]; ok {
+		return rule, true
+	}
+	rule, ok := rc.config.Routes[route]
+	return rule, ok
+}
+
+func (rc *ResponseCache) serve(ctx *Context, rule CacheRule, next func()) {
+	key, ok := rc.cacheKey(ctx, rule)
+	if !ok {
+		next()
+		return
+	}
+
+	// 按用户区分的响应只允许客户端缓存，不允许共享代理缓存
+	if rc.credentialed(ctx) {
+		rule.Private = true
+	}
+
+	entry, err := rc.store.Get(ctx.Request.Context(), key)
+	if err != nil {
+		rc.storeError(ctx, err)
+		next()
+		return
+	}
+	if entry != nil {
+		rc.serveEntry(ctx, rule, entry, "HIT")
+		return
+	}
+
+	// 相同 key 的并发请求只有一个执行处理函数，其余等待结果
+	entry, leader := rc.flight.do(key, func() *CacheEntry {
+		return rc.fill(ctx, rule, key, next)
+	})
+	if leader {
+		return
+	}
+	if entry == nil {
+		// 首个请求的响应不可缓存，各自处理
+		next()
+		return
+	}
+
+	rc.serveEntry(ctx, rule, entry, "HIT")
+}
+
+// fill 执行处理函数并保存响应，返回 nil 表示响应不可缓存
+func (rc *ResponseCache) fill(ctx *Context, rule CacheRule, key string, next func()) *CacheEntry {
+	writer := &cacheResponseWriter{
+		ResponseWriter: ctx.Writer,
+		buffer:         getBuffer(),
+		maxSize:        rc.config.MaxBodySize,
+	}
+	defer putBuffer(writer.buffer)
+
+	before := ctx.Writer.Header().Clone()
+	ctx.Writer = writer
+
+	func() {
+		defer func() {
+			ctx.Writer = writer.ResponseWriter
+		}()
+		next()
+	}()
+
+	if !rc.cacheable(ctx, writer) {
+		writer.writeThrough()
+		return nil
+	}
+
+	now := time.Now()
+	ttl := rule.TTL
+	if ttl <= 0 {
+		ttl = rc.config.TTL
+	}
+
+	body := append([]byte(nil), writer.buffer.Bytes()...)
+	sum := sha256.Sum256(body)
+
+	entry := &CacheEntry{
+		Status:       http.StatusOK,
+		Header:       changedHeaders(before, writer.Header()),
+		Body:         body,
+		ETag:         `"` + hex.EncodeToString(sum[:16]) + `"`,
+		LastModified: now.UTC().Truncate(time.Second),
+		ExpiresAt:    now.Add(ttl),
+		Tags:         rc.tags(ctx, rule),
+	}
+	// 以下响应头由缓存统一生成
+	for _, name := range []string{"Etag", "Last-Modified", "Cache-Control", "Expires", "X-Cache"} {
+		entry.Header.Del(name)
+	}
+
+	if err := rc.store.Set(ctx.Request.Context(), key, entry, ttl); err != nil {
+		rc.storeError(ctx, err)
+	}
+
+	rc.serveEntry(ctx, rule, entry, "MISS")
+
+	return entry
+}
+
+func (rc *ResponseCache) cacheable(ctx *Context, writer *cacheResponseWriter) bool {
+	if writer.bypass || writer.overflow || writer.Status() != http.StatusOK || isStreaming(ctx.Context) {
+		return false
+	}
+	if code, ok := ctx.ResponseCode(); ok && code != SuccessCode {
+		return false
+	}
+
+	header := writer.Header()
+	if header.Get("Set-Cookie") != "" {
+		return false
+	}
+	cacheControl := strings.ToLower(header.Get("Cache-Control"))
+	if strings.Contains(cacheControl, "no-store") || strings.Contains(cacheControl, "private") {
+		return false
+	}
+
+	return true
+}
+
+func (rc *ResponseCache) serveEntry(ctx *Context, rule CacheRule, entry *CacheEntry, state string) {
+	header := ctx.Writer.Header()
+	for name, values := range entry.Header {
+		header[name] = append([]string(nil), values...)
+	}
+
+	maxAge := int(time.Until(entry.ExpiresAt).Seconds())
+	if maxAge < 0 {
+		maxAge = 0
+	}
+	visibility := "public"
+	if rule.Private {
+		visibility = "private"
+	}
+
+	header.Set("ETag", entry.ETag)
+	header.Set("Last-Modified", entry.LastModified.Format(http.TimeFormat))
+	header.Set("Cache-Control", visibility+", max-age="+strconv.Itoa(maxAge))
+	header.Set("X-Cache", state)
+	if vary := rc.varyHeaders(rule); len(vary) > 0 {
+		header.Set("Vary", strings.Join(vary, ", "))
+	}
+
+	ctx.Set("response-code", SuccessCode)
+
+	if notModified(ctx.Request, entry) {
+		header.Del("Content-Type")
+		header.Del("Content-Length")
+		ctx.Status(http.StatusNotModified)
+		ctx.Context.Abort()
+		return
+	}
+
+	ctx.Status(entry.Status)
+	ctx.Writer.Write(entry.Body)
+	ctx.Context.Abort()
+}
+
+// credentialed 请求是否携带认证信息（Authorization 请求头或已有的会话）
+func (rc *ResponseCache) credentialed(ctx *Context) bool {
+	if ctx.GetHeader("Authorization") != "" {
+		return true
+	}
+	session := ctx.Session()
+	return session != nil && !session.IsNew()
+}
+
+// cacheKey 缓存 key：路由模板 + 请求地址、排序后的查询参数、请求头和 KeyFunc 的摘要
+// 携带认证信息的请求响应可能包含用户数据，KeyFunc 没有按用户区分 key 时不缓存
+func (rc *ResponseCache) cacheKey(ctx *Context, rule CacheRule) (string, bool) {
+	var extra string
+	if rc.config.KeyFunc != nil {
+		if extra = rc.config.KeyFunc(ctx); extra == "-" {
+			return "", false
+		}
+	}
+	if extra == "" && rc.credentialed(ctx) {
+		return "", false
+	}
+
+	h := sha256.New()
+	h.Write([]byte(ctx.Request.URL.Path))
+	h.Write([]byte{'\n'})
+	h.Write([]byte(ctx.Request.URL.Query().Encode()))
+	for _, name := range rc.varyHeaders(rule) {
+		h.Write([]byte{'\n'})
+		h.Write([]byte(ctx.GetHeader(name)))
+	}
+	h.Write([]byte{'\n'})
+	h.Write([]byte(extra))
+
+	route := ctx.FullPath()
+	if route == "" {
+		route = ctx.Request.URL.Path
+	}
+
+	return http.MethodGet + " " + route + ":" + hex.EncodeToString(h.Sum(nil)[:16]), true
+}
+
+func (rc *ResponseCache) varyHeaders(rule CacheRule) []string {
+	if len(rule.VaryHeaders) == 0 {
+		return rc.config.VaryHeaders
+	}
+
+	headers := append([]string(nil), rc.config.VaryHeaders...)
+	headers = append(headers, rule.VaryHeaders...)
+	sort.Strings(headers)
+
+	return headers
+}
+
+func (rc *ResponseCache) tags(ctx *Context, rule CacheRule) []string {
+	tags := append([]string(nil), rule.Tags...)
+	if rule.TagFunc != nil {
+		tags = append(tags, rule.TagFunc(ctx)...)
+	}
+	return tags
+}
+
+func (rc *ResponseCache) storeError(ctx *Context, err error) {
+	if rc.config.OnStoreError != nil {
+		rc.config.OnStoreError(ctx, err)
+	}
+}
+
+// notModified 条件请求：If-None-Match 优先，其次 If-Modified-Since
+func notModified(req *http.Request, entry *CacheEntry) bool {
+	if inm := req.Header.Get("If-None-Match"); inm != "" {
+		etag := strings.TrimPrefix(entry.ETag, "W/")
+		for _, tag := range strings.Split(inm, ",") {
+			tag = strings.TrimSpace(tag)
+			if tag == "*" || strings.TrimPrefix(tag, "W/") == etag {
+				return true
+			}
+		}
+		return false
+	}
+
+	if ims := req.Header.Get("If-Modified-Since"); ims != "" {
+		t, err := http.ParseTime(ims)
+		if err != nil {
+			return false
+		}
+		return !entry.LastModified.After(t)
+	}
+
+	return false
+}
+
+// 缓存响应写入器，缓冲响应以便生成 ETag 和处理条件请求
+// 处理函数调用 Flush（如流式输出）时改为直接输出，不再缓存
+type cacheResponseWriter struct {
+	gin.ResponseWriter
+	buffer   *bytes.Buffer
+	maxSize  int
+	status   int
+	bypass   bool
+	overflow bool
+}
+
+func (w *cacheResponseWriter) WriteHeader(statusCode int) {
+	if w.bypass {
+		w.ResponseWriter.WriteHeader(statusCode)
+		return
+	}
+	w.status = statusCode
+}
+
+func (w *cacheResponseWriter) WriteHeaderNow() {
+	if w.bypass {
+		w.ResponseWriter.WriteHeaderNow()
+	}
+}
+
+func (w *cacheResponseWriter) Write(data []byte) (int, error) {
+	if w.bypass {
+		return w.ResponseWriter.Write(data)
+	}
+	if w.status == 0 {
+		w.status = http.StatusOK
+	}
+	if w.buffer.Len()+len(data) > w.maxSize {
+		w.overflow = true
+	}
+	return w.buffer.Write(data)
+}
+
+func (w *cacheResponseWriter) WriteString(s string) (int, error) {
+	return w.Write([]byte(s))
+}
+
+func (w *cacheResponseWriter) Status() int {
+	if w.bypass {
+		return w.ResponseWriter.Status()
+	}
+	if w.status == 0 {
+		return http.StatusOK
+	}
+	return w.status
+}
+
+func (w *cacheResponseWriter) Size() int {
+	if w.bypass {
+		return w.ResponseWriter.Size()
+	}
+	if w.status == 0 && w.buffer.Len() == 0 {
+		return -1
+	}
+	return w.buffer.Len()
+}
+
+func (w *cacheResponseWriter) Written() bool {
+	if w.bypass {
+		return w.ResponseWriter.Written()
+	}
+	return w.status != 0 || w.buffer.Len() > 0
+}
+
+func (w *cacheResponseWriter) Flush() {
+	w.writeThrough()
+	w.ResponseWriter.Flush()
+}
+
+// writeThrough 输出已缓冲的响应，之后的写入直接输出
+func (w *cacheResponseWriter) writeThrough() {
+	if w.bypass {
+		return
+	}
+	w.bypass = true
+
+	if w.status != 0 {
+		w.ResponseWriter.WriteHeader(w.status)
+	}
+	if w.buffer.Len() > 0 {
+		w.ResponseWriter.Write(w.buffer.Bytes())
+	}
+}
+
+type cacheFlightCall struct {
+	wg    sync.WaitGroup
+	entry *CacheEntry
+}
+
+// cacheFlightGroup 合并相同 key 的并发回源请求，防止缓存击穿
+type cacheFlightGroup struct {
+	calls map[string]*cacheFlightCall
+	mu    sync.Mutex
+}
+
+// do 执行 fn，相同 key 正在执行时等待其结果；leader 表示当前调用是否执行了 fn
+func (g *cacheFlightGroup) do(key string, fn func() *CacheEntry) (entry *CacheEntry, leader bool) {
+	g.mu.Lock()
+	if g.calls == nil {
+		g.calls = make(map[string]*cacheFlightCall)
+	}
+	if call, ok := g.calls[key]; ok {
+		g.mu.Unlock()
+		call.wg.Wait()
+		return call.entry, false
+	}
+
+	call := &cacheFlightCall{}
+	call.wg.Add(1)
+	g.calls[key] = call
+	g.mu.Unlock()
+
+	// fn panic 时也要唤醒等待的请求
+	defer func() {
+		g.mu.Lock()
+		delete(g.calls, key)
+		g.mu.Unlock()
+		call.wg.Done()
+	}()
+
+	call.entry = fn()
+
+	return call.entry, true
+}
+
+// ResponseCacheMiddleware 响应缓存中间件，只处理配置的路由
+func ResponseCacheMiddleware(cache *ResponseCache) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		ctx := &Context{Context: c}
+
+		rule, ok := cache.match(ctx)
+		if !ok {
+			c.Next()
+			return
+		}
+
+		cache.serve(ctx, rule, c.Next)
+	}
+}
@@ -0,0 +1,255 @@
+package goohttp
+
+import (
+	"container/list"
+	"context"
+	"encoding/json"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/go-redis/redis/v8"
+	gooredis "v2.googo.io/goo-redis"
+)
+
+// CacheEntry 缓存的响应
+type CacheEntry struct {
+	Status       int         `json:"status"`         // 响应状态码
+	Header       http.Header `json:"header"`         // 处理函数设置的响应头
+	Body         []byte      `json:"body"`           // 响应体
+	ETag         string      `json:"etag"`           // 实体标签
+	LastModified time.Time   `json:"last_modified"`  // 缓存时间
+	ExpiresAt    time.Time   `json:"expires_at"`     // 过期时间
+	Tags         []string    `json:"tags,omitempty"` // 缓存标签，用于按标签失效
+}
+
+// ResponseCacheStore 响应缓存存储
+type ResponseCacheStore interface {
+	// Get 获取缓存，不存在时返回 nil, nil
+	Get(ctx context.Context, key string) (*CacheEntry, error)
+	// Set 保存缓存，并按 entry.Tags 建立标签索引
+	Set(ctx context.Context, key string, entry *CacheEntry, ttl time.Duration) error
+	// Delete 删除缓存
+	Delete(ctx context.Context, keys ...string) error
+	// InvalidateTags 删除带有指定标签的缓存
+	InvalidateTags(ctx context.Context, tags ...string) error
+}
+
+type memoryCacheItem struct {
+	key   string
+	entry *CacheEntry
+}
+
+// MemoryCacheStore 进程内 LRU 缓存（单实例部署使用）
+type MemoryCacheStore struct {
+	maxEntries int
+	ll         *list.List
+	items      map[string]*list.Element
+	tags       map[string]map[string]struct{}
+	mu         sync.Mutex
+}
+
+// NewMemoryCacheStore 创建进程内 LRU 缓存，超过 maxEntries 时淘汰最久未使用的缓存
+func NewMemoryCacheStore(maxEntries int) *MemoryCacheStore {
+	if maxEntries <= 0 {
+		maxEntries = 10000
+	}
+
+	return &MemoryCacheStore{
+		maxEntries: maxEntries,
+		ll:         list.New(),
+		items:      make(map[string]*list.Element),
+		tags:       make(map[string]map[string]struct{}),
+	}
+}
+
+func (s *MemoryCacheStore) Get(ctx context.Context, key string) (*CacheEntry, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	elem, ok := s.items[key]
+	if !ok {
+		return nil, nil
+	}
+
+	item := elem.Value.(*memoryCacheItem)
+	if !time.Now().Before(item.entry.ExpiresAt) {
+		s.removeElement(elem)
+		return nil, nil
+	}
+
+	s.ll.MoveToFront(elem)
+
+	return item.entry, nil
+}
+
+func (s *MemoryCacheStore) Set(ctx context.Context, key string, entry *CacheEntry, ttl time.Duration) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if elem, ok := s.items[key]; ok {
+		s.removeElement(elem)
+	}
+
+	elem := s.ll.PushFront(&memoryCacheItem{key: key, entry: entry})
+	s.items[key] = elem
+
+	for _, tag := range entry.Tags {
+		keys, ok := s.tags[tag]
+		if !ok {
+			keys = make(map[string]struct{})
+			s.tags[tag] = keys
+		}
+		keys[key] = struct{}{}
+	}
+
+	for s.ll.Len() > s.maxEntries {
+		s.removeElement(s.ll.Back())
+	}
+
+	return nil
+}
+
+func (s *MemoryCacheStore) Delete(ctx context.Context, keys ...string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, key := range keys {
+		if elem, ok := s.items[key]; ok {
+			s.removeElement(elem)
+		}
+	}
+
+	return nil
+}
+
+func (s *MemoryCacheStore) InvalidateTags(ctx context.Context, tags ...string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, tag := range tags {
+		for key := range s.tags[tag] {
+			if elem, ok := s.items[key]; ok {
+				s.removeElement(elem)
+			}
+		}
+		delete(s.tags, tag)
+	}
+
+	return nil
+}
+
+// Len 当前缓存数量
+func (s *MemoryCacheStore) Len() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.ll.Len()
+}
+
+func (s *MemoryCacheStore) removeElement(elem *list.Element) {
+	item := elem.Value.(*memoryCacheItem)
+
+	s.ll.Remove(elem)
+	delete(s.items, item.key)
+
+	for _, tag := range item.entry.Tags {
+		if keys, ok := s.tags[tag]; ok {
+			delete(keys, item.key)
+			if len(keys) == 0 {
+				delete(s.tags, tag)
+			}
+		}
+	}
+}
+
+// RedisCacheStore 基于 goo-redis 的响应缓存（多实例部署使用）
+// 标签索引保存在 Set 中，key 为 prefix + "tag:" + 标签
+type RedisCacheStore struct {
+	client *gooredis.Client
+	prefix string
+}
+
+func NewRedisCacheStore(client *gooredis.Client, prefix string) *RedisCacheStore {
+	if prefix == "" {
+		prefix = "goohttp:cache:"
+	}
+
+	return &RedisCacheStore{
+		client: client,
+		prefix: prefix,
+	}
+}
+
+func (s *RedisCacheStore) Get(ctx context.Context, key string) (*CacheEntry, error) {
+	data, err := s.client.Client().Get(ctx, s.prefix+key).Bytes()
+	if err == redis.Nil {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var entry CacheEntry
+	if err := json.Unmarshal(data, &entry); err != nil {
+		return nil, err
+	}
+
+	return &entry, nil
+}
+
+func (s *RedisCacheStore) Set(ctx context.Context, key string, entry *CacheEntry, ttl time.Duration) error {
+	data, err := json.Marshal(entry)
+	if err != nil {
+		return err
+	}
+
+	pipe := s.client.Client().TxPipeline()
+	pipe.Set(ctx, s.prefix+key, data, ttl)
+	for _, tag := range entry.Tags {
+		tagKey := s.prefix + "tag:" + tag
+		pipe.SAdd(ctx, tagKey, key)
+		// 标签索引比缓存多保留一段时间，过期的成员在失效时一并删除
+		pipe.Expire(ctx, tagKey, ttl+time.Hour)
+	}
+
+	_, err = pipe.Exec(ctx)
+	return err
+}
+
+func (s *RedisCacheStore) Delete(ctx context.Context, keys ...string) error {
+	if len(keys) == 0 {
+		return nil
+	}
+
+	fullKeys := make([]string, len(keys))
+	for i, key := range keys {
+		fullKeys[i] = s.prefix + key
+	}
+
+	return s.client.Client().Del(ctx, fullKeys...).Err()
+}
+
+func (s *RedisCacheStore) InvalidateTags(ctx context.Context, tags ...string) error {
+	rdb := s.client.Client()
+
+	for _, tag := range tags {
+		tagKey := s.prefix + "tag:" + tag
+
+		keys, err := rdb.SMembers(ctx, tagKey).Result()
+		if err != nil {
+			return err
+		}
+
+		fullKeys := make([]string, 0, len(keys)+1)
+		for _, key := range keys {
+			fullKeys = append(fullKeys, s.prefix+key)
+		}
+		fullKeys = append(fullKeys, tagKey)
+
+		if err := rdb.Del(ctx, fullKeys...).Err(); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -1,5 +1,11 @@
 package goohttp
 
+import (
+	"maps"
+	"slices"
+	"time"
+)
+
 var (
 	DefaultConfig = &Config{
 		Addr:            ":8080",
@@ -10,21 +16,150 @@ var (
 		CORSConfig:      DefaultCORSConfig,
 		EnableRateLimit: true,
 		RateLimiters:    []*RateLimiter{},
+		ShutdownTimeout: 30 * time.Second,
 	}
 )
 
 type Config struct {
-	Addr            string         `yaml:"addr" json:"addr"`                           // 监听端口
-	TraceIdHeader   string         `yaml:"trace_id_header" json:"trace_id_header"`     // TraceId 请求头名称，默认为 X-Request-Id
-	EnableLog       bool           `yaml:"enable_log" json:"enable_log"`               // 是否启用日志
-	Logger          Logger         `yaml:"-" json:"-"`                                 // 日志对象
-	EnableCORS      bool           `yaml:"enable_cors" json:"enable_cors"`             // 是否启用CORS
-	CORSConfig      *CORSConfig    `yaml:"cors" json:"cors"`                           // CORS配置
-	EnableRateLimit bool           `yaml:"enable_rate_limit" json:"enable_rate_limit"` // 是否启用限流
-	RateLimiters    []*RateLimiter `yaml:"-" json:"-"`                                 // 限流对象
-	EnableEncrypt   bool           `yaml:"enable_encrypt" json:"enable_encrypt"`       // 是否启用加密传输
-	Encryptor       Encryptor      `yaml:"-" json:"-"`                                 // 加解密对象
-	ResponseHooks   []ResponseHook `yaml:"-" json:"-"`                                 // 响应钩子函数
+	Addr              string            `yaml:"addr" json:"addr"`                             // 监听端口
+	TraceIdHeader     string            `yaml:"trace_id_header" json:"trace_id_header"`       // TraceId 请求头名称，默认为 X-Request-Id
+	EnableLog         bool              `yaml:"enable_log" json:"enable_log"`                 // 是否启用日志
+	Logger            Logger            `yaml:"-" json:"-"`                                   // 日志对象
+	EnableCORS        bool              `yaml:"enable_cors" json:"enable_cors"`               // 是否启用CORS
+	CORSConfig        *CORSConfig       `yaml:"cors" json:"cors"`                             // CORS配置
+	EnableRateLimit   bool              `yaml:"enable_rate_limit" json:"enable_rate_limit"`   // 是否启用限流
+	RateLimiters      []*RateLimiter    `yaml:"-" json:"-"`                                   // 限流对象
+	EnableJWT         bool              `yaml:"enable_jwt" json:"enable_jwt"`                 // 是否启用JWT认证
+	JWTAuth           *JWTAuth          `yaml:"-" json:"-"`                                   // JWT认证对象
+	EnableSign        bool              `yaml:"enable_sign" json:"enable_sign"`               // 是否启用签名校验
+	SignVerifier      *SignVerifier     `yaml:"-" json:"-"`                                   // 签名校验对象
+	EnableEncrypt     bool              `yaml:"enable_encrypt" json:"enable_encrypt"`         // 是否启用加密传输
+	Encryptor         Encryptor         `yaml:"-" json:"-"`                                   // 加解密对象
+	Encryption        *Encryption       `yaml:"-" json:"-"`                                   // 加解密对象（按应用选择密钥、信封格式、按路由加密），优先于 Encryptor
+	ResponseHooks     []ResponseHook    `yaml:"-" json:"-"`                                   // 响应钩子函数
+	EnableOpenAPI     bool              `yaml:"enable_openapi" json:"enable_openapi"`         // 是否提供接口文档
+	OpenAPIConfig     *OpenAPIConfig    `yaml:"openapi" json:"openapi"`                       // 接口文档配置
+	EnableHealth      bool              `yaml:"enable_health" json:"enable_health"`           // 是否提供健康检查地址
+	HealthConfig      *HealthConfig     `yaml:"health" json:"health"`                         // 健康检查配置
+	EnableMetrics     bool              `yaml:"enable_metrics" json:"enable_metrics"`         // 是否启用请求指标
+	MetricsConfig     *MetricsConfig    `yaml:"metrics" json:"metrics"`                       // 请求指标配置
+	EnableIdempotency bool              `yaml:"enable_idempotency" json:"enable_idempotency"` // 是否启用幂等处理
+	Idempotency       *Idempotency      `yaml:"-" json:"-"`                                   // 幂等处理对象
+	EnableCache       bool              `yaml:"enable_cache" json:"enable_cache"`             // 是否启用响应缓存
+	ResponseCache     *ResponseCache    `yaml:"-" json:"-"`                                   // 响应缓存对象
+	RecoveryConfig    *RecoveryConfig   `yaml:"-" json:"-"`                                   // panic 恢复配置
+	TrustedProxies    []string          `yaml:"trusted_proxies" json:"trusted_proxies"`       // 可信代理的 IP 或 CIDR，为空时不信任任何代理请求头
+	RemoteIPHeaders   []string          `yaml:"remote_ip_headers" json:"remote_ip_headers"`   // 读取客户端IP的请求头（默认 X-Forwarded-For、X-Real-Ip）
+	EnableIPFilter    bool              `yaml:"enable_ip_filter" json:"enable_ip_filter"`     // 是否启用IP黑白名单
+	IPFilter          *IPFilter         `yaml:"-" json:"-"`                                   // IP黑白名单对象
+	EnableTimeout     bool              `yaml:"enable_timeout" json:"enable_timeout"`         // 是否启用请求超时
+	TimeoutConfig     *TimeoutConfig    `yaml:"-" json:"-"`                                   // 请求超时配置
+	EnableLoadShed    bool              `yaml:"enable_load_shed" json:"enable_load_shed"`     // 是否启用自适应过载保护
+	LoadShedder       *AdaptiveLimiter  `yaml:"-" json:"-"`                                   // 自适应并发限制器
+	EnableAccessLog   bool              `yaml:"enable_access_log" json:"enable_access_log"`   // 是否启用访问日志（默认关闭；通过 goolog 输出，启用后不再使用 Logger 输出请求日志）
+	AccessLogConfig   *AccessLogConfig  `yaml:"-" json:"-"`                                   // 访问日志配置
+	EnableTLS         bool              `yaml:"enable_tls" json:"enable_tls"`                 // Addr 是否使用 TLS
+	TLSConfig         *TLSConfig        `yaml:"tls" json:"tls"`                               // Addr 的 TLS 配置（证书热加载、mTLS）
+	EnableH2C         bool              `yaml:"enable_h2c" json:"enable_h2c"`                 // Addr 是否启用明文 HTTP/2（h2c），启用 TLS 时忽略
+	Listeners         []*ListenerConfig `yaml:"listeners" json:"listeners"`                   // 额外的监听地址（TCP、unix socket），与 Addr 同时监听
+	ShutdownTimeout   time.Duration     `yaml:"shutdown_timeout" json:"shutdown_timeout"`     // 收到退出信号后优雅关闭的超时时间（默认 30 秒）
+	DrainDelay        time.Duration     `yaml:"drain_delay" json:"drain_delay"`               // 关闭前等待负载均衡摘除流量的时间，为 0 时启用健康检查使用 HealthConfig.DrainDelay
+	Registry          *RegistryConfig   `yaml:"registry" json:"registry"`                     // 注册中心配置，Run 时自动注册，关闭时注销
+	TraceIdGenerator  func() string     `yaml:"-" json:"-"`                                   // 请求没有 TraceId 时的生成函数（默认 UUID）
+	EnableAdmin       bool              `yaml:"enable_admin" json:"enable_admin"`             // 是否启用管理接口（pprof、运行时信息、生效配置、路由列表、日志级别）
+	AdminConfig       *AdminConfig      `yaml:"admin" json:"admin"`                           // 管理接口配置
+	EnableSession     bool              `yaml:"enable_session" json:"enable_session"`         // 是否启用会话（cookie / Redis 存储、CSRF 校验）
+	SessionManager    *SessionManager   `yaml:"-" json:"-"`                                   // 会话管理
+}
+
+// Clone 深拷贝配置：各项配置结构体及其中的切片、map 都会复制，修改副本不影响原配置
+// JWTAuth、RateLimiter、Encryption 等运行时对象仍然共享
+func (c *Config) Clone() *Config {
+	cp := *c
+
+	if c.CORSConfig != nil {
+		cors := *c.CORSConfig
+		cors.AllowOrigins = slices.Clone(cors.AllowOrigins)
+		cors.AllowMethods = slices.Clone(cors.AllowMethods)
+		cors.AllowHeaders = slices.Clone(cors.AllowHeaders)
+		cors.ExposeHeaders = slices.Clone(cors.ExposeHeaders)
+		cp.CORSConfig = &cors
+	}
+	if c.OpenAPIConfig != nil {
+		openAPI := *c.OpenAPIConfig
+		openAPI.Servers = slices.Clone(openAPI.Servers)
+		openAPI.SecuritySchemes = maps.Clone(openAPI.SecuritySchemes)
+		openAPI.DefaultSecurity = slices.Clone(openAPI.DefaultSecurity)
+		cp.OpenAPIConfig = &openAPI
+	}
+	if c.HealthConfig != nil {
+		health := *c.HealthConfig
+		cp.HealthConfig = &health
+	}
+	if c.MetricsConfig != nil {
+		metrics := *c.MetricsConfig
+		metrics.Buckets = slices.Clone(metrics.Buckets)
+		metrics.SizeBuckets = slices.Clone(metrics.SizeBuckets)
+		cp.MetricsConfig = &metrics
+	}
+	if c.RecoveryConfig != nil {
+		recovery := *c.RecoveryConfig
+		recovery.SensitiveHeaders = slices.Clone(recovery.SensitiveHeaders)
+		recovery.SensitiveQuery = slices.Clone(recovery.SensitiveQuery)
+		cp.RecoveryConfig = &recovery
+	}
+	if c.TimeoutConfig != nil {
+		timeout := *c.TimeoutConfig
+		timeout.Routes = maps.Clone(timeout.Routes)
+		cp.TimeoutConfig = &timeout
+	}
+	if c.AccessLogConfig != nil {
+		accessLog := *c.AccessLogConfig
+		accessLog.SkipRoutes = slices.Clone(accessLog.SkipRoutes)
+		accessLog.SampleRoutes = maps.Clone(accessLog.SampleRoutes)
+		accessLog.Headers = slices.Clone(accessLog.Headers)
+		accessLog.SensitiveHeaders = slices.Clone(accessLog.SensitiveHeaders)
+		accessLog.SensitiveQuery = slices.Clone(accessLog.SensitiveQuery)
+		accessLog.SensitiveFields = slices.Clone(accessLog.SensitiveFields)
+		cp.AccessLogConfig = &accessLog
+	}
+	if c.TLSConfig != nil {
+		tls := *c.TLSConfig
+		cp.TLSConfig = &tls
+	}
+	if c.Registry != nil {
+		registry := *c.Registry
+		registry.Tags = slices.Clone(registry.Tags)
+		registry.Metadata = maps.Clone(registry.Metadata)
+		cp.Registry = &registry
+	}
+	if c.AdminConfig != nil {
+		admin := *c.AdminConfig
+		admin.AllowIPs = slices.Clone(admin.AllowIPs)
+		admin.SensitiveKeys = slices.Clone(admin.SensitiveKeys)
+		cp.AdminConfig = &admin
+	}
+
+	cp.RateLimiters = slices.Clone(c.RateLimiters)
+	cp.ResponseHooks = slices.Clone(c.ResponseHooks)
+	cp.TrustedProxies = slices.Clone(c.TrustedProxies)
+	cp.RemoteIPHeaders = slices.Clone(c.RemoteIPHeaders)
+	if c.Listeners != nil {
+		cp.Listeners = make([]*ListenerConfig, len(c.Listeners))
+		for i, listener := range c.Listeners {
+			if listener != nil {
+				l := *listener
+				if l.TLS != nil {
+					tls := *l.TLS
+					l.TLS = &tls
+				}
+				listener = &l
+			}
+			cp.Listeners[i] = listener
+		}
+	}
+
+	return &cp
 }
 
 type ConfigOption func(*Config)
@@ -81,6 +216,30 @@ func WithRateLimit(rateLimiters ...*RateLimiter) ConfigOption {
 	}
 }
 
+func WithEnableJWT(enableJWT bool) ConfigOption {
+	return func(c *Config) {
+		c.EnableJWT = enableJWT
+	}
+}
+
+func WithJWTAuth(jwtAuth *JWTAuth) ConfigOption {
+	return func(c *Config) {
+		c.JWTAuth = jwtAuth
+	}
+}
+
+func WithEnableSign(enableSign bool) ConfigOption {
+	return func(c *Config) {
+		c.EnableSign = enableSign
+	}
+}
+
+func WithSignVerifier(signVerifier *SignVerifier) ConfigOption {
+	return func(c *Config) {
+		c.SignVerifier = signVerifier
+	}
+}
+
 func WithEnableEncrypt(enableEncrypt bool) ConfigOption {
 	return func(c *Config) {
 		c.EnableEncrypt = enableEncrypt
@@ -93,8 +252,212 @@ func WithEncryptor(encryptor Encryptor) ConfigOption {
 	}
 }
 
+func WithEncryption(encryption *Encryption) ConfigOption {
+	return func(c *Config) {
+		c.Encryption = encryption
+	}
+}
+
 func WithResponseHooks(responseHooks []ResponseHook) ConfigOption {
 	return func(c *Config) {
 		c.ResponseHooks = responseHooks
 	}
 }
+
+func WithEnableOpenAPI(enableOpenAPI bool) ConfigOption {
+	return func(c *Config) {
+		c.EnableOpenAPI = enableOpenAPI
+	}
+}
+
+func WithOpenAPIConfig(openAPIConfig *OpenAPIConfig) ConfigOption {
+	return func(c *Config) {
+		c.OpenAPIConfig = openAPIConfig
+	}
+}
+
+func WithEnableHealth(enableHealth bool) ConfigOption {
+	return func(c *Config) {
+		c.EnableHealth = enableHealth
+	}
+}
+
+func WithHealthConfig(healthConfig *HealthConfig) ConfigOption {
+	return func(c *Config) {
+		c.HealthConfig = healthConfig
+	}
+}
+
+func WithEnableMetrics(enableMetrics bool) ConfigOption {
+	return func(c *Config) {
+		c.EnableMetrics = enableMetrics
+	}
+}
+
+func WithMetricsConfig(metricsConfig *MetricsConfig) ConfigOption {
+	return func(c *Config) {
+		c.MetricsConfig = metricsConfig
+	}
+}
+
+func WithEnableIdempotency(enableIdempotency bool) ConfigOption {
+	return func(c *Config) {
+		c.EnableIdempotency = enableIdempotency
+	}
+}
+
+func WithIdempotency(idempotency *Idempotency) ConfigOption {
+	return func(c *Config) {
+		c.Idempotency = idempotency
+	}
+}
+
+func WithEnableCache(enableCache bool) ConfigOption {
+	return func(c *Config) {
+		c.EnableCache = enableCache
+	}
+}
+
+func WithResponseCache(responseCache *ResponseCache) ConfigOption {
+	return func(c *Config) {
+		c.ResponseCache = responseCache
+	}
+}
+
+func WithRecoveryConfig(recoveryConfig *RecoveryConfig) ConfigOption {
+	return func(c *Config) {
+		c.RecoveryConfig = recoveryConfig
+	}
+}
+
+func WithTrustedProxies(trustedProxies ...string) ConfigOption {
+	return func(c *Config) {
+		c.TrustedProxies = trustedProxies
+	}
+}
+
+func WithRemoteIPHeaders(remoteIPHeaders ...string) ConfigOption {
+	return func(c *Config) {
+		c.RemoteIPHeaders = remoteIPHeaders
+	}
+}
+
+func WithEnableIPFilter(enableIPFilter bool) ConfigOption {
+	return func(c *Config) {
+		c.EnableIPFilter = enableIPFilter
+	}
+}
+
+func WithIPFilter(ipFilter *IPFilter) ConfigOption {
+	return func(c *Config) {
+		c.IPFilter = ipFilter
+	}
+}
+
+func WithEnableTimeout(enableTimeout bool) ConfigOption {
+	return func(c *Config) {
+		c.EnableTimeout = enableTimeout
+	}
+}
+
+func WithTimeoutConfig(timeoutConfig *TimeoutConfig) ConfigOption {
+	return func(c *Config) {
+		c.TimeoutConfig = timeoutConfig
+	}
+}
+
+func WithEnableLoadShed(enableLoadShed bool) ConfigOption {
+	return func(c *Config) {
+		c.EnableLoadShed = enableLoadShed
+	}
+}
+
+func WithLoadShedder(loadShedder *AdaptiveLimiter) ConfigOption {
+	return func(c *Config) {
+		c.LoadShedder = loadShedder
+	}
+}
+
+func WithEnableAccessLog(enableAccessLog bool) ConfigOption {
+	return func(c *Config) {
+		c.EnableAccessLog = enableAccessLog
+	}
+}
+
+func WithAccessLogConfig(accessLogConfig *AccessLogConfig) ConfigOption {
+	return func(c *Config) {
+		c.AccessLogConfig = accessLogConfig
+	}
+}
+
+func WithEnableTLS(enableTLS bool) ConfigOption {
+	return func(c *Config) {
+		c.EnableTLS = enableTLS
+	}
+}
+
+func WithTLSConfig(tlsConfig *TLSConfig) ConfigOption {
+	return func(c *Config) {
+		c.TLSConfig = tlsConfig
+	}
+}
+
+func WithEnableH2C(enableH2C bool) ConfigOption {
+	return func(c *Config) {
+		c.EnableH2C = enableH2C
+	}
+}
+
+func WithListeners(listeners ...*ListenerConfig) ConfigOption {
+	return func(c *Config) {
+		c.Listeners = listeners
+	}
+}
+
+func WithShutdownTimeout(shutdownTimeout time.Duration) ConfigOption {
+	return func(c *Config) {
+		c.ShutdownTimeout = shutdownTimeout
+	}
+}
+
+func WithDrainDelay(drainDelay time.Duration) ConfigOption {
+	return func(c *Config) {
+		c.DrainDelay = drainDelay
+	}
+}
+
+func WithRegistry(registryConfig *RegistryConfig) ConfigOption {
+	return func(c *Config) {
+		c.Registry = registryConfig
+	}
+}
+
+func WithTraceIdGenerator(traceIdGenerator func() string) ConfigOption {
+	return func(c *Config) {
+		c.TraceIdGenerator = traceIdGenerator
+	}
+}
+
+func WithEnableAdmin(enableAdmin bool) ConfigOption {
+	return func(c *Config) {
+		c.EnableAdmin = enableAdmin
+	}
+}
+
+func WithAdminConfig(adminConfig *AdminConfig) ConfigOption {
+	return func(c *Config) {
+		c.AdminConfig = adminConfig
+	}
+}
+
+func WithEnableSession(enableSession bool) ConfigOption {
+	return func(c *Config) {
+		c.EnableSession = enableSession
+	}
+}
+
+func WithSessionManager(sessionManager *SessionManager) ConfigOption {
+	return func(c *Config) {
+		c.SessionManager = sessionManager
+	}
+}
@@ -2,6 +2,7 @@ package goohttp
 
 import (
 	"net/http"
+	"net/netip"
 
 	"github.com/gin-gonic/gin"
 )
@@ -18,34 +19,53 @@ func (c *Context) SetTraceId(traceId string) {
 	c.Context.Set("trace-id", traceId)
 }
 
+// ClientIP 获取客户端真实IP
+// 只有请求来自 Config.TrustedProxies 中的代理时才读取 X-Forwarded-For / X-Real-Ip，否则使用连接的对端地址
 func (c *Context) ClientIP() string {
-	if v := c.Context.GetHeader("X-Real-Ip"); v != "" {
-		return v
+	ip := c.Context.ClientIP()
+	if addr, err := netip.ParseAddr(ip); err == nil {
+		return addr.Unmap().String()
 	}
-	return c.Context.ClientIP()
+	return ip
+}
+
+// ResponseCode 获取已写入响应的业务状态码
+func (c *Context) ResponseCode() (int, bool) {
+	v, ok := c.Context.Get("response-code")
+	if !ok {
+		return 0, false
+	}
+	code, ok := v.(int)
+	return code, ok
+}
+
+// writeResponse 输出统一响应，并记录业务状态码供指标、日志使用
+func (c *Context) writeResponse(httpStatus int, resp *Response) {
+	c.Context.Set("response-code", resp.Code)
+	c.Context.JSON(httpStatus, resp)
 }
 
 func (c *Context) Success(data any) {
-	c.Context.JSON(SuccessCode, Success(c, data))
+	c.writeResponse(SuccessCode, Success(c, data))
 }
 
 func (c *Context) SuccessWithMessage(message string, data interface{}) {
-	c.Context.JSON(http.StatusOK, SuccessWithMessage(c, message, data))
+	c.writeResponse(http.StatusOK, SuccessWithMessage(c, message, data))
 }
 
 func (c *Context) Error(code int, message string) {
-	c.Context.JSON(http.StatusOK, Error(c, code, message))
+	c.writeResponse(http.StatusOK, Error(c, code, message))
 }
 
 func (c *Context) ErrorWithData(code int, message string, data interface{}) {
-	c.Context.JSON(http.StatusOK, ErrorWithData(c, code, message, data))
+	c.writeResponse(http.StatusOK, ErrorWithData(c, code, message, data))
 }
 
 func (c *Context) ErrorWithStatus(httpStatus int, code int, message string) {
-	c.Context.JSON(httpStatus, Error(c, code, message))
+	c.writeResponse(httpStatus, Error(c, code, message))
 }
 
 func (c *Context) Abort(httpStatus int, code int, message string) {
-	c.Context.JSON(httpStatus, Error(c, code, message))
+	c.writeResponse(httpStatus, Error(c, code, message))
 	c.Context.Abort()
 }
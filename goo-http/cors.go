@@ -12,7 +12,7 @@ var (
 	DefaultCORSConfig = &CORSConfig{
 		AllowOrigins:     []string{"*"},
 		AllowMethods:     []string{"GET", "POST", "PUT", "DELETE", "OPTIONS", "PATCH"},
-		AllowHeaders:     []string{"Origin", "Content-Type", "Accept", "Authorization", "X-Trace-Id", "User-Agent", "X-Timestamp", "X-AppId", "X-Sign"},
+		AllowHeaders:     []string{"Origin", "Content-Type", "Accept", "Authorization", "X-Trace-Id", "User-Agent", "X-Timestamp", "X-AppId", "X-Sign", "X-Nonce", "X-Sign-Method"},
 		ExposeHeaders:    []string{"X-Trace-Id"},
 		AllowCredentials: false,
 		MaxAge:           86400,
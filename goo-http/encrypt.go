@@ -2,14 +2,17 @@ package goohttp
 
 import (
 	"bytes"
+	"context"
 	"crypto/aes"
 	"crypto/cipher"
 	"crypto/rand"
+	"encoding/base64"
 	"errors"
 	"fmt"
 	"io"
 	"net/http"
 	"sync"
+	"time"
 
 	"github.com/gin-gonic/gin"
 )
@@ -27,6 +30,27 @@ type Encryptor interface {
 	GetCipher() cipher.AEAD
 }
 
+// AADEncryptor 支持附加数据的非 AEAD 加密器（如 SM4CBCEncryptor），附加数据参与认证但不加密
+// 信封格式通过附加数据认证密钥ID、算法和时间戳
+type AADEncryptor interface {
+	Encryptor
+	EncryptWithAAD(plaintext, additionalData []byte) ([]byte, error)
+	DecryptWithAAD(ciphertext, additionalData []byte) ([]byte, error)
+}
+
+// SessionEncryptor 会话加密器，每个请求使用独立的会话密钥（如 SM2HybridEncryptor）
+// EncryptMiddleware 使用解密请求时得到的会话加密器加密响应
+type SessionEncryptor interface {
+	Encryptor
+	// EncryptSession 加密并返回会话加密器（客户端使用），additionalData 参与认证，可以为 nil
+	EncryptSession(plaintext, additionalData []byte) ([]byte, Encryptor, error)
+	// DecryptSession 解密并返回会话加密器（服务端使用），additionalData 需要与加密时一致
+	DecryptSession(ciphertext, additionalData []byte) ([]byte, Encryptor, error)
+}
+
+// EncryptSessionHeader 没有请求体时（如 GET 请求），会话加密器通过该请求头传递会话密钥（Base64）
+const EncryptSessionHeader = "X-Encrypt-Session"
+
 type AES256GCMEncryptor struct {
 	aead cipher.AEAD
 	key  []byte
@@ -114,55 +138,395 @@ func (e *AES256GCMEncryptor) SetKey(key []byte) error {
 	return nil
 }
 
+// Algorithm 算法名称
+func (e *AES256GCMEncryptor) Algorithm() string {
+	return EncryptAlgAES256GCM
+}
+
+// Format 打印加密器时隐藏密钥，对所有格式化动词生效
+func (e *AES256GCMEncryptor) Format(f fmt.State, verb rune) {
+	fmt.Fprint(f, e.String())
+}
+
+// String 实现 fmt.Stringer
+func (e *AES256GCMEncryptor) String() string {
+	return "AES256GCMEncryptor{key:******}"
+}
+
+// 加密传输错误码
+const (
+	EncryptCodeReadFailed    = 4001 // 读取请求体失败
+	EncryptCodeDecryptFailed = 4002 // 解密失败
+	EncryptCodeInvalidTime   = 4005 // 信封时间戳超出时间窗口
+	EncryptCodeUnknownKey    = 4006 // 应用或密钥不存在
+	EncryptCodeKeyFailure    = 5002 // 获取密钥失败
+)
+
+var (
+	DefaultEncryptConfig = &EncryptConfig{
+		AppIdHeader: "X-AppId",
+		KeyIdHeader: "X-Encrypt-Key-Id",
+		Encoding:    EncryptEncodingRaw,
+		TimeWindow:  5 * time.Minute,
+	}
+)
+
+type EncryptConfig struct {
+	Encryptor   Encryptor                     // 加密器，未配置 KeyProvider 时所有请求使用同一个密钥
+	KeyProvider EncryptKeyProvider            // 按应用ID选择密钥，支持新旧密钥同时生效
+	AppIdHeader string                        // 应用ID请求头（默认 X-AppId），签名校验通过时使用签名的应用ID
+	KeyIdHeader string                        // 没有请求体时指定响应密钥ID的请求头（默认 X-Encrypt-Key-Id），为空时使用当前密钥
+	Envelope    bool                          // 是否使用信封格式（EncryptEnvelope），携带密钥ID、算法、nonce 和时间戳
+	Encoding    string                        // 传输编码：raw（默认）、base64、hex；信封格式中 raw 按 base64 处理
+	TimeWindow  time.Duration                 // 信封时间戳允许偏差（默认 5 分钟，小于 0 时不校验）
+	Routes      []string                      // 加密的路由，如 "POST /api/orders" 或 "/api/orders"，为空时对所有路由生效
+	SkipRoutes  []string                      // 不加密的路由，格式同 Routes
+	OnError     func(ctx *Context, err error) // 加解密出错时的回调（如记录日志、告警）
+}
+
+// Encryption 请求解密、响应加密
+// 响应使用解密请求的密钥加密，密钥轮换期间使用旧密钥的客户端仍可以正常解密响应
+type Encryption struct {
+	config     *EncryptConfig
+	codec      encryptCodec
+	routes     map[string]bool
+	skipRoutes map[string]bool
+}
+
+func NewEncryption(config *EncryptConfig) *Encryption {
+	if config == nil {
+		config = DefaultEncryptConfig
+	}
+
+	c := *config
+	if c.AppIdHeader == "" {
+		c.AppIdHeader = DefaultEncryptConfig.AppIdHeader
+	}
+	if c.KeyIdHeader == "" {
+		c.KeyIdHeader = DefaultEncryptConfig.KeyIdHeader
+	}
+	if c.Encoding == "" {
+		c.Encoding = DefaultEncryptConfig.Encoding
+	}
+	if c.TimeWindow == 0 {
+		c.TimeWindow = DefaultEncryptConfig.TimeWindow
+	}
+
+	return &Encryption{
+		config: &c,
+		codec: encryptCodec{
+			envelope: c.Envelope,
+			encoding: c.Encoding,
+		},
+		routes:     routeSet(c.Routes),
+		skipRoutes: routeSet(c.SkipRoutes),
+	}
+}
+
+func routeSet(routes []string) map[string]bool {
+	if len(routes) == 0 {
+		return nil
+	}
+	set := make(map[string]bool, len(routes))
+	for _, route := range routes {
+		set[route] = true
+	}
+	return set
+}
+
+// Wrap 路由级加密，包装处理函数
+//
+//	server.Post("/orders", enc.Wrap(createOrder))
+func (e *Encryption) Wrap(handler HandlerFunc) HandlerFunc {
+	return func(ctx *Context) {
+		e.serve(ctx, func() {
+			handler(ctx)
+		})
+	}
+}
+
+func (e *Encryption) match(ctx *Context) bool {
+	return e.Match(ctx.Request.Method, ctx.FullPath())
+}
+
+// Match 路由是否加密，route 为注册的路由（如 /api/orders/:id）
+func (e *Encryption) Match(method string, route string) bool {
+	if e.skipRoutes[method+" "+route] || e.skipRoutes[route] {
+		return false
+	}
+	if e.routes == nil {
+		return true
+	}
+	return e.routes[method+" "+route] || e.routes[route]
+}
+
+// Client 创建与服务端配置一致的客户端加解密，使用应用的当前密钥
+// SM2 混合加密的服务端密钥没有对方公钥，需要使用服务端公钥自行创建 EncryptClient
+func (e *Encryption) Client(ctx context.Context, appId string) (*EncryptClient, error) {
+	client := &EncryptClient{
+		AppId:    appId,
+		Envelope: e.config.Envelope,
+		Encoding: e.config.Encoding,
+	}
+
+	if e.config.KeyProvider == nil {
+		if e.config.Encryptor == nil {
+			return nil, ErrEncryptKeyNotFound
+		}
+		client.Key = &EncryptKey{Encryptor: e.config.Encryptor}
+		return client, nil
+	}
+
+	keys, err := e.config.KeyProvider.GetKeys(ctx, appId)
+	if err != nil {
+		return nil, err
+	}
+	if len(keys) == 0 {
+		return nil, ErrEncryptKeyNotFound
+	}
+	client.Key = keys[0]
+	return client, nil
+}
+
+// keys 当前请求可用的密钥
+func (e *Encryption) keys(ctx *Context) ([]*EncryptKey, error) {
+	if e.config.KeyProvider == nil {
+		if e.config.Encryptor == nil {
+			return nil, ErrEncryptKeyNotFound
+		}
+		return []*EncryptKey{{Encryptor: e.config.Encryptor}}, nil
+	}
+
+	appId := ctx.AppId()
+	if appId == "" {
+		appId = ctx.GetHeader(e.config.AppIdHeader)
+	}
+
+	keys, err := e.config.KeyProvider.GetKeys(ctx.Request.Context(), appId)
+	if err != nil {
+		return nil, err
+	}
+	if len(keys) == 0 {
+		return nil, ErrEncryptKeyNotFound
+	}
+	return keys, nil
+}
+
+func (e *Encryption) serve(ctx *Context, next func()) {
+	c := ctx.Context
+
+	keys, err := e.keys(ctx)
+	if err != nil {
+		e.error(ctx, err)
+		if errors.Is(err, ErrAppNotFound) || errors.Is(err, ErrEncryptKeyNotFound) {
+			ctx.Abort(http.StatusBadRequest, EncryptCodeUnknownKey, "密钥不存在")
+		} else {
+			ctx.Abort(http.StatusInternalServerError, EncryptCodeKeyFailure, "获取密钥失败")
+		}
+		return
+	}
+
+	// 响应使用的密钥：解密请求的密钥（会话加密器为本次的会话密钥），没有请求体时为当前密钥
+	responseKey := keys[0]
+
+	var payload []byte
+	if c.Request.Body != nil && c.Request.Body != http.NoBody && c.Request.ContentLength != 0 {
+		buf := getBuffer()
+		defer putBuffer(buf)
+
+		if _, err := io.Copy(buf, c.Request.Body); err != nil {
+			recordDecryptFailure(ctx, "read")
+			ctx.Abort(http.StatusBadRequest, EncryptCodeReadFailed, "获取请求数据失败")
+			return
+		}
+		payload = buf.Bytes()
+	}
+
+	session := c.GetHeader(EncryptSessionHeader)
+
+	switch {
+	case len(payload) > 0:
+		plaintext, key, err := e.codec.open(keys, payload, e.config.TimeWindow)
+		if err != nil {
+			e.decryptError(ctx, err)
+			return
+		}
+		responseKey = key
+
+		c.Request.Body = io.NopCloser(bytes.NewReader(plaintext))
+		c.Request.ContentLength = int64(len(plaintext))
+	case session != "":
+		data, err := base64.StdEncoding.DecodeString(session)
+		if err != nil {
+			e.decryptError(ctx, ErrInvalidCiphertext)
+			return
+		}
+		_, key, err := e.codec.open(keys, data, e.config.TimeWindow)
+		if err != nil {
+			e.decryptError(ctx, err)
+			return
+		}
+		responseKey = key
+	default:
+		if keyId := c.GetHeader(e.config.KeyIdHeader); keyId != "" {
+			if responseKey = findEncryptKey(keys, keyId); responseKey == nil {
+				e.error(ctx, ErrEncryptKeyNotFound)
+				ctx.Abort(http.StatusBadRequest, EncryptCodeUnknownKey, "密钥不存在")
+				return
+			}
+		}
+	}
+
+	// 包装响应写入器以加密响应
+	writer := &encryptResponseWriter{
+		ResponseWriter: c.Writer,
+		ctx:            c,
+		key:            responseKey,
+		codec:          e.codec,
+		buffer:         getBuffer(),
+	}
+	c.Writer = writer
+
+	defer func() {
+		c.Writer = writer.ResponseWriter
+		writer.release()
+	}()
+
+	next()
+
+	// 加密失败时返回 500，不输出明文
+	if err := writer.flush(); err != nil {
+		c.Error(err)
+		e.error(ctx, err)
+	}
+}
+
+func (e *Encryption) decryptError(ctx *Context, err error) {
+	e.error(ctx, err)
+
+	switch {
+	case errors.Is(err, ErrEnvelopeExpired):
+		recordDecryptFailure(ctx, "expired")
+		ctx.Abort(http.StatusBadRequest, EncryptCodeInvalidTime, "请求已过期")
+	case errors.Is(err, ErrEncryptKeyNotFound):
+		recordDecryptFailure(ctx, "key")
+		ctx.Abort(http.StatusBadRequest, EncryptCodeUnknownKey, "密钥不存在")
+	case errors.Is(err, ErrInvalidEnvelope):
+		recordDecryptFailure(ctx, "envelope")
+		ctx.Abort(http.StatusBadRequest, EncryptCodeDecryptFailed, "解密请求数据失败")
+	default:
+		recordDecryptFailure(ctx, "decrypt")
+		ctx.Abort(http.StatusBadRequest, EncryptCodeDecryptFailed, "解密请求数据失败")
+	}
+}
+
+func (e *Encryption) error(ctx *Context, err error) {
+	if e.config.OnError != nil {
+		e.config.OnError(ctx, err)
+	}
+}
+
 // 加密响应写入器
 type encryptResponseWriter struct {
 	gin.ResponseWriter
-	encryptor     Encryptor
+	ctx           *gin.Context
+	key           *EncryptKey
+	codec         encryptCodec
 	buffer        *bytes.Buffer
 	mu            sync.Mutex
 	headerWritten bool
 	statusCode    int
+	streaming     bool // 流式输出（SSE 等）不加密，直接输出
 }
 
 func (w *encryptResponseWriter) Write(data []byte) (int, error) {
 	w.mu.Lock()
 	defer w.mu.Unlock()
 
+	if w.streaming {
+		return w.ResponseWriter.Write(data)
+	}
+
 	// 写入到缓冲区
 	return w.buffer.Write(data)
 }
 
+func (w *encryptResponseWriter) WriteString(s string) (int, error) {
+	return w.Write([]byte(s))
+}
+
 func (w *encryptResponseWriter) WriteHeader(statusCode int) {
 	w.mu.Lock()
 	defer w.mu.Unlock()
 
+	if w.streaming {
+		w.ResponseWriter.WriteHeader(statusCode)
+		return
+	}
+
 	w.statusCode = statusCode
 	w.headerWritten = true
 }
 
+// Flush 只有流式输出（SSE 或 text/event-stream）时才直接输出，之后的数据不再缓冲和加密
+// 其他响应的刷新被忽略，处理函数返回后统一加密输出，避免明文泄露
+func (w *encryptResponseWriter) Flush() {
+	if !isStreaming(w.ctx) {
+		return
+	}
+
+	w.mu.Lock()
+	if !w.streaming {
+		w.streaming = true
+		if w.headerWritten {
+			w.ResponseWriter.WriteHeader(w.statusCode)
+		}
+		if w.buffer.Len() > 0 {
+			w.ResponseWriter.Write(w.buffer.Bytes())
+			w.buffer.Reset()
+		}
+	}
+	w.mu.Unlock()
+
+	w.ResponseWriter.Flush()
+}
+
 func (w *encryptResponseWriter) flush() error {
 	w.mu.Lock()
 	defer w.mu.Unlock()
 
+	if w.streaming {
+		return nil
+	}
+
 	if w.buffer.Len() == 0 {
+		// 304 等没有响应体的响应也要输出状态码
+		if w.headerWritten {
+			w.ResponseWriter.WriteHeader(w.statusCode)
+		}
 		return nil
 	}
 
 	// 如果还没有写入状态码，使用默认值
 	if !w.headerWritten {
-		w.statusCode = 200
-		w.ResponseWriter.WriteHeader(w.statusCode)
-	} else {
-		w.ResponseWriter.WriteHeader(w.statusCode)
+		w.statusCode = http.StatusOK
 	}
 
+	header := w.ResponseWriter.Header()
+	header.Del("Content-Length")
+
 	// 加密数据
-	data := w.buffer.Bytes()
-	encrypted, err := w.encryptor.Encrypt(data)
+	encrypted, _, err := w.codec.seal(w.key, w.buffer.Bytes())
 	if err != nil {
-		return err
+		header.Del("Content-Type")
+		w.ResponseWriter.WriteHeader(http.StatusInternalServerError)
+		w.ResponseWriter.WriteHeaderNow()
+		return fmt.Errorf("%w: %v", ErrEncryptFailed, err)
 	}
 
+	header.Set("Content-Type", w.codec.contentType())
+	w.ResponseWriter.WriteHeader(w.statusCode)
+
 	// 写入加密后的数据
 	_, err = w.ResponseWriter.Write(encrypted)
 	return err
@@ -175,46 +539,136 @@ func (w *encryptResponseWriter) release() {
 	}
 }
 
-func EncryptMiddleware(encryptor Encryptor) gin.HandlerFunc {
+// EncryptionMiddleware 加密中间件，只处理配置的路由
+func EncryptionMiddleware(enc *Encryption) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		ctx := &Context{Context: c}
 
-		// 解密请求体
-		if c.Request.Body != nil && c.Request.ContentLength > 0 {
-			buf := getBuffer()
-			defer putBuffer(buf)
+		if !enc.match(ctx) {
+			c.Next()
+			return
+		}
 
-			if _, err := io.Copy(buf, c.Request.Body); err != nil {
-				ctx.Abort(http.StatusBadRequest, 4001, "获取请求数据失败")
-				return
-			}
+		enc.serve(ctx, c.Next)
+	}
+}
 
-			decrypted, err := encryptor.Decrypt(buf.Bytes())
-			if err != nil {
-				ctx.Abort(http.StatusBadRequest, 4002, "解密请求数据失败")
-				return
-			}
+// EncryptMiddleware 使用单一加密器的加密中间件，不使用信封格式
+func EncryptMiddleware(encryptor Encryptor) gin.HandlerFunc {
+	return EncryptionMiddleware(NewEncryption(&EncryptConfig{
+		Encryptor: encryptor,
+	}))
+}
+
+// EncryptClient 客户端加解密，Envelope、Encoding 需要与服务端 EncryptConfig 一致
+//
+//	client := &goohttp.EncryptClient{AppId: "app1", Key: &goohttp.EncryptKey{Id: "2024-06", Encryptor: encryptor}, Envelope: true}
+//	respKey, err := client.EncryptRequest(req)
+//	resp, err := http.DefaultClient.Do(req)
+//	body, err := client.DecryptResponse(resp, respKey)
+type EncryptClient struct {
+	Key      *EncryptKey // 密钥（SM2 混合加密为服务端公钥）
+	AppId    string      // 应用ID，不为空时设置 X-AppId 请求头
+	Envelope bool        // 是否使用信封格式
+	Encoding string      // 传输编码：raw（默认）、base64、hex
+}
+
+func (c *EncryptClient) codec() encryptCodec {
+	return encryptCodec{envelope: c.Envelope, encoding: c.Encoding}
+}
 
-			c.Request.Body = io.NopCloser(bytes.NewReader(decrypted))
-			c.Request.ContentLength = int64(len(decrypted))
+// EncryptRequest 加密请求体，返回用于解密响应的密钥
+// 会话加密器（如 SM2HybridEncryptor）返回本次请求的会话密钥，没有请求体时通过 X-Encrypt-Session 请求头传递会话密钥
+func (c *EncryptClient) EncryptRequest(req *http.Request) (*EncryptKey, error) {
+	if c.Key == nil || c.Key.Encryptor == nil {
+		return nil, ErrEncryptKeyNotFound
+	}
+
+	var body []byte
+	if req.Body != nil {
+		var err error
+		if body, err = io.ReadAll(req.Body); err != nil {
+			return nil, err
 		}
+		req.Body.Close()
+	}
+
+	if c.AppId != "" {
+		req.Header.Set(DefaultEncryptConfig.AppIdHeader, c.AppId)
+	}
 
-		// 包装响应写入器以加密响应
-		writer := &encryptResponseWriter{
-			ResponseWriter: c.Writer,
-			encryptor:      encryptor,
-			buffer:         getBuffer(),
+	responseKey := c.Key
+
+	_, isSession := c.Key.Encryptor.(SessionEncryptor)
+	switch {
+	case len(body) > 0:
+		ciphertext, key, err := c.codec().seal(c.Key, body)
+		if err != nil {
+			return nil, err
+		}
+		body, responseKey = ciphertext, key
+	case isSession:
+		ciphertext, key, err := c.codec().seal(c.Key, nil)
+		if err != nil {
+			return nil, err
+		}
+		responseKey = key
+		req.Header.Set(EncryptSessionHeader, base64.StdEncoding.EncodeToString(ciphertext))
+	case c.Key.Id != "":
+		req.Header.Set(DefaultEncryptConfig.KeyIdHeader, c.Key.Id)
+	}
+
+	req.ContentLength = int64(len(body))
+	if len(body) == 0 {
+		req.Body = http.NoBody
+		req.GetBody = nil
+	} else {
+		req.Body = io.NopCloser(bytes.NewReader(body))
+		req.GetBody = func() (io.ReadCloser, error) {
+			return io.NopCloser(bytes.NewReader(body)), nil
 		}
-		c.Writer = writer
+	}
 
-		c.Next()
+	return responseKey, nil
+}
 
-		// 刷新加密数据
-		if err := writer.flush(); err != nil {
-			// 如果刷新失败，记录错误但不中断请求
+// DecryptResponse 解密响应体，返回明文，resp.Body 替换为明文
+func (c *EncryptClient) DecryptResponse(resp *http.Response, key *EncryptKey) ([]byte, error) {
+	body, err := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+
+	if len(body) > 0 {
+		if body, _, err = c.codec().open([]*EncryptKey{key}, body, -1); err != nil {
+			return nil, err
 		}
+	}
 
-		// 释放资源
-		writer.release()
+	resp.Body = io.NopCloser(bytes.NewReader(body))
+	resp.ContentLength = int64(len(body))
+
+	return body, nil
+}
+
+// EncryptRequest 客户端加密请求体，返回用于解密响应的加密器（不使用信封格式）
+// 会话加密器（如 SM2HybridEncryptor）返回本次请求的会话加密器，没有请求体时通过 X-Encrypt-Session 请求头传递会话密钥
+//
+//	respEncryptor, err := goohttp.EncryptRequest(req, encryptor)
+//	resp, err := http.DefaultClient.Do(req)
+//	body, err := goohttp.DecryptResponse(resp, respEncryptor)
+func EncryptRequest(req *http.Request, encryptor Encryptor) (Encryptor, error) {
+	client := &EncryptClient{Key: &EncryptKey{Encryptor: encryptor}}
+	key, err := client.EncryptRequest(req)
+	if err != nil {
+		return nil, err
 	}
+	return key.Encryptor, nil
+}
+
+// DecryptResponse 客户端解密响应体（不使用信封格式），返回明文，resp.Body 替换为明文
+func DecryptResponse(resp *http.Response, encryptor Encryptor) ([]byte, error) {
+	client := &EncryptClient{}
+	return client.DecryptResponse(resp, &EncryptKey{Encryptor: encryptor})
 }
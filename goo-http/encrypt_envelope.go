@@ -0,0 +1,291 @@
+package goohttp
+
+import (
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"time"
+)
+
+// 传输编码
+const (
+	EncryptEncodingRaw    = "raw"    // 二进制
+	EncryptEncodingBase64 = "base64" // 标准 Base64
+	EncryptEncodingHex    = "hex"    // 十六进制
+)
+
+var (
+	ErrInvalidEnvelope = errors.New("无效的加密信封")
+	ErrEnvelopeExpired = errors.New("加密信封已过期")
+)
+
+// EncryptEnvelope 加密信封（JSON）
+//
+//	{"v":1,"kid":"2024-06","alg":"SM4-GCM","ts":1718000000,"nonce":"...","data":"..."}
+//
+// "v\nkid\nalg\nts" 作为附加数据参与认证，篡改密钥ID、算法或时间戳都会导致解密失败：
+// AEAD 加密器（GetCipher 不为 nil）单独输出 nonce；会话加密器（SM2 混合加密）将其作为会话 SM4-GCM 的附加数据；
+// AADEncryptor（SM4-CBC）将其计入 MAC。后两种的 nonce / iv 包含在 data 中。
+// 不支持附加数据的自定义加密器无法认证信封头，不能使用信封格式
+type EncryptEnvelope struct {
+	Version   int    `json:"v"`               // 版本，固定为 1
+	KeyId     string `json:"kid,omitempty"`   // 密钥ID
+	Algorithm string `json:"alg,omitempty"`   // 算法
+	Timestamp int64  `json:"ts"`              // 加密时间（秒）
+	Nonce     string `json:"nonce,omitempty"` // nonce（按传输编码）
+	Data      string `json:"data"`            // 密文（按传输编码）
+}
+
+func (e *EncryptEnvelope) additionalData() []byte {
+	return []byte(strconv.Itoa(e.Version) + "\n" + e.KeyId + "\n" + e.Algorithm + "\n" + strconv.FormatInt(e.Timestamp, 10))
+}
+
+// encryptCodec 信封格式与传输编码
+type encryptCodec struct {
+	envelope bool
+	encoding string
+}
+
+func (c encryptCodec) encode(data []byte) []byte {
+	switch c.encoding {
+	case EncryptEncodingHex:
+		buf := make([]byte, hex.EncodedLen(len(data)))
+		hex.Encode(buf, data)
+		return buf
+	case EncryptEncodingBase64:
+		buf := make([]byte, base64.StdEncoding.EncodedLen(len(data)))
+		base64.StdEncoding.Encode(buf, data)
+		return buf
+	}
+	return data
+}
+
+func (c encryptCodec) decode(data []byte) ([]byte, error) {
+	switch c.encoding {
+	case EncryptEncodingHex:
+		buf := make([]byte, hex.DecodedLen(len(data)))
+		n, err := hex.Decode(buf, data)
+		if err != nil {
+			return nil, ErrInvalidCiphertext
+		}
+		return buf[:n], nil
+	case EncryptEncodingBase64:
+		buf := make([]byte, base64.StdEncoding.DecodedLen(len(data)))
+		n, err := base64.StdEncoding.Decode(buf, data)
+		if err != nil {
+			return nil, ErrInvalidCiphertext
+		}
+		return buf[:n], nil
+	}
+	return data, nil
+}
+
+// fieldCodec 信封字段的编码，raw 按 base64 处理
+func (c encryptCodec) fieldCodec() encryptCodec {
+	if c.encoding == EncryptEncodingHex {
+		return c
+	}
+	return encryptCodec{encoding: EncryptEncodingBase64}
+}
+
+// contentType 密文的 Content-Type
+func (c encryptCodec) contentType() string {
+	switch {
+	case c.envelope:
+		return "application/json; charset=utf-8"
+	case c.encoding == EncryptEncodingBase64 || c.encoding == EncryptEncodingHex:
+		return "text/plain; charset=utf-8"
+	}
+	return "application/octet-stream"
+}
+
+// seal 加密，会话加密器（客户端）返回本次的会话密钥，用于解密响应
+func (c encryptCodec) seal(key *EncryptKey, plaintext []byte) ([]byte, *EncryptKey, error) {
+	if !c.envelope {
+		ciphertext, responseKey, err := sealWith(key, plaintext, nil)
+		if err != nil {
+			return nil, nil, err
+		}
+		return c.encode(ciphertext), responseKey, nil
+	}
+
+	envelope := &EncryptEnvelope{
+		Version:   1,
+		KeyId:     key.Id,
+		Algorithm: key.Algorithm(),
+		Timestamp: time.Now().Unix(),
+	}
+	fields := c.fieldCodec()
+
+	var ciphertext []byte
+	responseKey := key
+	_, isSession := key.Encryptor.(SessionEncryptor)
+	if aead := key.Encryptor.GetCipher(); aead != nil && !isSession {
+		nonce := make([]byte, aead.NonceSize())
+		if _, err := io.ReadFull(rand.Reader, nonce); err != nil {
+			return nil, nil, fmt.Errorf("failed to generate nonce: %w", err)
+		}
+		ciphertext = aead.Seal(nil, nonce, plaintext, envelope.additionalData())
+		envelope.Nonce = string(fields.encode(nonce))
+	} else {
+		if !isSession && !isAADEncryptor(key.Encryptor) {
+			return nil, nil, fmt.Errorf("%w: encryptor does not support additional data", ErrEncryptFailed)
+		}
+		var err error
+		if ciphertext, responseKey, err = sealWith(key, plaintext, envelope.additionalData()); err != nil {
+			return nil, nil, err
+		}
+	}
+	envelope.Data = string(fields.encode(ciphertext))
+
+	data, err := json.Marshal(envelope)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return data, responseKey, nil
+}
+
+// sealWith 使用非 AEAD 方式加密（nonce / iv 包含在密文中），additionalData 为 nil 时不使用附加数据
+func sealWith(key *EncryptKey, plaintext []byte, additionalData []byte) ([]byte, *EncryptKey, error) {
+	if sessionEncryptor, ok := key.Encryptor.(SessionEncryptor); ok {
+		ciphertext, session, err := sessionEncryptor.EncryptSession(plaintext, additionalData)
+		if err != nil {
+			return nil, nil, err
+		}
+		return ciphertext, &EncryptKey{Id: key.Id, Encryptor: session}, nil
+	}
+
+	if aadEncryptor, ok := key.Encryptor.(AADEncryptor); ok && additionalData != nil {
+		ciphertext, err := aadEncryptor.EncryptWithAAD(plaintext, additionalData)
+		return ciphertext, key, err
+	}
+
+	ciphertext, err := key.Encryptor.Encrypt(plaintext)
+	return ciphertext, key, err
+}
+
+func isAADEncryptor(encryptor Encryptor) bool {
+	_, ok := encryptor.(AADEncryptor)
+	return ok
+}
+
+// open 解密，返回解密使用的密钥；会话加密器（服务端）返回本次的会话密钥，用于加密响应
+// keys 的第一个为当前密钥，信封没有 kid 或不使用信封时依次尝试所有密钥
+func (c encryptCodec) open(keys []*EncryptKey, data []byte, timeWindow time.Duration) ([]byte, *EncryptKey, error) {
+	if !c.envelope {
+		ciphertext, err := c.decode(data)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		for _, key := range keys {
+			if plaintext, responseKey, err := openWith(key, ciphertext, nil, nil); err == nil {
+				return plaintext, responseKey, nil
+			}
+		}
+		return nil, nil, ErrDecryptFailed
+	}
+
+	envelope := &EncryptEnvelope{}
+	if err := json.Unmarshal(data, envelope); err != nil || envelope.Version != 1 || envelope.Data == "" {
+		return nil, nil, ErrInvalidEnvelope
+	}
+
+	if timeWindow > 0 {
+		skew := time.Since(time.Unix(envelope.Timestamp, 0))
+		if skew > timeWindow || skew < -timeWindow {
+			return nil, nil, ErrEnvelopeExpired
+		}
+	}
+
+	candidates := keys
+	if envelope.KeyId != "" {
+		key := findEncryptKey(keys, envelope.KeyId)
+		if key == nil {
+			return nil, nil, ErrEncryptKeyNotFound
+		}
+		candidates = []*EncryptKey{key}
+	}
+
+	fields := c.fieldCodec()
+	ciphertext, err := fields.decode([]byte(envelope.Data))
+	if err != nil {
+		return nil, nil, ErrInvalidEnvelope
+	}
+	var nonce []byte
+	if envelope.Nonce != "" {
+		if nonce, err = fields.decode([]byte(envelope.Nonce)); err != nil {
+			return nil, nil, ErrInvalidEnvelope
+		}
+	}
+
+	matched := false
+	for _, key := range candidates {
+		if alg := key.Algorithm(); envelope.Algorithm != "" && alg != "" && alg != envelope.Algorithm {
+			continue
+		}
+		matched = true
+
+		if plaintext, responseKey, err := openWith(key, ciphertext, nonce, envelope.additionalData()); err == nil {
+			return plaintext, responseKey, nil
+		}
+	}
+	if !matched {
+		return nil, nil, ErrEncryptKeyNotFound
+	}
+
+	return nil, nil, ErrDecryptFailed
+}
+
+func openWith(key *EncryptKey, ciphertext []byte, nonce []byte, additionalData []byte) ([]byte, *EncryptKey, error) {
+	if nonce != nil {
+		aead := key.Encryptor.GetCipher()
+		if aead == nil || len(nonce) != aead.NonceSize() {
+			return nil, nil, ErrInvalidEnvelope
+		}
+		plaintext, err := aead.Open(nil, nonce, ciphertext, additionalData)
+		if err != nil {
+			return nil, nil, fmt.Errorf("%w: %v", ErrDecryptFailed, err)
+		}
+		return plaintext, key, nil
+	}
+
+	sessionEncryptor, isSession := key.Encryptor.(SessionEncryptor)
+	// AEAD 密钥的信封必须携带 nonce，否则附加数据不参与认证，篡改 kid、alg、ts 无法发现
+	if key.Encryptor.GetCipher() != nil && !isSession {
+		return nil, nil, ErrInvalidEnvelope
+	}
+
+	if isSession {
+		plaintext, session, err := sessionEncryptor.DecryptSession(ciphertext, additionalData)
+		if err != nil {
+			return nil, nil, err
+		}
+		return plaintext, &EncryptKey{Id: key.Id, Encryptor: session}, nil
+	}
+
+	if additionalData != nil {
+		// 信封头必须参与认证，不支持附加数据的加密器不能用于信封格式
+		aadEncryptor, ok := key.Encryptor.(AADEncryptor)
+		if !ok {
+			return nil, nil, ErrInvalidEnvelope
+		}
+		plaintext, err := aadEncryptor.DecryptWithAAD(ciphertext, additionalData)
+		if err != nil {
+			return nil, nil, err
+		}
+		return plaintext, key, nil
+	}
+
+	plaintext, err := key.Encryptor.Decrypt(ciphertext)
+	if err != nil {
+		return nil, nil, err
+	}
+	return plaintext, key, nil
+}
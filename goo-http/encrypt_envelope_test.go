@@ -0,0 +1,101 @@
+package goohttp
+
+import (
+	"bytes"
+	"crypto/cipher"
+	"encoding/json"
+	"errors"
+	"testing"
+)
+
+// 篡改信封时间戳后所有算法都应解密失败，防止重放时改写 ts 绕过时间窗口
+func TestEncryptEnvelopeAuthenticatesTimestamp(t *testing.T) {
+	aesKey, err := NewAES256GCMEncryptor(bytes.Repeat([]byte{1}, 32))
+	if err != nil {
+		t.Fatal(err)
+	}
+	sm4GCMKey, err := NewSM4GCMEncryptor(bytes.Repeat([]byte{1}, SM4KeySize))
+	if err != nil {
+		t.Fatal(err)
+	}
+	sm4CBCKey, err := NewSM4CBCEncryptor(bytes.Repeat([]byte{1}, SM4KeySize), bytes.Repeat([]byte{3}, 32))
+	if err != nil {
+		t.Fatal(err)
+	}
+	serverKey, err := GenerateSM2Key(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	sm2Client, err := NewSM2HybridEncryptor(nil, serverKey.Public())
+	if err != nil {
+		t.Fatal(err)
+	}
+	sm2Server, err := NewSM2HybridEncryptor(serverKey, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name    string
+		sealKey Encryptor
+		openKey Encryptor
+	}{
+		{"AES-256-GCM", aesKey, aesKey},
+		{"SM4-GCM", sm4GCMKey, sm4GCMKey},
+		{"SM4-CBC", sm4CBCKey, sm4CBCKey},
+		{"SM2-SM4-GCM", sm2Client, sm2Server},
+	}
+
+	codec := encryptCodec{envelope: true, encoding: EncryptEncodingBase64}
+	plaintext := []byte(`{"amount":100}`)
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			data, _, err := codec.seal(&EncryptKey{Id: "k1", Encryptor: tt.sealKey}, plaintext)
+			if err != nil {
+				t.Fatal(err)
+			}
+			openKeys := []*EncryptKey{{Id: "k1", Encryptor: tt.openKey}}
+
+			got, _, err := codec.open(openKeys, data, 0)
+			if err != nil {
+				t.Fatalf("open: %v", err)
+			}
+			if !bytes.Equal(got, plaintext) {
+				t.Fatalf("plaintext = %s, want %s", got, plaintext)
+			}
+
+			envelope := &EncryptEnvelope{}
+			if err := json.Unmarshal(data, envelope); err != nil {
+				t.Fatal(err)
+			}
+			envelope.Timestamp++
+			tampered, _ := json.Marshal(envelope)
+
+			if _, _, err := codec.open(openKeys, tampered, 0); !errors.Is(err, ErrDecryptFailed) {
+				t.Fatalf("tampered ts err = %v, want %v", err, ErrDecryptFailed)
+			}
+		})
+	}
+}
+
+// 不支持附加数据的自定义加密器无法认证信封头，加密和解密都应拒绝
+func TestEncryptEnvelopeRejectsEncryptorWithoutAAD(t *testing.T) {
+	codec := encryptCodec{envelope: true, encoding: EncryptEncodingBase64}
+	key := &EncryptKey{Id: "k1", Encryptor: plainEncryptor{}}
+
+	if _, _, err := codec.seal(key, []byte("hello")); !errors.Is(err, ErrEncryptFailed) {
+		t.Fatalf("seal err = %v, want %v", err, ErrEncryptFailed)
+	}
+
+	data, _ := json.Marshal(&EncryptEnvelope{Version: 1, KeyId: "k1", Data: "aGVsbG8="})
+	if _, _, err := codec.open([]*EncryptKey{key}, data, 0); err == nil {
+		t.Fatal("open envelope without AAD support succeeded")
+	}
+}
+
+type plainEncryptor struct{}
+
+func (plainEncryptor) Encrypt(plaintext []byte) ([]byte, error)  { return plaintext, nil }
+func (plainEncryptor) Decrypt(ciphertext []byte) ([]byte, error) { return ciphertext, nil }
+func (plainEncryptor) GetCipher() cipher.AEAD                    { return nil }
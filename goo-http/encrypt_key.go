@@ -0,0 +1,64 @@
+package goohttp
+
+import (
+	"context"
+	"errors"
+)
+
+const (
+	EncryptAlgAES256GCM = "AES-256-GCM"
+	EncryptAlgSM4GCM    = "SM4-GCM"
+	EncryptAlgSM4CBC    = "SM4-CBC"
+	EncryptAlgSM2SM4GCM = "SM2-SM4-GCM"
+)
+
+var (
+	ErrEncryptKeyNotFound = errors.New("加密密钥不存在")
+)
+
+// EncryptKey 加密密钥
+type EncryptKey struct {
+	Id        string    // 密钥ID（版本号），信封格式中写入 kid
+	Encryptor Encryptor // 加密器
+}
+
+// Algorithm 算法名称，加密器实现了 Algorithm() string 时返回该值，否则返回空字符串
+func (k *EncryptKey) Algorithm() string {
+	if a, ok := k.Encryptor.(interface{ Algorithm() string }); ok {
+		return a.Algorithm()
+	}
+	return ""
+}
+
+// EncryptKeyProvider 按应用提供加密密钥
+type EncryptKeyProvider interface {
+	// GetKeys 返回应用可用的密钥，第一个为当前密钥，其余为轮换期间仍可用于解密的旧密钥
+	GetKeys(ctx context.Context, appId string) ([]*EncryptKey, error)
+}
+
+// EncryptKeyProviderFunc 函数形式的密钥提供者
+type EncryptKeyProviderFunc func(ctx context.Context, appId string) ([]*EncryptKey, error)
+
+func (f EncryptKeyProviderFunc) GetKeys(ctx context.Context, appId string) ([]*EncryptKey, error) {
+	return f(ctx, appId)
+}
+
+// StaticEncryptKeyProvider 静态密钥（appId => 密钥列表，第一个为当前密钥）
+type StaticEncryptKeyProvider map[string][]*EncryptKey
+
+func (p StaticEncryptKeyProvider) GetKeys(ctx context.Context, appId string) ([]*EncryptKey, error) {
+	if keys, ok := p[appId]; ok && len(keys) > 0 {
+		return keys, nil
+	}
+	return nil, ErrAppNotFound
+}
+
+// findEncryptKey 按密钥ID查找密钥
+func findEncryptKey(keys []*EncryptKey, id string) *EncryptKey {
+	for _, key := range keys {
+		if key.Id == id {
+			return key
+		}
+	}
+	return nil
+}
@@ -0,0 +1,403 @@
+package goohttp
+
+import (
+	"crypto/cipher"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"sync"
+)
+
+// SM4GCMEncryptor SM4-GCM 加密器，密文格式与 AES256GCMEncryptor 相同：nonce(12) + 密文 + tag(16)
+type SM4GCMEncryptor struct {
+	aead cipher.AEAD
+	key  []byte
+	mu   sync.RWMutex
+}
+
+func NewSM4GCMEncryptor(key []byte) (*SM4GCMEncryptor, error) {
+	aead, err := newSM4GCM(key)
+	if err != nil {
+		return nil, err
+	}
+
+	return &SM4GCMEncryptor{
+		aead: aead,
+		key:  key,
+	}, nil
+}
+
+func newSM4GCM(key []byte) (cipher.AEAD, error) {
+	block, err := NewSM4Cipher(key)
+	if err != nil {
+		return nil, err
+	}
+
+	aead, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create GCM: %w", err)
+	}
+
+	return aead, nil
+}
+
+func (e *SM4GCMEncryptor) Encrypt(plaintext []byte) ([]byte, error) {
+	return e.seal(plaintext, nil)
+}
+
+func (e *SM4GCMEncryptor) Decrypt(ciphertext []byte) ([]byte, error) {
+	return e.open(ciphertext, nil)
+}
+
+// seal 加密，密文格式：nonce + 密文 + tag
+func (e *SM4GCMEncryptor) seal(plaintext, additionalData []byte) ([]byte, error) {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+
+	nonce := make([]byte, e.aead.NonceSize())
+	if _, err := io.ReadFull(rand.Reader, nonce); err != nil {
+		return nil, fmt.Errorf("failed to generate nonce: %w", err)
+	}
+
+	return e.aead.Seal(nonce, nonce, plaintext, additionalData), nil
+}
+
+func (e *SM4GCMEncryptor) open(ciphertext, additionalData []byte) ([]byte, error) {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+
+	nonceSize := e.aead.NonceSize()
+	if len(ciphertext) < nonceSize+e.aead.Overhead() {
+		return nil, ErrInvalidCiphertext
+	}
+
+	nonce, ciphertext := ciphertext[:nonceSize], ciphertext[nonceSize:]
+	plaintext, err := e.aead.Open(nil, nonce, ciphertext, additionalData)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrDecryptFailed, err)
+	}
+
+	return plaintext, nil
+}
+
+func (e *SM4GCMEncryptor) GetCipher() cipher.AEAD {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+	return e.aead
+}
+
+// Algorithm 算法名称
+func (e *SM4GCMEncryptor) Algorithm() string {
+	return EncryptAlgSM4GCM
+}
+
+// Format 打印加密器时隐藏密钥，对所有格式化动词生效
+func (e *SM4GCMEncryptor) Format(f fmt.State, verb rune) {
+	fmt.Fprint(f, e.String())
+}
+
+// String 实现 fmt.Stringer
+func (e *SM4GCMEncryptor) String() string {
+	return "SM4GCMEncryptor{key:******}"
+}
+
+func (e *SM4GCMEncryptor) SetKey(key []byte) error {
+	aead, err := newSM4GCM(key)
+	if err != nil {
+		return err
+	}
+
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.aead = aead
+	e.key = key
+
+	return nil
+}
+
+// SM4CBCEncryptor SM4-CBC + HMAC-SM3 加密器（先加密后 MAC），用于对接只支持 CBC 的系统，新系统优先使用 SM4-GCM
+// 密文格式：iv(16) + 密文（PKCS#7 填充）+ HMAC-SM3(32)
+// 解密时先以常数时间校验 MAC 再去除填充，篡改的密文在解密前即被拒绝，不会形成填充预言（padding oracle）
+type SM4CBCEncryptor struct {
+	block  cipher.Block
+	key    []byte
+	macKey []byte
+	mu     sync.RWMutex
+}
+
+// sm4CBCMACSize HMAC-SM3 长度
+const sm4CBCMACSize = SM3Size
+
+// NewSM4CBCEncryptor 创建 SM4-CBC 加密器，key 为 16 字节加密密钥，macKey 为至少 16 字节的 MAC 密钥（应与 key 不同）
+func NewSM4CBCEncryptor(key, macKey []byte) (*SM4CBCEncryptor, error) {
+	if len(macKey) < 16 {
+		return nil, ErrInvalidKey
+	}
+
+	block, err := NewSM4Cipher(key)
+	if err != nil {
+		return nil, err
+	}
+
+	return &SM4CBCEncryptor{
+		block:  block,
+		key:    key,
+		macKey: macKey,
+	}, nil
+}
+
+func (e *SM4CBCEncryptor) Encrypt(plaintext []byte) ([]byte, error) {
+	return e.seal(plaintext, nil)
+}
+
+func (e *SM4CBCEncryptor) Decrypt(ciphertext []byte) ([]byte, error) {
+	return e.open(ciphertext, nil)
+}
+
+// EncryptWithAAD 加密，additionalData 参与 MAC 计算
+func (e *SM4CBCEncryptor) EncryptWithAAD(plaintext, additionalData []byte) ([]byte, error) {
+	return e.seal(plaintext, additionalData)
+}
+
+// DecryptWithAAD 解密，additionalData 需要与加密时一致
+func (e *SM4CBCEncryptor) DecryptWithAAD(ciphertext, additionalData []byte) ([]byte, error) {
+	return e.open(ciphertext, additionalData)
+}
+
+// seal 加密并计算 MAC，附加数据只参与 MAC 计算
+func (e *SM4CBCEncryptor) seal(plaintext, additionalData []byte) ([]byte, error) {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+
+	padding := SM4BlockSize - len(plaintext)%SM4BlockSize
+
+	ciphertext := make([]byte, SM4BlockSize+len(plaintext)+padding, SM4BlockSize+len(plaintext)+padding+sm4CBCMACSize)
+	iv := ciphertext[:SM4BlockSize]
+	if _, err := io.ReadFull(rand.Reader, iv); err != nil {
+		return nil, fmt.Errorf("failed to generate iv: %w", err)
+	}
+
+	data := ciphertext[SM4BlockSize:]
+	copy(data, plaintext)
+	for i := len(plaintext); i < len(data); i++ {
+		data[i] = byte(padding)
+	}
+
+	cipher.NewCBCEncrypter(e.block, iv).CryptBlocks(data, data)
+
+	return append(ciphertext, e.mac(additionalData, ciphertext)...), nil
+}
+
+// open 校验 MAC 后解密
+func (e *SM4CBCEncryptor) open(ciphertext, additionalData []byte) ([]byte, error) {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+
+	size := len(ciphertext) - sm4CBCMACSize
+	if size < 2*SM4BlockSize || size%SM4BlockSize != 0 {
+		return nil, ErrInvalidCiphertext
+	}
+
+	body, tag := ciphertext[:size], ciphertext[size:]
+	if !hmac.Equal(e.mac(additionalData, body), tag) {
+		return nil, ErrDecryptFailed
+	}
+
+	iv := body[:SM4BlockSize]
+	data := make([]byte, size-SM4BlockSize)
+	cipher.NewCBCDecrypter(e.block, iv).CryptBlocks(data, body[SM4BlockSize:])
+
+	// MAC 校验通过后填充只会因加密方实现错误而无效
+	padding := int(data[len(data)-1])
+	if padding == 0 || padding > SM4BlockSize {
+		return nil, ErrDecryptFailed
+	}
+	valid := 1
+	for _, b := range data[len(data)-padding:] {
+		valid &= subtle.ConstantTimeByteEq(b, byte(padding))
+	}
+	if valid != 1 {
+		return nil, ErrDecryptFailed
+	}
+
+	return data[:len(data)-padding], nil
+}
+
+// mac HMAC-SM3(len(aad) ‖ aad ‖ iv ‖ 密文)，附加数据长度为 8 字节大端
+func (e *SM4CBCEncryptor) mac(additionalData, body []byte) []byte {
+	var size [8]byte
+	binary.BigEndian.PutUint64(size[:], uint64(len(additionalData)))
+
+	h := hmac.New(NewSM3, e.macKey)
+	h.Write(size[:])
+	h.Write(additionalData)
+	h.Write(body)
+	return h.Sum(nil)
+}
+
+// GetCipher CBC 模式不是 AEAD，返回 nil
+func (e *SM4CBCEncryptor) GetCipher() cipher.AEAD {
+	return nil
+}
+
+// Algorithm 算法名称
+func (e *SM4CBCEncryptor) Algorithm() string {
+	return EncryptAlgSM4CBC
+}
+
+// Format 打印加密器时隐藏密钥，对所有格式化动词生效
+func (e *SM4CBCEncryptor) Format(f fmt.State, verb rune) {
+	fmt.Fprint(f, e.String())
+}
+
+// String 实现 fmt.Stringer
+func (e *SM4CBCEncryptor) String() string {
+	return "SM4CBCEncryptor{key:******, mac_key:******}"
+}
+
+func (e *SM4CBCEncryptor) SetKey(key, macKey []byte) error {
+	if len(macKey) < 16 {
+		return ErrInvalidKey
+	}
+
+	block, err := NewSM4Cipher(key)
+	if err != nil {
+		return err
+	}
+
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.block = block
+	e.key = key
+	e.macKey = macKey
+
+	return nil
+}
+
+// SM2HybridEncryptor SM2 + SM4 混合加密器
+// 每次加密生成随机 SM4 会话密钥，会话密钥用对方的 SM2 公钥加密，数据用 SM4-GCM 加密
+// 密文格式：SM2 加密的会话密钥（C1C3C2，113 字节）+ SM4-GCM 密文（nonce + 密文 + tag）
+//
+// 服务端只需要自己的私钥：EncryptMiddleware 使用请求中的会话密钥加密响应，不需要客户端公钥
+// 客户端只需要服务端公钥：通过 EncryptRequest 加密请求，用返回的会话加密器解密响应
+type SM2HybridEncryptor struct {
+	privateKey *SM2PrivateKey
+	peerKey    *SM2PublicKey
+	mu         sync.RWMutex
+}
+
+// NewSM2HybridEncryptor 创建混合加密器
+// privateKey 自己的私钥，用于解密；peerKey 对方的公钥，用于加密。服务端通常只设置 privateKey，客户端只设置 peerKey
+func NewSM2HybridEncryptor(privateKey *SM2PrivateKey, peerKey *SM2PublicKey) (*SM2HybridEncryptor, error) {
+	if privateKey == nil && peerKey == nil {
+		return nil, ErrInvalidKey
+	}
+
+	return &SM2HybridEncryptor{
+		privateKey: privateKey,
+		peerKey:    peerKey,
+	}, nil
+}
+
+func (e *SM2HybridEncryptor) Encrypt(plaintext []byte) ([]byte, error) {
+	ciphertext, _, err := e.EncryptSession(plaintext, nil)
+	return ciphertext, err
+}
+
+func (e *SM2HybridEncryptor) Decrypt(ciphertext []byte) ([]byte, error) {
+	plaintext, _, err := e.DecryptSession(ciphertext, nil)
+	return plaintext, err
+}
+
+// EncryptSession 生成会话密钥并加密，返回的会话加密器用于解密对方使用同一会话密钥加密的响应
+// additionalData 作为 SM4-GCM 的附加数据
+func (e *SM2HybridEncryptor) EncryptSession(plaintext, additionalData []byte) ([]byte, Encryptor, error) {
+	e.mu.RLock()
+	peerKey := e.peerKey
+	e.mu.RUnlock()
+
+	if peerKey == nil {
+		return nil, nil, fmt.Errorf("%w: %v", ErrEncryptFailed, ErrSM2InvalidPublicKey)
+	}
+
+	key := make([]byte, SM4KeySize)
+	if _, err := io.ReadFull(rand.Reader, key); err != nil {
+		return nil, nil, fmt.Errorf("failed to generate session key: %w", err)
+	}
+
+	wrapped, err := SM2Encrypt(rand.Reader, peerKey, key)
+	if err != nil {
+		return nil, nil, fmt.Errorf("%w: %v", ErrEncryptFailed, err)
+	}
+
+	session, err := NewSM4GCMEncryptor(key)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	data, err := session.seal(plaintext, additionalData)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return append(wrapped, data...), session, nil
+}
+
+// DecryptSession 解密并返回会话加密器，用于使用同一会话密钥加密响应
+func (e *SM2HybridEncryptor) DecryptSession(ciphertext, additionalData []byte) ([]byte, Encryptor, error) {
+	e.mu.RLock()
+	privateKey := e.privateKey
+	e.mu.RUnlock()
+
+	if privateKey == nil {
+		return nil, nil, fmt.Errorf("%w: %v", ErrDecryptFailed, ErrSM2InvalidPrivateKey)
+	}
+
+	wrappedSize := SM2Overhead + SM4KeySize
+	if len(ciphertext) < wrappedSize {
+		return nil, nil, ErrInvalidCiphertext
+	}
+
+	key, err := SM2Decrypt(privateKey, ciphertext[:wrappedSize])
+	if err != nil {
+		return nil, nil, fmt.Errorf("%w: %v", ErrDecryptFailed, err)
+	}
+
+	session, err := NewSM4GCMEncryptor(key)
+	if err != nil {
+		return nil, nil, fmt.Errorf("%w: %v", ErrDecryptFailed, err)
+	}
+
+	plaintext, err := session.open(ciphertext[wrappedSize:], additionalData)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return plaintext, session, nil
+}
+
+// GetCipher 混合加密每次使用不同的会话密钥，返回 nil
+func (e *SM2HybridEncryptor) GetCipher() cipher.AEAD {
+	return nil
+}
+
+// Algorithm 算法名称
+func (e *SM2HybridEncryptor) Algorithm() string {
+	return EncryptAlgSM2SM4GCM
+}
+
+// SetKeys 更新密钥，参数为 nil 时保留原密钥
+func (e *SM2HybridEncryptor) SetKeys(privateKey *SM2PrivateKey, peerKey *SM2PublicKey) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	if privateKey != nil {
+		e.privateKey = privateKey
+	}
+	if peerKey != nil {
+		e.peerKey = peerKey
+	}
+}
@@ -0,0 +1,128 @@
+package goohttp
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestSM4CBCEncryptorRoundTrip(t *testing.T) {
+	encryptor, err := NewSM4CBCEncryptor(bytes.Repeat([]byte{1}, SM4KeySize), bytes.Repeat([]byte{3}, 32))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, size := range []int{0, 1, 15, 16, 17, 1000} {
+		message := bytes.Repeat([]byte{0x5a}, size)
+
+		ciphertext, err := encryptor.Encrypt(message)
+		if err != nil {
+			t.Fatal(err)
+		}
+		plaintext, err := encryptor.Decrypt(ciphertext)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !bytes.Equal(plaintext, message) {
+			t.Fatalf("size %d: plaintext = %x, want %x", size, plaintext, message)
+		}
+	}
+
+	other, _ := NewSM4CBCEncryptor(bytes.Repeat([]byte{2}, SM4KeySize), bytes.Repeat([]byte{3}, 32))
+	ciphertext, _ := encryptor.Encrypt([]byte("hello"))
+	if _, err := other.Decrypt(ciphertext); err != ErrDecryptFailed {
+		t.Fatalf("wrong key err = %v, want %v", err, ErrDecryptFailed)
+	}
+
+	if _, err := NewSM4CBCEncryptor(bytes.Repeat([]byte{1}, SM4KeySize), nil); err != ErrInvalidKey {
+		t.Fatalf("missing mac key err = %v, want %v", err, ErrInvalidKey)
+	}
+}
+
+// 篡改 iv、密文或 MAC 的任意字节都在去除填充前被拒绝，错误与填充无关
+func TestSM4CBCEncryptorRejectsTampering(t *testing.T) {
+	encryptor, err := NewSM4CBCEncryptor(bytes.Repeat([]byte{1}, SM4KeySize), bytes.Repeat([]byte{3}, 32))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ciphertext, err := encryptor.Encrypt([]byte("0123456789abcdef0123"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for i := range ciphertext {
+		tampered := append([]byte(nil), ciphertext...)
+		tampered[i] ^= 1
+		if _, err := encryptor.Decrypt(tampered); err != ErrDecryptFailed {
+			t.Fatalf("byte %d: err = %v, want %v", i, err, ErrDecryptFailed)
+		}
+	}
+
+	if _, err := encryptor.Decrypt(ciphertext[:len(ciphertext)-1]); err != ErrInvalidCiphertext {
+		t.Fatalf("truncated err = %v, want %v", err, ErrInvalidCiphertext)
+	}
+}
+
+func TestSM2HybridEncryptorRoundTrip(t *testing.T) {
+	serverKey, err := GenerateSM2Key(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	clientKey, err := GenerateSM2Key(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	client, err := NewSM2HybridEncryptor(clientKey, serverKey.Public())
+	if err != nil {
+		t.Fatal(err)
+	}
+	server, err := NewSM2HybridEncryptor(serverKey, clientKey.Public())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// 客户端用服务端公钥加密请求
+	request := []byte(`{"amount":100}`)
+	ciphertext, clientSession, err := client.EncryptSession(request, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	plaintext, serverSession, err := server.DecryptSession(ciphertext, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(plaintext, request) {
+		t.Fatalf("request = %s, want %s", plaintext, request)
+	}
+
+	// 服务端用会话密钥加密响应，客户端用同一会话密钥解密
+	response := []byte(`{"code":0}`)
+	encrypted, err := serverSession.Encrypt(response)
+	if err != nil {
+		t.Fatal(err)
+	}
+	decrypted, err := clientSession.Decrypt(encrypted)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(decrypted, response) {
+		t.Fatalf("response = %s, want %s", decrypted, response)
+	}
+
+	// 服务端反向加密，客户端用自己的私钥解密
+	ciphertext, err = server.Encrypt(response)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if plaintext, err = client.Decrypt(ciphertext); err != nil || !bytes.Equal(plaintext, response) {
+		t.Fatalf("client.Decrypt = %s, %v", plaintext, err)
+	}
+
+	// 篡改密文解密失败
+	ciphertext[len(ciphertext)-1] ^= 1
+	if _, err := client.Decrypt(ciphertext); err == nil {
+		t.Fatal("tampered ciphertext decrypted")
+	}
+}
@@ -0,0 +1,169 @@
+package goohttptest
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+
+	goohttp "v2.googo.io/goo-http"
+)
+
+// Request 测试请求
+type Request struct {
+	server *Server
+	method string
+	path   string
+	header http.Header
+	query  url.Values
+	body   []byte
+	plain  bool
+}
+
+// NewRequest 创建请求，path 可以包含查询参数
+func (s *Server) NewRequest(method string, path string) *Request {
+	r := &Request{
+		server: s,
+		method: method,
+		path:   path,
+		header: make(http.Header),
+		query:  make(url.Values),
+	}
+
+	s.mu.RLock()
+	for key, value := range s.headers {
+		r.header.Set(key, value)
+	}
+	if s.appId != "" {
+		r.header.Set("X-AppId", s.appId)
+	}
+	s.mu.RUnlock()
+
+	return r
+}
+
+// Do 发送请求，body 为 nil 时没有请求体，[]byte、string 原样发送，其他类型编码为 JSON
+func (s *Server) Do(method string, path string, body any) *Response {
+	s.t.Helper()
+
+	r := s.NewRequest(method, path)
+	switch v := body.(type) {
+	case nil:
+	case []byte:
+		r.Body(v, "")
+	case string:
+		r.Body([]byte(v), "")
+	default:
+		r.JSON(v)
+	}
+	return r.Do()
+}
+
+// Header 设置请求头
+func (r *Request) Header(key string, value string) *Request {
+	r.header.Set(key, value)
+	return r
+}
+
+// Query 添加查询参数
+func (r *Request) Query(key string, value string) *Request {
+	r.query.Add(key, value)
+	return r
+}
+
+// JSON 使用 JSON 请求体
+func (r *Request) JSON(v any) *Request {
+	r.server.t.Helper()
+
+	data, err := json.Marshal(v)
+	if err != nil {
+		r.server.t.Fatalf("goohttptest: encode json body: %v", err)
+	}
+	return r.Body(data, "application/json")
+}
+
+// Form 使用表单请求体
+func (r *Request) Form(values url.Values) *Request {
+	return r.Body([]byte(values.Encode()), "application/x-www-form-urlencoded")
+}
+
+// Body 使用原始请求体，contentType 为空时不设置 Content-Type
+func (r *Request) Body(data []byte, contentType string) *Request {
+	r.body = data
+	if contentType != "" {
+		r.header.Set("Content-Type", contentType)
+	}
+	return r
+}
+
+// Plain 不加密请求、不解密响应（测试加密失败等场景）
+func (r *Request) Plain() *Request {
+	r.plain = true
+	return r
+}
+
+// Do 在进程内处理请求
+func (r *Request) Do() *Response {
+	t := r.server.t
+	t.Helper()
+
+	target := r.path
+	if len(r.query) > 0 {
+		sep := "?"
+		if strings.Contains(target, "?") {
+			sep = "&"
+		}
+		target += sep + r.query.Encode()
+	}
+
+	var body io.Reader
+	if r.body != nil {
+		body = bytes.NewReader(r.body)
+	}
+	req := httptest.NewRequest(r.method, target, body)
+	for key, values := range r.header {
+		req.Header[key] = values
+	}
+
+	var client *goohttp.EncryptClient
+	if !r.plain {
+		c, err := r.server.encryptClient(req.Context(), r.method, req.URL.Path, req.Header.Get("X-AppId"))
+		if err != nil {
+			t.Fatalf("goohttptest: encrypt client: %v", err)
+		}
+		client = c
+	}
+
+	var responseKey *goohttp.EncryptKey
+	if client != nil {
+		key, err := client.EncryptRequest(req)
+		if err != nil {
+			t.Fatalf("goohttptest: encrypt request: %v", err)
+		}
+		responseKey = key
+	}
+
+	recorder := httptest.NewRecorder()
+	r.server.ServeHTTP(recorder, req)
+
+	result := recorder.Result()
+	resp := &Response{
+		StatusCode: result.StatusCode,
+		Header:     result.Header,
+		Body:       recorder.Body.Bytes(),
+
+		traceIdHeader: r.server.traceIdHeader(),
+	}
+
+	// 加密路由解密响应；中间件直接返回的错误响应（如解密失败）和流式输出是明文
+	if client != nil && len(resp.Body) > 0 && !strings.HasPrefix(resp.Header.Get("Content-Type"), "text/event-stream") {
+		if plaintext, err := client.DecryptResponse(result, responseKey); err == nil {
+			resp.Body = plaintext
+		}
+	}
+
+	return resp
+}
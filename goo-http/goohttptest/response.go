@@ -0,0 +1,112 @@
+package goohttptest
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	goohttp "v2.googo.io/goo-http"
+)
+
+// Response 测试响应，加密路由的 Body 为解密后的明文
+type Response struct {
+	StatusCode    int
+	Header        http.Header
+	Body          []byte
+	traceIdHeader string
+}
+
+// envelope 统一响应格式，Data 延迟解码到调用方的类型
+type envelope struct {
+	Code    int             `json:"code"`
+	Message string          `json:"message"`
+	Data    json.RawMessage `json:"data"`
+	TraceId string          `json:"trace_id"`
+}
+
+func (r *Response) envelope(t testing.TB) *envelope {
+	t.Helper()
+
+	env := &envelope{}
+	if err := json.Unmarshal(r.Body, env); err != nil {
+		t.Fatalf("goohttptest: response is not a goohttp.Response (status %d): %v\n%s", r.StatusCode, err, r.Body)
+	}
+	return env
+}
+
+// TraceId 响应的 TraceId，从服务器配置的 TraceId 请求头读取
+func (r *Response) TraceId() string {
+	return r.Header.Get(r.traceIdHeader)
+}
+
+// Envelope 解码统一响应格式，data 不为 nil 时将 Data 解码到 data
+func (r *Response) Envelope(t testing.TB, data any) *goohttp.Response {
+	t.Helper()
+
+	env := r.envelope(t)
+	if data != nil && len(env.Data) > 0 {
+		if err := json.Unmarshal(env.Data, data); err != nil {
+			t.Fatalf("goohttptest: decode data: %v\n%s", err, env.Data)
+		}
+	}
+
+	return &goohttp.Response{
+		Code:    env.Code,
+		Message: env.Message,
+		Data:    data,
+		TraceId: env.TraceId,
+	}
+}
+
+// JSON 将响应体解码到 v（非统一响应格式的接口，如健康检查）
+func (r *Response) JSON(t testing.TB, v any) {
+	t.Helper()
+
+	if err := json.Unmarshal(r.Body, v); err != nil {
+		t.Fatalf("goohttptest: decode json: %v\n%s", err, r.Body)
+	}
+}
+
+// AssertStatus 断言 HTTP 状态码
+func (r *Response) AssertStatus(t testing.TB, status int) *Response {
+	t.Helper()
+
+	if r.StatusCode != status {
+		t.Fatalf("goohttptest: expected status %d, got %d\n%s", status, r.StatusCode, r.Body)
+	}
+	return r
+}
+
+// AssertSuccess 断言业务成功（code 为 0），data 不为 nil 时将 Data 解码到 data
+func (r *Response) AssertSuccess(t testing.TB, data any) *Response {
+	t.Helper()
+
+	env := r.envelope(t)
+	if env.Code != goohttp.SuccessCode {
+		t.Fatalf("goohttptest: expected success, got code %d: %s (status %d, trace-id %s)", env.Code, env.Message, r.StatusCode, env.TraceId)
+	}
+	r.Envelope(t, data)
+	return r
+}
+
+// AssertCode 断言业务码
+func (r *Response) AssertCode(t testing.TB, code int) *Response {
+	t.Helper()
+
+	env := r.envelope(t)
+	if env.Code != code {
+		t.Fatalf("goohttptest: expected code %d, got %d: %s (status %d, trace-id %s)", code, env.Code, env.Message, r.StatusCode, env.TraceId)
+	}
+	return r
+}
+
+// AssertError 断言业务错误的业务码和 HTTP 状态码
+func (r *Response) AssertError(t testing.TB, err *goohttp.BizError) *Response {
+	t.Helper()
+
+	r.AssertCode(t, err.Code)
+	if err.HTTPStatus != 0 {
+		r.AssertStatus(t, err.HTTPStatus)
+	}
+	return r
+}
@@ -0,0 +1,111 @@
+package goohttptest
+
+import (
+	"context"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	goohttp "v2.googo.io/goo-http"
+)
+
+// TraceIdPrefix 测试服务器生成的 TraceId 前缀，按请求顺序生成 trace-1、trace-2……
+const TraceIdPrefix = "trace-"
+
+// Server 测试服务器
+// 配置与其他 Server 隔离，请求在进程内处理，不监听端口；加密路由自动加密请求、解密响应
+type Server struct {
+	*goohttp.Server
+	t        testing.TB
+	config   *goohttp.Config
+	traceSeq atomic.Int64
+	mu       sync.RWMutex
+	appId    string
+	client   *goohttp.EncryptClient
+	headers  map[string]string
+}
+
+// New 创建测试服务器，默认关闭请求日志，TraceId 按请求顺序生成（可以通过 goohttp.WithTraceIdGenerator 覆盖）
+func New(t testing.TB, opts ...goohttp.ConfigOption) *Server {
+	t.Helper()
+	gin.SetMode(gin.TestMode)
+
+	s := &Server{
+		t:       t,
+		headers: make(map[string]string),
+	}
+
+	defaults := []goohttp.ConfigOption{
+		goohttp.WithEnableLog(false),
+		goohttp.WithEnableAccessLog(false),
+		goohttp.WithTraceIdGenerator(func() string {
+			return TraceIdPrefix + strconv.FormatInt(s.traceSeq.Add(1), 10)
+		}),
+	}
+
+	s.Server = goohttp.New(append(defaults, opts...)...)
+	// 记录生效的配置，用于判断加密路由和读取 TraceId
+	s.config = s.Server.Config()
+	return s
+}
+
+// SetAppId 设置请求的应用 ID（X-AppId），按应用选择加密密钥时使用该应用的当前密钥
+func (s *Server) SetAppId(appId string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.appId = appId
+}
+
+// SetEncryptClient 设置客户端加解密
+// 默认根据服务端的加密配置创建；SM2 混合加密、路由级加密（Encryption.Wrap）需要手动设置
+func (s *Server) SetEncryptClient(client *goohttp.EncryptClient) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.client = client
+}
+
+// SetHeader 设置所有请求默认携带的请求头（如 Authorization）
+func (s *Server) SetHeader(key string, value string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.headers[key] = value
+}
+
+// encryptClient 请求使用的客户端加解密，路由不加密时返回 nil
+func (s *Server) encryptClient(ctx context.Context, method string, path string, appId string) (*goohttp.EncryptClient, error) {
+	s.mu.RLock()
+	client := s.client
+	s.mu.RUnlock()
+
+	if !s.config.EnableEncrypt {
+		return client, nil
+	}
+
+	if enc := s.config.Encryption; enc != nil {
+		if route, ok := s.Route(method, path); ok && !enc.Match(method, route.Path()) {
+			return nil, nil
+		}
+		if client != nil {
+			return client, nil
+		}
+		return enc.Client(ctx, appId)
+	}
+
+	if client != nil {
+		return client, nil
+	}
+	if s.config.Encryptor != nil {
+		return &goohttp.EncryptClient{Key: &goohttp.EncryptKey{Encryptor: s.config.Encryptor}}, nil
+	}
+	return nil, nil
+}
+
+// traceIdHeader 服务器使用的 TraceId 请求头
+func (s *Server) traceIdHeader() string {
+	if s.config.TraceIdHeader != "" {
+		return s.config.TraceIdHeader
+	}
+	return goohttp.DefaultTraceIdHeader
+}
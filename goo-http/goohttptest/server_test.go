@@ -0,0 +1,56 @@
+package goohttptest
+
+import (
+	"net/http"
+	"testing"
+
+	goohttp "v2.googo.io/goo-http"
+)
+
+// 配置选项只应用一次，有副作用的选项（如计数、注册回调）不会重复执行
+func TestNewAppliesOptionsOnce(t *testing.T) {
+	calls := 0
+	New(t, func(*goohttp.Config) { calls++ })
+
+	if calls != 1 {
+		t.Fatalf("option applied %d times, want 1", calls)
+	}
+}
+
+// TraceId 从配置的请求头读取，而不是默认请求头
+func TestResponseTraceIdUsesConfiguredHeader(t *testing.T) {
+	s := New(t, goohttp.WithTraceIdHeader("X-Request-Id"))
+	s.Get("/ping", func(ctx *goohttp.Context) {
+		ctx.Success(nil)
+	})
+
+	resp := s.Do(http.MethodGet, "/ping", nil).AssertStatus(t, http.StatusOK)
+	if got := resp.TraceId(); got != TraceIdPrefix+"1" {
+		t.Fatalf("TraceId() = %q, want %q", got, TraceIdPrefix+"1")
+	}
+	if got := resp.Header.Get(goohttp.DefaultTraceIdHeader); got != "" {
+		t.Fatalf("default trace header = %q, want empty", got)
+	}
+}
+
+// 参数路由先注册时，静态路由仍然按 gin 的优先级匹配
+func TestServerStaticRouteBeforeParam(t *testing.T) {
+	s := New(t)
+	s.Get("/users/:id", func(ctx *goohttp.Context) {
+		ctx.Success("id")
+	})
+	s.Get("/users/me", func(ctx *goohttp.Context) {
+		ctx.Success("me")
+	})
+
+	var got string
+	s.Do(http.MethodGet, "/users/me", nil).AssertSuccess(t, &got)
+	if got != "me" {
+		t.Fatalf("GET /users/me handled by %q, want %q", got, "me")
+	}
+
+	route, ok := s.Route(http.MethodGet, "/users/me")
+	if !ok || route.Path() != "/users/me" {
+		t.Fatalf("Route(GET /users/me) = %v, %v", route, ok)
+	}
+}
@@ -0,0 +1,292 @@
+package goohttp
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"reflect"
+	"strings"
+
+	"github.com/gin-gonic/gin/binding"
+	"github.com/go-playground/validator/v10"
+	"google.golang.org/grpc/codes"
+)
+
+// 参数绑定/校验错误码
+const (
+	HandleCodeInvalidParams   = 4000 // 参数错误
+	HandleCodeInternalFailure = 5000 // 服务器内部错误
+)
+
+var (
+	ErrInvalidParams  = errors.New("参数错误")
+	ErrInternalServer = errors.New("服务器内部错误")
+)
+
+var (
+	BizErrInvalidParams   = RegisterBizError(HandleCodeInvalidParams, "goohttp.invalid_params", http.StatusBadRequest, codes.InvalidArgument)
+	BizErrInternalFailure = RegisterBizError(HandleCodeInternalFailure, "goohttp.internal_failure", http.StatusInternalServerError, codes.Internal)
+)
+
+func init() {
+	RegisterMessages("zh-CN", map[string]string{
+		"goohttp.invalid_params":   ErrInvalidParams.Error(),
+		"goohttp.internal_failure": ErrInternalServer.Error(),
+	})
+	RegisterMessages("en", map[string]string{
+		"goohttp.invalid_params":   "Invalid parameters",
+		"goohttp.internal_failure": "Internal server error",
+	})
+}
+
+// HTTPError 携带 HTTP 状态码和业务码的错误
+type HTTPError struct {
+	Status  int    // HTTP 状态码
+	Code    int    // 业务码
+	Message string // 错误消息
+	Data    any    // 附加数据
+	Err     error  // 原始错误
+}
+
+func NewHTTPError(status int, code int, message string) *HTTPError {
+	return &HTTPError{
+		Status:  status,
+		Code:    code,
+		Message: message,
+	}
+}
+
+func (e *HTTPError) Error() string {
+	if e.Err != nil {
+		return e.Message + ": " + e.Err.Error()
+	}
+	return e.Message
+}
+
+func (e *HTTPError) Unwrap() error {
+	return e.Err
+}
+
+// WithData 返回附带数据的副本
+func (e *HTTPError) WithData(data any) *HTTPError {
+	err := *e
+	err.Data = data
+	return &err
+}
+
+// Wrap 返回包装原始错误的副本
+func (e *HTTPError) Wrap(cause error) *HTTPError {
+	err := *e
+	err.Err = cause
+	return &err
+}
+
+// Is 业务码相同即视为同一错误
+func (e *HTTPError) Is(target error) bool {
+	var t *HTTPError
+	if errors.As(target, &t) {
+		return e.Code == t.Code && e.Status == t.Status
+	}
+	return false
+}
+
+// ErrorMapper 将处理函数返回的错误转换为响应
+type ErrorMapper func(ctx *Context, err error) *HTTPError
+
+var errorMapper ErrorMapper = MapError
+
+// SetErrorMapper 设置 Handle 使用的错误转换函数
+func SetErrorMapper(mapper ErrorMapper) {
+	if mapper == nil {
+		mapper = MapError
+	}
+	errorMapper = mapper
+}
+
+// MapError 默认错误转换：
+// HTTPError 原样返回，BizError 按请求语言取消息，参数校验错误返回 400/4000 和中文校验信息，
+// 其他错误返回 500/5000（不暴露错误详情）
+func MapError(ctx *Context, err error) *HTTPError {
+	var httpErr *HTTPError
+	if errors.As(err, &httpErr) {
+		return httpErr
+	}
+
+	if bizErr, ok := AsBizError(err); ok {
+		return &HTTPError{
+			Status:  bizErr.HTTPStatus,
+			Code:    bizErr.Code,
+			Message: bizErr.Message(ctx.Language()),
+			Err:     err,
+		}
+	}
+
+	var validationErrs validator.ValidationErrors
+	if errors.As(err, &validationErrs) {
+		return &HTTPError{
+			Status:  http.StatusBadRequest,
+			Code:    HandleCodeInvalidParams,
+			Message: firstValidationMessage(err),
+			Data:    ValidationMessages(err),
+			Err:     err,
+		}
+	}
+
+	if errors.Is(err, ErrInvalidParams) {
+		return &HTTPError{
+			Status:  http.StatusBadRequest,
+			Code:    HandleCodeInvalidParams,
+			Message: BizErrInvalidParams.Message(ctx.Language()),
+			Err:     err,
+		}
+	}
+
+	return &HTTPError{
+		Status:  http.StatusInternalServerError,
+		Code:    HandleCodeInternalFailure,
+		Message: BizErrInternalFailure.Message(ctx.Language()),
+		Err:     err,
+	}
+}
+
+// Handle 类型化处理函数
+// 按结构体标签绑定请求：uri 路径参数、form 查询参数/表单、header 请求头、json 请求体，绑定后使用 binding 标签校验。
+// 成功时返回 Response 信封，失败时通过 ErrorMapper 转换为 HTTP 状态码、业务码和消息。
+func Handle[Req any, Resp any](fn func(ctx *Context, req *Req) (*Resp, error)) HandlerFunc {
+	setupValidator()
+
+	tags := bindTagsOf(reflect.TypeOf((*Req)(nil)).Elem())
+
+	return func(ctx *Context) {
+		req := new(Req)
+
+		if err := bindRequest(ctx, req, tags); err != nil {
+			ctx.Fail(err)
+			return
+		}
+
+		resp, err := fn(ctx, req)
+		if err != nil {
+			ctx.Fail(err)
+			return
+		}
+
+		// 处理函数已自行写入响应（如文件下载）
+		if ctx.Writer.Written() || ctx.IsAborted() {
+			return
+		}
+
+		if resp == nil {
+			ctx.Success(nil)
+			return
+		}
+
+		ctx.Success(resp)
+	}
+}
+
+// bindTags 请求结构体使用的绑定标签
+type bindTags struct {
+	uri    bool
+	form   bool
+	header bool
+}
+
+func bindTagsOf(t reflect.Type) bindTags {
+	var tags bindTags
+	collectBindTags(t, &tags, map[reflect.Type]bool{})
+	return tags
+}
+
+func collectBindTags(t reflect.Type, tags *bindTags, visited map[reflect.Type]bool) {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	if t.Kind() != reflect.Struct || visited[t] {
+		return
+	}
+	visited[t] = true
+
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+
+		if _, ok := field.Tag.Lookup("uri"); ok {
+			tags.uri = true
+		}
+		if _, ok := field.Tag.Lookup("form"); ok {
+			tags.form = true
+		}
+		if _, ok := field.Tag.Lookup("header"); ok {
+			tags.header = true
+		}
+
+		if field.Anonymous {
+			collectBindTags(field.Type, tags, visited)
+		}
+	}
+}
+
+// bindRequest 依次绑定 JSON 请求体、form、uri、header，后绑定的覆盖先绑定的，
+// 请求体中的同名字段不能覆盖路径参数和请求头（如鉴权网关注入的租户ID）
+func bindRequest(ctx *Context, req any, tags bindTags) error {
+	contentType := ctx.ContentType()
+	hasBody := ctx.Request.Body != nil && ctx.Request.Body != http.NoBody && ctx.Request.ContentLength != 0
+
+	if hasBody && (contentType == binding.MIMEJSON || contentType == "") {
+		if err := json.NewDecoder(ctx.Request.Body).Decode(req); err != nil && err != io.EOF {
+			return errors.Join(ErrInvalidParams, err)
+		}
+	}
+
+	if tags.form {
+		form := ctx.Request.URL.Query()
+
+		if hasBody {
+			switch contentType {
+			case binding.MIMEPOSTForm:
+				if err := ctx.Request.ParseForm(); err != nil {
+					return errors.Join(ErrInvalidParams, err)
+				}
+				form = ctx.Request.Form
+			case binding.MIMEMultipartPOSTForm:
+				if _, err := ctx.MultipartForm(); err != nil {
+					return errors.Join(ErrInvalidParams, err)
+				}
+				form = ctx.Request.Form
+			}
+		}
+
+		if err := binding.MapFormWithTag(req, form, "form"); err != nil {
+			return errors.Join(ErrInvalidParams, err)
+		}
+	}
+
+	if tags.uri && len(ctx.Params) > 0 {
+		params := make(map[string][]string, len(ctx.Params))
+		for _, param := range ctx.Params {
+			params[param.Key] = []string{param.Value}
+		}
+		if err := binding.MapFormWithTag(req, params, "uri"); err != nil {
+			return errors.Join(ErrInvalidParams, err)
+		}
+	}
+
+	if tags.header {
+		// 同时提供规范格式和小写格式的请求头名称
+		headers := make(map[string][]string, len(ctx.Request.Header)*2)
+		for key, values := range ctx.Request.Header {
+			headers[key] = values
+			headers[strings.ToLower(key)] = values
+		}
+		if err := binding.MapFormWithTag(req, headers, "header"); err != nil {
+			return errors.Join(ErrInvalidParams, err)
+		}
+	}
+
+	if binding.Validator == nil {
+		return nil
+	}
+
+	return binding.Validator.ValidateStruct(req)
+}
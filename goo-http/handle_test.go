@@ -0,0 +1,45 @@
+package goohttp
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+)
+
+// 请求体中的同名字段不能覆盖路径参数和请求头
+func TestHandleBindPathAndHeaderOverrideBody(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	type req struct {
+		Id       string `uri:"id" json:"id"`
+		TenantId string `header:"X-Tenant-Id" json:"tenant_id"`
+		Nickname string `json:"nickname"`
+	}
+
+	server := New(WithEnableLog(false))
+	server.Put("/users/:id", Handle(func(ctx *Context, req *req) (*req, error) {
+		return req, nil
+	}))
+
+	r := httptest.NewRequest(http.MethodPut, "/users/42", strings.NewReader(`{"id":"1","tenant_id":"other","nickname":"goo"}`))
+	r.Header.Set("Content-Type", "application/json")
+	r.Header.Set("X-Tenant-Id", "t1")
+	w := httptest.NewRecorder()
+	server.ServeHTTP(w, r)
+
+	var resp struct {
+		Data req `json:"data"`
+	}
+	if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("status = %d, body = %s: %v", w.Code, w.Body, err)
+	}
+
+	want := req{Id: "42", TenantId: "t1", Nickname: "goo"}
+	if resp.Data != want {
+		t.Fatalf("bound = %+v, want %+v", resp.Data, want)
+	}
+}
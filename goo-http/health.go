@@ -0,0 +1,96 @@
+package goohttp
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	goohealth "v2.googo.io/goo-health"
+)
+
+var (
+	DefaultHealthConfig = &HealthConfig{
+		HealthPath: "/healthz",
+		ReadyPath:  "/readyz",
+		LivePath:   "/livez",
+		DrainDelay: 5 * time.Second,
+	}
+)
+
+type HealthConfig struct {
+	Registry   *goohealth.Registry `yaml:"-" json:"-"`                     // 健康检查注册表（默认使用 goohealth.Default()）
+	HealthPath string              `yaml:"health_path" json:"health_path"` // 健康检查地址（默认 /healthz）
+	ReadyPath  string              `yaml:"ready_path" json:"ready_path"`   // 就绪检查地址（默认 /readyz）
+	LivePath   string              `yaml:"live_path" json:"live_path"`     // 存活检查地址（默认 /livez）
+	DrainDelay time.Duration       `yaml:"drain_delay" json:"drain_delay"` // 关闭前就绪检查返回未就绪的等待时间，让负载均衡先摘除流量（默认 5 秒）
+}
+
+// HealthHandler 输出检查报告，未就绪时返回 503
+func HealthHandler(check func(ctx context.Context) *goohealth.Report) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		report := check(c.Request.Context())
+
+		status := http.StatusOK
+		if !report.Healthy() {
+			status = http.StatusServiceUnavailable
+		}
+
+		c.Header("Cache-Control", "no-store")
+		c.JSON(status, report)
+	}
+}
+
+// setupHealth 注册健康检查地址
+// 在中间件之前注册，探针请求不经过日志、限流、认证等中间件
+func (s *Server) setupHealth() {
+	config := s.healthConfig()
+	registry := s.healthRegistry()
+
+	if config.HealthPath != "" {
+		s.engine.GET(config.HealthPath, HealthHandler(registry.Check))
+	}
+	if config.ReadyPath != "" {
+		s.engine.GET(config.ReadyPath, HealthHandler(registry.Ready))
+	}
+	if config.LivePath != "" {
+		s.engine.GET(config.LivePath, HealthHandler(registry.Live))
+	}
+}
+
+func (s *Server) healthConfig() *HealthConfig {
+	if s.config.HealthConfig != nil {
+		return s.config.HealthConfig
+	}
+	return DefaultHealthConfig
+}
+
+func (s *Server) healthRegistry() *goohealth.Registry {
+	if registry := s.healthConfig().Registry; registry != nil {
+		return registry
+	}
+	return goohealth.Default()
+}
+
+// drain 标记下线并等待负载均衡摘除流量
+// Config.DrainDelay 优先，未设置时启用健康检查使用 HealthConfig.DrainDelay
+func (s *Server) drain(ctx context.Context) {
+	delay := s.config.DrainDelay
+	if s.config.EnableHealth {
+		s.healthRegistry().SetDraining(true)
+		if delay <= 0 {
+			delay = s.healthConfig().DrainDelay
+		}
+	}
+	if delay <= 0 {
+		return
+	}
+
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+
+	select {
+	case <-timer.C:
+	case <-ctx.Done():
+	}
+}
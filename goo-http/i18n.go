@@ -0,0 +1,146 @@
+package goohttp
+
+import (
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+var (
+	// DefaultLanguage 默认语言，请求未携带 Accept-Language 或没有匹配的消息目录时使用
+	DefaultLanguage = "zh-CN"
+)
+
+var (
+	catalogs   = make(map[string]map[string]string) // 语言 => 消息 key => 消息
+	catalogsMu sync.RWMutex
+)
+
+// RegisterMessages 注册消息目录，同一语言多次注册时合并
+func RegisterMessages(lang string, messages map[string]string) {
+	lang = normalizeLanguage(lang)
+
+	catalogsMu.Lock()
+	defer catalogsMu.Unlock()
+
+	catalog, ok := catalogs[lang]
+	if !ok {
+		catalog = make(map[string]string, len(messages))
+		catalogs[lang] = catalog
+	}
+
+	for key, message := range messages {
+		catalog[key] = message
+	}
+}
+
+// Translate 获取消息，依次尝试指定语言、同一主语言的其他目录（en => en-us）、默认语言，都没有时返回 key
+func Translate(lang string, key string) string {
+	catalogsMu.RLock()
+	defer catalogsMu.RUnlock()
+
+	for _, candidate := range languageFallbacks(lang) {
+		if catalog, ok := catalogs[candidate]; ok {
+			if message, ok := catalog[key]; ok {
+				return message
+			}
+		}
+
+		base := baseLanguage(candidate)
+		for name, catalog := range catalogs {
+			if name != candidate && baseLanguage(name) == base {
+				if message, ok := catalog[key]; ok {
+					return message
+				}
+			}
+		}
+	}
+
+	return key
+}
+
+// MatchLanguage 按 Accept-Language 的权重选择已注册的语言
+func MatchLanguage(acceptLanguage string) string {
+	type weighted struct {
+		lang string
+		q    float64
+	}
+
+	var langs []weighted
+	for _, part := range strings.Split(acceptLanguage, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+
+		lang, q := part, 1.0
+		if i := strings.IndexByte(part, ';'); i >= 0 {
+			lang = strings.TrimSpace(part[:i])
+			param := strings.TrimSpace(part[i+1:])
+			if strings.HasPrefix(param, "q=") {
+				if v, err := strconv.ParseFloat(param[2:], 64); err == nil {
+					q = v
+				}
+			}
+		}
+
+		if lang == "" || lang == "*" || q <= 0 {
+			continue
+		}
+		langs = append(langs, weighted{lang: normalizeLanguage(lang), q: q})
+	}
+
+	sort.SliceStable(langs, func(i, j int) bool {
+		return langs[i].q > langs[j].q
+	})
+
+	catalogsMu.RLock()
+	defer catalogsMu.RUnlock()
+
+	for _, item := range langs {
+		if _, ok := catalogs[item.lang]; ok {
+			return item.lang
+		}
+		if base := baseLanguage(item.lang); base != item.lang {
+			if _, ok := catalogs[base]; ok {
+				return base
+			}
+		}
+		// zh => zh-cn
+		for lang := range catalogs {
+			if baseLanguage(lang) == item.lang {
+				return lang
+			}
+		}
+	}
+
+	return normalizeLanguage(DefaultLanguage)
+}
+
+// Language 当前请求的语言（根据 Accept-Language 匹配已注册的消息目录）
+func (c *Context) Language() string {
+	if lang := c.Context.GetString("language"); lang != "" {
+		return lang
+	}
+
+	lang := MatchLanguage(c.GetHeader("Accept-Language"))
+	c.Context.Set("language", lang)
+
+	return lang
+}
+
+func languageFallbacks(lang string) []string {
+	return []string{normalizeLanguage(lang), normalizeLanguage(DefaultLanguage)}
+}
+
+func normalizeLanguage(lang string) string {
+	return strings.ToLower(strings.ReplaceAll(strings.TrimSpace(lang), "_", "-"))
+}
+
+func baseLanguage(lang string) string {
+	if i := strings.IndexByte(lang, '-'); i > 0 {
+		return lang[:i]
+	}
+	return lang
+}
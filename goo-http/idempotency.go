@@ -0,0 +1,392 @@
+package goohttp
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+var (
+	ErrIdempotencyStoreBusy = errors.New("幂等存储占用失败，请重试")
+	ErrIdempotencyLockLost  = errors.New("幂等键占用已过期，记录未保存")
+)
+
+var (
+	DefaultIdempotencyConfig = &IdempotencyConfig{
+		Header:       "Idempotency-Key",
+		Methods:      []string{http.MethodPost, http.MethodPatch},
+		TTL:          24 * time.Hour,
+		LockTTL:      time.Minute,
+		MaxKeyLength: 255,
+		MaxBodySize:  1 * 1024 * 1024,
+	}
+)
+
+type IdempotencyConfig struct {
+	Store        IdempotencyStore              // 幂等存储（默认进程内存储，多实例部署使用 RedisIdempotencyStore）
+	Header       string                        // 幂等键请求头（默认 Idempotency-Key）
+	Methods      []string                      // 生效的请求方法（默认 POST、PATCH）
+	Routes       []string                      // 生效的路由，如 "POST /api/orders" 或 "/api/orders"，为空时对所有路由生效
+	Required     bool                          // 是否必须携带幂等键
+	TTL          time.Duration                 // 响应保存时间（默认 24 小时）
+	LockTTL      time.Duration                 // 处理中记录的过期时间，防止进程异常退出后 key 一直被占用（默认 1 分钟）
+	MaxKeyLength int                           // 幂等键最大长度（默认 255）
+	MaxBodySize  int                           // 可保存的最大响应体（默认 1MB），超出时不保存
+	ScopeFunc    func(ctx *Context) string     // 幂等键作用域（如用户ID），不同作用域的相同 key 互不影响，为空时按签名应用、JWT 主体、会话或 Authorization 请求头区分
+	OnStoreError func(ctx *Context, err error) // 幂等存储出错时的回调（如记录日志、告警）
+}
+
+// Idempotency 幂等处理
+// 首个请求占用 key 并保存响应，之后相同 key 且请求内容相同的请求直接返回保存的响应
+type Idempotency struct {
+	config *IdempotencyConfig
+	store  IdempotencyStore
+	local  *MemoryIdempotencyStore // 默认存储，未配置 Store 时使用
+	routes map[string]bool
+}
+
+func NewIdempotency(config *IdempotencyConfig) *Idempotency {
+	if config == nil {
+		config = DefaultIdempotencyConfig
+	}
+
+	c := *config
+	if c.Header == "" {
+		c.Header = DefaultIdempotencyConfig.Header
+	}
+	if len(c.Methods) == 0 {
+		c.Methods = DefaultIdempotencyConfig.Methods
+	}
+	if c.TTL <= 0 {
+		c.TTL = DefaultIdempotencyConfig.TTL
+	}
+	if c.LockTTL <= 0 {
+		c.LockTTL = DefaultIdempotencyConfig.LockTTL
+	}
+	if c.MaxKeyLength <= 0 {
+		c.MaxKeyLength = DefaultIdempotencyConfig.MaxKeyLength
+	}
+	if c.MaxBodySize <= 0 {
+		c.MaxBodySize = DefaultIdempotencyConfig.MaxBodySize
+	}
+
+	idem := &Idempotency{
+		config: &c,
+		store:  c.Store,
+	}
+
+	if idem.store == nil {
+		idem.local = NewMemoryIdempotencyStore(time.Minute)
+		idem.store = idem.local
+	}
+
+	if len(c.Routes) > 0 {
+		idem.routes = make(map[string]bool, len(c.Routes))
+		for _, route := range c.Routes {
+			idem.routes[route] = true
+		}
+	}
+
+	return idem
+}
+
+// Stop 停止默认存储的清理goroutine
+func (i *Idempotency) Stop() {
+	if i.local != nil {
+		i.local.Stop()
+	}
+}
+
+// Wrap 路由级幂等处理，包装处理函数
+//
+//	server.Post("/orders", idem.Wrap(createOrder))
+func (i *Idempotency) Wrap(handler HandlerFunc) HandlerFunc {
+	return func(ctx *Context) {
+		i.serve(ctx, func() {
+			handler(ctx)
+		})
+	}
+}
+
+func (i *Idempotency) match(ctx *Context) bool {
+	method := ctx.Request.Method
+
+	matched := false
+	for _, m := range i.config.Methods {
+		if strings.EqualFold(m, method) {
+			matched = true
+			break
+		}
+	}
+	if !matched {
+		return false
+	}
+
+	if i.routes == nil {
+		return true
+	}
+
+	route := ctx.FullPath()
+	return i.routes[method+" "+route] || i.routes[route]
+}
+
+func (i *Idempotency) serve(ctx *Context, next func()) {
+	key := ctx.GetHeader(i.config.Header)
+	if key == "" {
+		if i.config.Required {
+			ctx.Abort(http.StatusBadRequest, 4003, "缺少幂等键")
+			return
+		}
+		next()
+		return
+	}
+	if len(key) > i.config.MaxKeyLength {
+		ctx.Abort(http.StatusBadRequest, 4004, "幂等键过长")
+		return
+	}
+
+	fingerprint, err := requestFingerprint(ctx)
+	if err != nil {
+		ctx.Abort(http.StatusBadRequest, 4000, "获取请求数据失败")
+		return
+	}
+
+	scope := ""
+	if i.config.ScopeFunc != nil {
+		scope = i.config.ScopeFunc(ctx)
+	} else {
+		scope = idempotencyPrincipal(ctx)
+	}
+	storeKey := scope + ":" + ctx.Request.Method + " " + ctx.FullPath() + ":" + key
+
+	token := newIdempotencyToken()
+	record, locked, err := i.store.Lock(ctx.Request.Context(), storeKey, &IdempotencyRecord{
+		Fingerprint: fingerprint,
+		CreatedAt:   time.Now(),
+		Token:       token,
+	}, i.config.LockTTL)
+	if err != nil {
+		i.storeError(ctx, err)
+		ctx.Abort(http.StatusServiceUnavailable, 5031, "幂等存储不可用")
+		return
+	}
+
+	if !locked {
+		switch {
+		case record.Fingerprint != fingerprint:
+			ctx.Abort(http.StatusUnprocessableEntity, 4220, "幂等键已用于其他请求")
+		case !record.Completed:
+			ctx.Header("Retry-After", "1")
+			ctx.Abort(http.StatusConflict, 4090, "请求正在处理中")
+		default:
+			replayIdempotentResponse(ctx, record)
+		}
+		return
+	}
+
+	writer := &idempotencyResponseWriter{
+		ResponseWriter: ctx.Writer,
+		buffer:         getBuffer(),
+		maxSize:        i.config.MaxBodySize,
+	}
+	defer putBuffer(writer.buffer)
+
+	before := ctx.Writer.Header().Clone()
+	ctx.Writer = writer
+
+	// 处理函数 panic 时释放 key，客户端可以重试
+	completed := false
+	defer func() {
+		ctx.Writer = writer.ResponseWriter
+		if !completed {
+			i.unlock(ctx, storeKey, token)
+		}
+	}()
+
+	next()
+
+	status := writer.status
+	if status == 0 {
+		status = http.StatusOK
+	}
+
+	// 服务端错误和超出大小的响应不保存，客户端重试时重新处理
+	if status >= http.StatusInternalServerError || writer.overflow {
+		return
+	}
+
+	err = i.store.Save(ctx.Request.Context(), storeKey, &IdempotencyRecord{
+		Fingerprint: fingerprint,
+		Completed:   true,
+		Status:      status,
+		Header:      changedHeaders(before, writer.Header()),
+		Body:        append([]byte(nil), writer.buffer.Bytes()...),
+		CreatedAt:   time.Now(),
+		Token:       token,
+	}, i.config.TTL)
+	if err != nil {
+		i.storeError(ctx, err)
+		return
+	}
+
+	completed = true
+}
+
+func (i *Idempotency) unlock(ctx *Context, storeKey, token string) {
+	// 请求可能已被取消，使用独立的 ctx 释放
+	c, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+
+	if err := i.store.Unlock(c, storeKey, token); err != nil {
+		i.storeError(ctx, err)
+	}
+}
+
+func (i *Idempotency) storeError(ctx *Context, err error) {
+	if i.config.OnStoreError != nil {
+		i.config.OnStoreError(ctx, err)
+	}
+}
+
+// idempotencyPrincipal 默认作用域：签名应用 ID、JWT 主体、会话 ID 或 Authorization 请求头，
+// 取摘要避免凭证明文出现在存储 key 中，都没有时为匿名作用域
+func idempotencyPrincipal(ctx *Context) string {
+	var principal string
+	switch {
+	case ctx.AppId() != "":
+		principal = "app\n" + ctx.AppId()
+	case ctx.JWTClaims() != nil && ctx.JWTClaims().Subject != "":
+		principal = "jwt\n" + ctx.JWTClaims().Issuer + "\n" + ctx.JWTClaims().Subject
+	case ctx.Session() != nil && !ctx.Session().IsNew():
+		principal = "session\n" + ctx.Session().Id()
+	case ctx.GetHeader("Authorization") != "":
+		principal = "auth\n" + ctx.GetHeader("Authorization")
+	default:
+		return ""
+	}
+
+	sum := sha256.Sum256([]byte(principal))
+	return hex.EncodeToString(sum[:16])
+}
+
+// newIdempotencyToken 生成占用令牌，过期后被其他请求重新占用时，原请求无法覆盖或删除新记录
+func newIdempotencyToken() string {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		panic(err)
+	}
+	return hex.EncodeToString(b)
+}
+
+// requestFingerprint 请求指纹：方法、路径、查询参数和请求体的 SHA-256
+func requestFingerprint(ctx *Context) (string, error) {
+	h := sha256.New()
+	io.WriteString(h, ctx.Request.Method)
+	io.WriteString(h, "\n")
+	io.WriteString(h, ctx.Request.URL.RequestURI())
+	io.WriteString(h, "\n")
+
+	if ctx.Request.Body != nil {
+		buf := getBuffer()
+		defer putBuffer(buf)
+
+		if _, err := io.Copy(buf, ctx.Request.Body); err != nil {
+			return "", err
+		}
+		h.Write(buf.Bytes())
+
+		ctx.Request.Body = io.NopCloser(bytes.NewReader(append([]byte(nil), buf.Bytes()...)))
+	}
+
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+// changedHeaders 只保存处理函数新增或修改的响应头，TraceId、限流等外层中间件设置的响应头重放时重新生成
+func changedHeaders(before http.Header, after http.Header) http.Header {
+	changed := make(http.Header)
+	for key, values := range after {
+		if key == "Content-Length" {
+			continue
+		}
+		if old, ok := before[key]; ok && strings.Join(old, "\n") == strings.Join(values, "\n") {
+			continue
+		}
+		changed[key] = append([]string(nil), values...)
+	}
+	return changed
+}
+
+func replayIdempotentResponse(ctx *Context, record *IdempotencyRecord) {
+	header := ctx.Writer.Header()
+	for key, values := range record.Header {
+		header[key] = append([]string(nil), values...)
+	}
+	header.Set("Idempotent-Replayed", "true")
+
+	ctx.Status(record.Status)
+	ctx.Writer.Write(record.Body)
+	ctx.Context.Abort()
+}
+
+// 幂等响应写入器，转发响应的同时保存响应体
+type idempotencyResponseWriter struct {
+	gin.ResponseWriter
+	buffer   *bytes.Buffer
+	maxSize  int
+	status   int
+	overflow bool
+}
+
+func (w *idempotencyResponseWriter) WriteHeader(statusCode int) {
+	w.status = statusCode
+	w.ResponseWriter.WriteHeader(statusCode)
+}
+
+func (w *idempotencyResponseWriter) Write(data []byte) (int, error) {
+	if w.status == 0 {
+		w.status = http.StatusOK
+	}
+
+	if !w.overflow {
+		if w.buffer.Len()+len(data) > w.maxSize {
+			w.overflow = true
+		} else {
+			w.buffer.Write(data)
+		}
+	}
+
+	return w.ResponseWriter.Write(data)
+}
+
+func (w *idempotencyResponseWriter) WriteString(s string) (int, error) {
+	return w.Write([]byte(s))
+}
+
+// Flush 流式输出不保存
+func (w *idempotencyResponseWriter) Flush() {
+	w.overflow = true
+	w.ResponseWriter.Flush()
+}
+
+// IdempotencyMiddleware 幂等中间件，只处理配置的请求方法和路由
+func IdempotencyMiddleware(idem *Idempotency) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		ctx := &Context{Context: c}
+
+		if !idem.match(ctx) {
+			c.Next()
+			return
+		}
+
+		idem.serve(ctx, c.Next)
+	}
+}
@@ -0,0 +1,245 @@
+package goohttp
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/go-redis/redis/v8"
+	gooredis "v2.googo.io/goo-redis"
+)
+
+// IdempotencyRecord 幂等记录
+type IdempotencyRecord struct {
+	Fingerprint string      `json:"fingerprint"`          // 请求指纹（方法、路径、请求体的摘要）
+	Completed   bool        `json:"completed"`            // 是否已处理完成，未完成表示首个请求正在处理
+	Status      int         `json:"status,omitempty"`     // 响应状态码
+	Header      http.Header `json:"header,omitempty"`     // 处理函数设置的响应头
+	Body        []byte      `json:"body,omitempty"`       // 响应体
+	CreatedAt   time.Time   `json:"created_at,omitempty"` // 创建时间
+	Token       string      `json:"token,omitempty"`      // 占用令牌，Save 和 Unlock 只在令牌一致时生效
+}
+
+// IdempotencyStore 幂等存储
+type IdempotencyStore interface {
+	// Lock 尝试占用 key：key 不存在时写入未完成的记录并返回 (nil, true)，已存在时返回已有记录和 false
+	Lock(ctx context.Context, key string, record *IdempotencyRecord, ttl time.Duration) (*IdempotencyRecord, bool, error)
+	// Save 保存处理完成的记录，只在已有记录的令牌与 record.Token 一致时写入，否则返回 ErrIdempotencyLockLost
+	Save(ctx context.Context, key string, record *IdempotencyRecord, ttl time.Duration) error
+	// Unlock 删除令牌一致的记录，处理失败时调用，客户端可以重试
+	Unlock(ctx context.Context, key string, token string) error
+}
+
+type idempotencyEntry struct {
+	record    *IdempotencyRecord
+	expiresAt time.Time
+}
+
+// MemoryIdempotencyStore 进程内幂等存储（单实例部署使用）
+type MemoryIdempotencyStore struct {
+	entries  map[string]*idempotencyEntry
+	mu       sync.Mutex
+	stopCh   chan struct{}
+	stopOnce sync.Once
+	wg       sync.WaitGroup
+}
+
+// NewMemoryIdempotencyStore 创建进程内幂等存储，过期记录会被定期清理
+func NewMemoryIdempotencyStore(cleanupInterval time.Duration) *MemoryIdempotencyStore {
+	if cleanupInterval <= 0 {
+		cleanupInterval = time.Minute
+	}
+
+	s := &MemoryIdempotencyStore{
+		entries: make(map[string]*idempotencyEntry),
+		stopCh:  make(chan struct{}),
+	}
+
+	s.wg.Add(1)
+	go s.cleanup(cleanupInterval)
+
+	return s
+}
+
+func (s *MemoryIdempotencyStore) Lock(ctx context.Context, key string, record *IdempotencyRecord, ttl time.Duration) (*IdempotencyRecord, bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := time.Now()
+	if entry, exists := s.entries[key]; exists && now.Before(entry.expiresAt) {
+		copied := *entry.record
+		return &copied, false, nil
+	}
+
+	copied := *record
+	s.entries[key] = &idempotencyEntry{
+		record:    &copied,
+		expiresAt: now.Add(ttl),
+	}
+
+	return nil, true, nil
+}
+
+func (s *MemoryIdempotencyStore) Save(ctx context.Context, key string, record *IdempotencyRecord, ttl time.Duration) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	// 占用已过期并被其他请求重新占用时不覆盖
+	entry, exists := s.entries[key]
+	if !exists || !time.Now().Before(entry.expiresAt) || entry.record.Token != record.Token {
+		return ErrIdempotencyLockLost
+	}
+
+	copied := *record
+	s.entries[key] = &idempotencyEntry{
+		record:    &copied,
+		expiresAt: time.Now().Add(ttl),
+	}
+
+	return nil
+}
+
+func (s *MemoryIdempotencyStore) Unlock(ctx context.Context, key string, token string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if entry, exists := s.entries[key]; exists && entry.record.Token == token {
+		delete(s.entries, key)
+	}
+	return nil
+}
+
+func (s *MemoryIdempotencyStore) cleanup(interval time.Duration) {
+	defer s.wg.Done()
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			now := time.Now()
+			s.mu.Lock()
+			for key, entry := range s.entries {
+				if !now.Before(entry.expiresAt) {
+					delete(s.entries, key)
+				}
+			}
+			s.mu.Unlock()
+		case <-s.stopCh:
+			return
+		}
+	}
+}
+
+// Stop 停止清理goroutine
+func (s *MemoryIdempotencyStore) Stop() {
+	s.stopOnce.Do(func() {
+		close(s.stopCh)
+	})
+	s.wg.Wait()
+}
+
+// RedisIdempotencyStore 基于 goo-redis 的幂等存储（多实例部署使用）
+type RedisIdempotencyStore struct {
+	client *gooredis.Client
+	prefix string
+}
+
+func NewRedisIdempotencyStore(client *gooredis.Client, prefix string) *RedisIdempotencyStore {
+	if prefix == "" {
+		prefix = "goohttp:idempotency:"
+	}
+
+	return &RedisIdempotencyStore{
+		client: client,
+		prefix: prefix,
+	}
+}
+
+func (s *RedisIdempotencyStore) Lock(ctx context.Context, key string, record *IdempotencyRecord, ttl time.Duration) (*IdempotencyRecord, bool, error) {
+	data, err := json.Marshal(record)
+	if err != nil {
+		return nil, false, err
+	}
+
+	rdb := s.client.Client()
+
+	// SETNX 失败后记录可能恰好过期，重试一次
+	for i := 0; i < 2; i++ {
+		ok, err := rdb.SetNX(ctx, s.prefix+key, data, ttl).Result()
+		if err != nil {
+			return nil, false, err
+		}
+		if ok {
+			return nil, true, nil
+		}
+
+		existing, err := rdb.Get(ctx, s.prefix+key).Bytes()
+		if err == redis.Nil {
+			continue
+		}
+		if err != nil {
+			return nil, false, err
+		}
+
+		var stored IdempotencyRecord
+		if err := json.Unmarshal(existing, &stored); err != nil {
+			return nil, false, err
+		}
+
+		return &stored, false, nil
+	}
+
+	return nil, false, ErrIdempotencyStoreBusy
+}
+
+// redisIdempotencySaveScript 令牌一致时写入处理完成的记录
+var redisIdempotencySaveScript = redis.NewScript(`
+local data = redis.call("GET", KEYS[1])
+if not data then
+	return 0
+end
+local ok, record = pcall(cjson.decode, data)
+if not ok or record["token"] ~= ARGV[1] then
+	return 0
+end
+redis.call("SET", KEYS[1], ARGV[2], "PX", ARGV[3])
+return 1
+`)
+
+// redisIdempotencyUnlockScript 令牌一致时删除记录
+var redisIdempotencyUnlockScript = redis.NewScript(`
+local data = redis.call("GET", KEYS[1])
+if not data then
+	return 0
+end
+local ok, record = pcall(cjson.decode, data)
+if not ok or record["token"] ~= ARGV[1] then
+	return 0
+end
+return redis.call("DEL", KEYS[1])
+`)
+
+func (s *RedisIdempotencyStore) Save(ctx context.Context, key string, record *IdempotencyRecord, ttl time.Duration) error {
+	data, err := json.Marshal(record)
+	if err != nil {
+		return err
+	}
+
+	saved, err := redisIdempotencySaveScript.Run(ctx, s.client.Client(), []string{s.prefix + key}, record.Token, data, ttl.Milliseconds()).Int()
+	if err != nil {
+		return err
+	}
+	if saved == 0 {
+		return ErrIdempotencyLockLost
+	}
+
+	return nil
+}
+
+func (s *RedisIdempotencyStore) Unlock(ctx context.Context, key string, token string) error {
+	return redisIdempotencyUnlockScript.Run(ctx, s.client.Client(), []string{s.prefix + key}, token).Err()
+}
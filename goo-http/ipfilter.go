@@ -0,0 +1,148 @@
+package goohttp
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/netip"
+	"strings"
+	"sync/atomic"
+
+	"github.com/gin-gonic/gin"
+)
+
+var (
+	ErrInvalidCIDR = errors.New("IP 或 CIDR 格式错误")
+)
+
+type IPFilterConfig struct {
+	Allow []string `yaml:"allow" json:"allow"` // 允许的 IP 或 CIDR，不为空时只允许列表中的 IP
+	Deny  []string `yaml:"deny" json:"deny"`   // 拒绝的 IP 或 CIDR，优先于 Allow
+}
+
+type ipFilterRules struct {
+	allow []netip.Prefix
+	deny  []netip.Prefix
+}
+
+// IPFilter IP 黑白名单，支持 IPv4 / IPv6，名单可以在运行时更新
+type IPFilter struct {
+	rules atomic.Pointer[ipFilterRules]
+}
+
+func NewIPFilter(config *IPFilterConfig) (*IPFilter, error) {
+	f := &IPFilter{}
+	if err := f.Update(config); err != nil {
+		return nil, err
+	}
+	return f, nil
+}
+
+// Update 更新黑白名单，格式错误时保留原名单
+func (f *IPFilter) Update(config *IPFilterConfig) error {
+	if config == nil {
+		config = &IPFilterConfig{}
+	}
+
+	allow, err := ParsePrefixes(config.Allow)
+	if err != nil {
+		return err
+	}
+	deny, err := ParsePrefixes(config.Deny)
+	if err != nil {
+		return err
+	}
+
+	f.rules.Store(&ipFilterRules{
+		allow: allow,
+		deny:  deny,
+	})
+
+	return nil
+}
+
+// Allowed 检查 IP 是否允许访问，无法解析的 IP 不允许访问
+func (f *IPFilter) Allowed(ip string) bool {
+	addr, err := netip.ParseAddr(ip)
+	if err != nil {
+		return false
+	}
+	addr = addr.Unmap()
+
+	rules := f.rules.Load()
+
+	for _, prefix := range rules.deny {
+		if prefix.Contains(addr) {
+			return false
+		}
+	}
+
+	if len(rules.allow) == 0 {
+		return true
+	}
+	for _, prefix := range rules.allow {
+		if prefix.Contains(addr) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Handler 路由级 IP 过滤，可用于路由分组
+//
+//	admin := server.Group("/admin", filter.Handler())
+func (f *IPFilter) Handler() HandlerFunc {
+	return func(ctx *Context) {
+		if !f.Allowed(ctx.ClientIP()) {
+			ctx.Abort(http.StatusForbidden, 4030, "IP not allowed")
+		}
+	}
+}
+
+func IPFilterMiddleware(filter *IPFilter) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		ctx := &Context{Context: c}
+
+		if !filter.Allowed(ctx.ClientIP()) {
+			ctx.Abort(http.StatusForbidden, 4030, "IP not allowed")
+			return
+		}
+
+		c.Next()
+	}
+}
+
+// ParsePrefixes 解析 IP 或 CIDR 列表，单个 IP 视为 /32（IPv6 为 /128）
+func ParsePrefixes(values []string) ([]netip.Prefix, error) {
+	prefixes := make([]netip.Prefix, 0, len(values))
+
+	for _, value := range values {
+		value = strings.TrimSpace(value)
+		if value == "" {
+			continue
+		}
+
+		if strings.Contains(value, "/") {
+			prefix, err := netip.ParsePrefix(value)
+			if err != nil {
+				return nil, fmt.Errorf("%w: %s", ErrInvalidCIDR, value)
+			}
+			// IPv4 映射的 IPv6 前缀转换为 IPv4
+			if prefix.Addr().Is4In6() && prefix.Bits() >= 96 {
+				prefix = netip.PrefixFrom(prefix.Addr().Unmap(), prefix.Bits()-96)
+			}
+			prefixes = append(prefixes, prefix.Masked())
+			continue
+		}
+
+		addr, err := netip.ParseAddr(value)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %s", ErrInvalidCIDR, value)
+		}
+		addr = addr.Unmap()
+		prefixes = append(prefixes, netip.PrefixFrom(addr, addr.BitLen()))
+	}
+
+	return prefixes, nil
+}
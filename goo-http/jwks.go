@@ -0,0 +1,413 @@
+package goohttp
+
+import (
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"math/big"
+	"net/http"
+	"os"
+	"sync"
+	"time"
+)
+
+// JWTKey JWT 签名/验签密钥
+type JWTKey struct {
+	Kid        string
+	Alg        string
+	Secret     []byte           // HS256 密钥
+	PrivateKey crypto.Signer    // RS256/ES256 私钥（签发使用）
+	PublicKey  crypto.PublicKey // RS256/ES256 公钥（验签使用）
+}
+
+// JWTKeyProvider 根据 kid 和算法获取验签密钥
+type JWTKeyProvider interface {
+	GetKey(ctx context.Context, kid string, alg string) (*JWTKey, error)
+}
+
+func NewHS256Key(kid string, secret []byte) *JWTKey {
+	return &JWTKey{
+		Kid:    kid,
+		Alg:    JWTAlgHS256,
+		Secret: secret,
+	}
+}
+
+// ParseJWTKeyPEM 解析 PEM 格式的私钥或公钥，算法根据密钥类型确定（RSA 为 RS256，P-256 为 ES256）
+func ParseJWTKeyPEM(kid string, data []byte) (*JWTKey, error) {
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, fmt.Errorf("%w: invalid pem", ErrInvalidKey)
+	}
+
+	var parsed any
+	var err error
+
+	switch block.Type {
+	case "RSA PRIVATE KEY":
+		parsed, err = x509.ParsePKCS1PrivateKey(block.Bytes)
+	case "EC PRIVATE KEY":
+		parsed, err = x509.ParseECPrivateKey(block.Bytes)
+	case "PRIVATE KEY":
+		parsed, err = x509.ParsePKCS8PrivateKey(block.Bytes)
+	case "RSA PUBLIC KEY":
+		parsed, err = x509.ParsePKCS1PublicKey(block.Bytes)
+	case "PUBLIC KEY":
+		parsed, err = x509.ParsePKIXPublicKey(block.Bytes)
+	case "CERTIFICATE":
+		var cert *x509.Certificate
+		if cert, err = x509.ParseCertificate(block.Bytes); err == nil {
+			parsed = cert.PublicKey
+		}
+	default:
+		return nil, fmt.Errorf("%w: unsupported pem type %s", ErrInvalidKey, block.Type)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidKey, err)
+	}
+
+	key := &JWTKey{Kid: kid}
+
+	switch k := parsed.(type) {
+	case *rsa.PrivateKey:
+		key.Alg, key.PrivateKey, key.PublicKey = JWTAlgRS256, k, &k.PublicKey
+	case *rsa.PublicKey:
+		key.Alg, key.PublicKey = JWTAlgRS256, k
+	case *ecdsa.PrivateKey:
+		key.Alg, key.PrivateKey, key.PublicKey = JWTAlgES256, k, &k.PublicKey
+	case *ecdsa.PublicKey:
+		key.Alg, key.PublicKey = JWTAlgES256, k
+	default:
+		return nil, fmt.Errorf("%w: unsupported key type %T", ErrInvalidKey, parsed)
+	}
+
+	if key.Alg == JWTAlgES256 {
+		if pub := key.PublicKey.(*ecdsa.PublicKey); pub.Curve != elliptic.P256() {
+			return nil, fmt.Errorf("%w: ES256 requires P-256 curve", ErrInvalidKey)
+		}
+	}
+
+	return key, nil
+}
+
+// LoadJWTKeyFile 从 PEM 文件加载密钥
+func LoadJWTKeyFile(kid string, path string) (*JWTKey, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return ParseJWTKeyPEM(kid, data)
+}
+
+// JWTKeySet 静态密钥集，支持基于 kid 的密钥轮换
+type JWTKeySet struct {
+	keys       map[string]*JWTKey
+	signingKid string
+	mu         sync.RWMutex
+}
+
+// NewJWTKeySet 创建密钥集，第一个密钥为签名密钥
+func NewJWTKeySet(keys ...*JWTKey) *JWTKeySet {
+	ks := &JWTKeySet{
+		keys: make(map[string]*JWTKey),
+	}
+
+	for _, key := range keys {
+		ks.Add(key)
+	}
+
+	if len(keys) > 0 {
+		ks.signingKid = keys[0].Kid
+	}
+
+	return ks
+}
+
+// Add 添加密钥（同 kid 覆盖）
+func (ks *JWTKeySet) Add(key *JWTKey) {
+	ks.mu.Lock()
+	defer ks.mu.Unlock()
+	ks.keys[key.Kid] = key
+}
+
+// Remove 移除密钥
+func (ks *JWTKeySet) Remove(kid string) {
+	ks.mu.Lock()
+	defer ks.mu.Unlock()
+	delete(ks.keys, kid)
+}
+
+// SetSigningKey 切换签名密钥
+func (ks *JWTKeySet) SetSigningKey(kid string) error {
+	ks.mu.Lock()
+	defer ks.mu.Unlock()
+
+	if _, ok := ks.keys[kid]; !ok {
+		return ErrJWTKeyNotFound
+	}
+	ks.signingKid = kid
+	return nil
+}
+
+// SigningKey 获取当前签名密钥
+func (ks *JWTKeySet) SigningKey() *JWTKey {
+	ks.mu.RLock()
+	defer ks.mu.RUnlock()
+	return ks.keys[ks.signingKid]
+}
+
+func (ks *JWTKeySet) GetKey(ctx context.Context, kid string, alg string) (*JWTKey, error) {
+	ks.mu.RLock()
+	defer ks.mu.RUnlock()
+
+	if kid != "" {
+		if key, ok := ks.keys[kid]; ok {
+			return key, nil
+		}
+		return nil, ErrJWTKeyNotFound
+	}
+
+	// 令牌未携带 kid 时，只有唯一匹配算法的密钥才可用
+	var found *JWTKey
+	for _, key := range ks.keys {
+		if key.Alg == alg {
+			if found != nil {
+				return nil, ErrJWTKeyNotFound
+			}
+			found = key
+		}
+	}
+	if found == nil {
+		return nil, ErrJWTKeyNotFound
+	}
+
+	return found, nil
+}
+
+var (
+	DefaultJWKSConfig = &JWKSConfig{
+		CacheTTL:           10 * time.Minute,
+		MinRefreshInterval: 1 * time.Minute,
+		Timeout:            5 * time.Second,
+	}
+)
+
+type JWKSConfig struct {
+	URL                string        // JWKS 地址
+	CacheTTL           time.Duration // 缓存时间（默认 10 分钟）
+	MinRefreshInterval time.Duration // 遇到未知 kid 时的最小刷新间隔，也是加载失败后的重试间隔（默认 1 分钟）
+	Timeout            time.Duration // 请求超时（默认 5 秒）
+	HTTPClient         *http.Client  // 自定义 HTTP 客户端
+}
+
+// JWKSProvider 从 JWKS 地址加载并缓存验签公钥
+// 加载失败后继续使用已缓存的密钥，并在 MinRefreshInterval 内不再请求，避免 JWKS 不可用时每个请求都等待超时
+type JWKSProvider struct {
+	config    *JWKSConfig
+	client    *http.Client
+	keys      map[string]*JWTKey
+	fetchedAt time.Time
+	failedAt  time.Time // 最近一次加载失败的时间
+	failedErr error     // 最近一次加载失败的错误
+	mu        sync.RWMutex
+	fetchMu   sync.Mutex
+}
+
+func NewJWKSProvider(config *JWKSConfig) *JWKSProvider {
+	if config == nil {
+		config = DefaultJWKSConfig
+	}
+
+	c := *config
+	if c.CacheTTL == 0 {
+		c.CacheTTL = DefaultJWKSConfig.CacheTTL
+	}
+	if c.MinRefreshInterval == 0 {
+		c.MinRefreshInterval = DefaultJWKSConfig.MinRefreshInterval
+	}
+	if c.Timeout == 0 {
+		c.Timeout = DefaultJWKSConfig.Timeout
+	}
+
+	client := c.HTTPClient
+	if client == nil {
+		client = &http.Client{Timeout: c.Timeout}
+	}
+
+	return &JWKSProvider{
+		config: &c,
+		client: client,
+		keys:   make(map[string]*JWTKey),
+	}
+}
+
+func (p *JWKSProvider) GetKey(ctx context.Context, kid string, alg string) (*JWTKey, error) {
+	p.mu.RLock()
+	key, ok := p.keys[kid]
+	expired := time.Since(p.fetchedAt) > p.config.CacheTTL
+	recent := time.Since(p.fetchedAt) < p.config.MinRefreshInterval
+	p.mu.RUnlock()
+
+	if ok && !expired {
+		return key, nil
+	}
+
+	// 未知 kid 可能是密钥已轮换，限制刷新频率以防被恶意 kid 打爆
+	if !ok && !expired && recent {
+		return nil, ErrJWTKeyNotFound
+	}
+
+	if err := p.Refresh(ctx); err != nil {
+		// 刷新失败时继续使用缓存中的密钥
+		if ok {
+			return key, nil
+		}
+		return nil, err
+	}
+
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+
+	if key, ok = p.keys[kid]; !ok {
+		return nil, ErrJWTKeyNotFound
+	}
+
+	return key, nil
+}
+
+// Refresh 重新加载 JWKS，距上次加载失败不足 MinRefreshInterval 时直接返回上次的错误
+func (p *JWKSProvider) Refresh(ctx context.Context) error {
+	p.fetchMu.Lock()
+	defer p.fetchMu.Unlock()
+
+	// 其他 goroutine 刚刚刷新过，或刚刚加载失败
+	p.mu.RLock()
+	fresh := time.Since(p.fetchedAt) < p.config.MinRefreshInterval && len(p.keys) > 0
+	backoff := p.failedErr != nil && time.Since(p.failedAt) < p.config.MinRefreshInterval
+	failedErr := p.failedErr
+	p.mu.RUnlock()
+	if fresh {
+		return nil
+	}
+	if backoff {
+		return failedErr
+	}
+
+	keys, err := p.fetch(ctx)
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if err != nil {
+		p.failedAt = time.Now()
+		p.failedErr = err
+		return err
+	}
+
+	p.keys = keys
+	p.fetchedAt = time.Now()
+	p.failedErr = nil
+
+	return nil
+}
+
+func (p *JWKSProvider) fetch(ctx context.Context) (map[string]*JWTKey, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, p.config.URL, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("fetch jwks failed: status %d", resp.StatusCode)
+	}
+
+	var set struct {
+		Keys []*jwk `json:"keys"`
+	}
+	if err = json.NewDecoder(resp.Body).Decode(&set); err != nil {
+		return nil, fmt.Errorf("decode jwks failed: %w", err)
+	}
+
+	keys := make(map[string]*JWTKey, len(set.Keys))
+	for _, item := range set.Keys {
+		if item.Use != "" && item.Use != "sig" {
+			continue
+		}
+		key, err := item.toKey()
+		if err != nil {
+			// 跳过不支持的密钥
+			continue
+		}
+		keys[key.Kid] = key
+	}
+
+	return keys, nil
+}
+
+// jwk JSON Web Key
+type jwk struct {
+	Kty string `json:"kty"`
+	Kid string `json:"kid"`
+	Use string `json:"use"`
+	Alg string `json:"alg"`
+	Crv string `json:"crv"`
+	N   string `json:"n"`
+	E   string `json:"e"`
+	X   string `json:"x"`
+	Y   string `json:"y"`
+}
+
+func (k *jwk) toKey() (*JWTKey, error) {
+	switch k.Kty {
+	case "RSA":
+		n, err := b64Decode(k.N)
+		if err != nil {
+			return nil, err
+		}
+		e, err := b64Decode(k.E)
+		if err != nil {
+			return nil, err
+		}
+		pub := &rsa.PublicKey{
+			N: new(big.Int).SetBytes(n),
+			E: int(new(big.Int).SetBytes(e).Int64()),
+		}
+		return &JWTKey{Kid: k.Kid, Alg: JWTAlgRS256, PublicKey: pub}, nil
+
+	case "EC":
+		if k.Crv != "P-256" {
+			return nil, errors.New("unsupported curve")
+		}
+		x, err := b64Decode(k.X)
+		if err != nil {
+			return nil, err
+		}
+		y, err := b64Decode(k.Y)
+		if err != nil {
+			return nil, err
+		}
+		pub := &ecdsa.PublicKey{
+			Curve: elliptic.P256(),
+			X:     new(big.Int).SetBytes(x),
+			Y:     new(big.Int).SetBytes(y),
+		}
+		return &JWTKey{Kid: k.Kid, Alg: JWTAlgES256, PublicKey: pub}, nil
+
+	}
+
+	// JWKS 是公开地址，不接受对称密钥（kty 为 oct），否则任何人都能用其中的密钥签发令牌
+	return nil, errors.New("unsupported key type")
+}
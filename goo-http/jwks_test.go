@@ -0,0 +1,47 @@
+package goohttp
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+)
+
+// JWKS 中的对称密钥（oct）会被忽略
+func TestJWKSProviderRejectsSymmetricKeys(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"keys":[{"kty":"oct","kid":"hs","k":"c2VjcmV0"}]}`))
+	}))
+	defer server.Close()
+
+	provider := NewJWKSProvider(&JWKSConfig{URL: server.URL})
+	if _, err := provider.GetKey(context.Background(), "hs", JWTAlgHS256); err != ErrJWTKeyNotFound {
+		t.Fatalf("err = %v, want %v", err, ErrJWTKeyNotFound)
+	}
+}
+
+// 加载失败后在 MinRefreshInterval 内不再请求
+func TestJWKSProviderBacksOffAfterFailure(t *testing.T) {
+	var fetches atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fetches.Add(1)
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+
+	config := &JWKSConfig{URL: server.URL}
+	provider := NewJWKSProvider(config)
+	for range 3 {
+		if _, err := provider.GetKey(context.Background(), "k1", JWTAlgRS256); err == nil {
+			t.Fatal("GetKey succeeded")
+		}
+	}
+
+	if n := fetches.Load(); n != 1 {
+		t.Fatalf("fetches = %d, want 1", n)
+	}
+	if config.CacheTTL != 0 {
+		t.Fatal("NewJWKSProvider modified the caller's config")
+	}
+}
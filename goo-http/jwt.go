@@ -0,0 +1,540 @@
+package goohttp
+
+import (
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	JWTAlgHS256 = "HS256"
+	JWTAlgRS256 = "RS256"
+	JWTAlgES256 = "ES256"
+)
+
+var (
+	ErrJWTMissing          = errors.New("缺少令牌")
+	ErrJWTMalformed        = errors.New("令牌格式错误")
+	ErrJWTUnsupportedAlg   = errors.New("不支持的签名算法")
+	ErrJWTKeyNotFound      = errors.New("签名密钥不存在")
+	ErrJWTInvalidSignature = errors.New("令牌签名无效")
+	ErrJWTExpired          = errors.New("令牌已过期")
+	ErrJWTNotValidYet      = errors.New("令牌尚未生效")
+	ErrJWTInvalidIssuer    = errors.New("令牌签发者无效")
+	ErrJWTInvalidAudience  = errors.New("令牌受众无效")
+)
+
+var (
+	DefaultJWTConfig = &JWTConfig{
+		TokenHeader: "Authorization",
+		TokenScheme: "Bearer",
+		Leeway:      30 * time.Second,
+		ExpiresIn:   2 * time.Hour,
+	}
+)
+
+type JWTConfig struct {
+	Keys        JWTKeyProvider // 验签密钥（静态密钥集或 JWKS）
+	SigningKeys *JWTKeySet     // 签发密钥集（Issue 使用其中的当前签名密钥）
+	Algorithms  []string       // 允许的算法，为空时允许 HS256/RS256/ES256
+	Issuer      string         // 签发者，非空时校验 iss
+	Audience    []string       // 受众，非空时要求 aud 至少包含其中之一
+	Leeway      time.Duration  // 时间校验容差（默认 30 秒），小于 0 表示不允许误差
+	ExpiresIn   time.Duration  // 签发令牌的有效期（默认 2 小时）
+	SkipPaths   []string       // 跳过认证的路径，以 * 结尾表示前缀匹配
+	TokenHeader string         // 令牌请求头（默认 Authorization）
+	TokenScheme string         // 令牌前缀（默认 Bearer）
+	TokenQuery  string         // 令牌查询参数名，为空时不从查询参数读取
+}
+
+// JWTClaims JWT 标准声明
+type JWTClaims struct {
+	Issuer    string      `json:"iss,omitempty"`
+	Subject   string      `json:"sub,omitempty"`
+	Audience  JWTAudience `json:"aud,omitempty"`
+	ExpiresAt int64       `json:"exp,omitempty"`
+	NotBefore int64       `json:"nbf,omitempty"`
+	IssuedAt  int64       `json:"iat,omitempty"`
+	ID        string      `json:"jti,omitempty"`
+}
+
+// JWTAudience aud 声明，兼容字符串和字符串数组
+type JWTAudience []string
+
+func (a JWTAudience) MarshalJSON() ([]byte, error) {
+	if len(a) == 1 {
+		return json.Marshal(a[0])
+	}
+	return json.Marshal([]string(a))
+}
+
+func (a *JWTAudience) UnmarshalJSON(data []byte) error {
+	var single string
+	if err := json.Unmarshal(data, &single); err == nil {
+		*a = JWTAudience{single}
+		return nil
+	}
+
+	var multi []string
+	if err := json.Unmarshal(data, &multi); err != nil {
+		return err
+	}
+	*a = multi
+	return nil
+}
+
+func (a JWTAudience) Contains(aud string) bool {
+	for _, v := range a {
+		if v == aud {
+			return true
+		}
+	}
+	return false
+}
+
+type jwtHeader struct {
+	Alg string `json:"alg"`
+	Typ string `json:"typ,omitempty"`
+	Kid string `json:"kid,omitempty"`
+}
+
+type JWTAuth struct {
+	config *JWTConfig
+	algs   map[string]bool
+}
+
+func NewJWTAuth(config *JWTConfig) *JWTAuth {
+	if config == nil {
+		config = DefaultJWTConfig
+	}
+
+	// 复制配置，填充默认值时不修改调用方的配置
+	c := *config
+	config = &c
+
+	if config.TokenHeader == "" {
+		config.TokenHeader = DefaultJWTConfig.TokenHeader
+		if config.TokenScheme == "" {
+			config.TokenScheme = DefaultJWTConfig.TokenScheme
+		}
+	}
+	if config.Leeway == 0 {
+		config.Leeway = DefaultJWTConfig.Leeway
+	}
+	if config.ExpiresIn == 0 {
+		config.ExpiresIn = DefaultJWTConfig.ExpiresIn
+	}
+	if config.Keys == nil && config.SigningKeys != nil {
+		config.Keys = config.SigningKeys
+	}
+
+	algs := make(map[string]bool)
+	if len(config.Algorithms) == 0 {
+		algs[JWTAlgHS256] = true
+		algs[JWTAlgRS256] = true
+		algs[JWTAlgES256] = true
+	}
+	for _, alg := range config.Algorithms {
+		algs[alg] = true
+	}
+
+	return &JWTAuth{
+		config: config,
+		algs:   algs,
+	}
+}
+
+// Issue 签发令牌
+// claims 可以是 *JWTClaims，也可以是内嵌 JWTClaims 的自定义结构体
+// 未设置的 iss、aud、iat、exp 会按配置自动填充
+func (a *JWTAuth) Issue(claims any) (string, error) {
+	if a.config.SigningKeys == nil {
+		return "", ErrJWTKeyNotFound
+	}
+
+	key := a.config.SigningKeys.SigningKey()
+	if key == nil {
+		return "", ErrJWTKeyNotFound
+	}
+
+	payload, err := json.Marshal(claims)
+	if err != nil {
+		return "", err
+	}
+
+	// 补充标准声明
+	var fields map[string]any
+	if err = json.Unmarshal(payload, &fields); err != nil {
+		return "", fmt.Errorf("claims must be a json object: %w", err)
+	}
+
+	now := time.Now()
+	if _, ok := fields["iat"]; !ok {
+		fields["iat"] = now.Unix()
+	}
+	if _, ok := fields["exp"]; !ok {
+		fields["exp"] = now.Add(a.config.ExpiresIn).Unix()
+	}
+	if _, ok := fields["iss"]; !ok && a.config.Issuer != "" {
+		fields["iss"] = a.config.Issuer
+	}
+	if _, ok := fields["aud"]; !ok && len(a.config.Audience) > 0 {
+		fields["aud"] = JWTAudience(a.config.Audience)
+	}
+
+	if payload, err = json.Marshal(fields); err != nil {
+		return "", err
+	}
+
+	header, err := json.Marshal(&jwtHeader{Alg: key.Alg, Typ: "JWT", Kid: key.Kid})
+	if err != nil {
+		return "", err
+	}
+
+	signingInput := b64Encode(header) + "." + b64Encode(payload)
+
+	signature, err := key.sign([]byte(signingInput))
+	if err != nil {
+		return "", err
+	}
+
+	return signingInput + "." + b64Encode(signature), nil
+}
+
+// Parse 验证令牌并返回标准声明，claims 非空时同时解码到 claims
+func (a *JWTAuth) Parse(ctx context.Context, token string, claims any) (*JWTClaims, error) {
+	_, payload, err := a.verify(ctx, token)
+	if err != nil {
+		return nil, err
+	}
+
+	std := &JWTClaims{}
+	if err = json.Unmarshal(payload, std); err != nil {
+		return nil, ErrJWTMalformed
+	}
+
+	if err = a.validate(std); err != nil {
+		return nil, err
+	}
+
+	if claims != nil {
+		if err = json.Unmarshal(payload, claims); err != nil {
+			return nil, ErrJWTMalformed
+		}
+	}
+
+	return std, nil
+}
+
+func (a *JWTAuth) verify(ctx context.Context, token string) (*jwtHeader, []byte, error) {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return nil, nil, ErrJWTMalformed
+	}
+
+	headerBytes, err := b64Decode(parts[0])
+	if err != nil {
+		return nil, nil, ErrJWTMalformed
+	}
+
+	header := &jwtHeader{}
+	if err = json.Unmarshal(headerBytes, header); err != nil {
+		return nil, nil, ErrJWTMalformed
+	}
+
+	if !a.algs[header.Alg] {
+		return nil, nil, ErrJWTUnsupportedAlg
+	}
+
+	if a.config.Keys == nil {
+		return nil, nil, ErrJWTKeyNotFound
+	}
+
+	key, err := a.config.Keys.GetKey(ctx, header.Kid, header.Alg)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	// 防止算法混淆：令牌声明的算法必须与密钥算法一致
+	if key.Alg != header.Alg {
+		return nil, nil, ErrJWTUnsupportedAlg
+	}
+
+	signature, err := b64Decode(parts[2])
+	if err != nil {
+		return nil, nil, ErrJWTMalformed
+	}
+
+	if err = key.verify([]byte(parts[0]+"."+parts[1]), signature); err != nil {
+		return nil, nil, err
+	}
+
+	payload, err := b64Decode(parts[1])
+	if err != nil {
+		return nil, nil, ErrJWTMalformed
+	}
+
+	return header, payload, nil
+}
+
+func (a *JWTAuth) validate(claims *JWTClaims) error {
+	now := time.Now()
+	leeway := max(a.config.Leeway, 0)
+
+	if claims.ExpiresAt > 0 && now.After(time.Unix(claims.ExpiresAt, 0).Add(leeway)) {
+		return ErrJWTExpired
+	}
+	if claims.NotBefore > 0 && now.Before(time.Unix(claims.NotBefore, 0).Add(-leeway)) {
+		return ErrJWTNotValidYet
+	}
+	if claims.IssuedAt > 0 && now.Before(time.Unix(claims.IssuedAt, 0).Add(-leeway)) {
+		return ErrJWTNotValidYet
+	}
+
+	if a.config.Issuer != "" && claims.Issuer != a.config.Issuer {
+		return ErrJWTInvalidIssuer
+	}
+
+	if len(a.config.Audience) > 0 {
+		matched := false
+		for _, aud := range a.config.Audience {
+			if claims.Audience.Contains(aud) {
+				matched = true
+				break
+			}
+		}
+		if !matched {
+			return ErrJWTInvalidAudience
+		}
+	}
+
+	return nil
+}
+
+// extractToken 从请求头或查询参数中提取令牌
+func (a *JWTAuth) extractToken(c *gin.Context) string {
+	if v := c.GetHeader(a.config.TokenHeader); v != "" {
+		if a.config.TokenScheme == "" {
+			return v
+		}
+		// 前缀与令牌之间必须有空格，如 "Bearer xxx"，前缀不区分大小写
+		prefix := a.config.TokenScheme + " "
+		if len(v) > len(prefix) && strings.EqualFold(v[:len(prefix)], prefix) {
+			return strings.TrimSpace(v[len(prefix):])
+		}
+		return ""
+	}
+
+	if a.config.TokenQuery != "" {
+		return c.Query(a.config.TokenQuery)
+	}
+
+	return ""
+}
+
+func (a *JWTAuth) skip(path string) bool {
+	for _, p := range a.config.SkipPaths {
+		if strings.HasSuffix(p, "*") {
+			if strings.HasPrefix(path, strings.TrimSuffix(p, "*")) {
+				return true
+			}
+		} else if p == path {
+			return true
+		}
+	}
+	return false
+}
+
+func (a *JWTAuth) authenticate(ctx *Context) bool {
+	if a.skip(ctx.Request.URL.Path) {
+		return true
+	}
+
+	token := a.extractToken(ctx.Context)
+	if token == "" {
+		ctx.Abort(http.StatusUnauthorized, 4010, ErrJWTMissing.Error())
+		return false
+	}
+
+	_, payload, err := a.verify(ctx.Request.Context(), token)
+	if err != nil {
+		ctx.Abort(http.StatusUnauthorized, 4011, err.Error())
+		return false
+	}
+
+	claims := &JWTClaims{}
+	if err = json.Unmarshal(payload, claims); err != nil {
+		ctx.Abort(http.StatusUnauthorized, 4011, ErrJWTMalformed.Error())
+		return false
+	}
+
+	if err = a.validate(claims); err != nil {
+		if errors.Is(err, ErrJWTExpired) {
+			ctx.Abort(http.StatusUnauthorized, 4012, err.Error())
+		} else {
+			ctx.Abort(http.StatusUnauthorized, 4011, err.Error())
+		}
+		return false
+	}
+
+	ctx.Set("jwt-claims", claims)
+	ctx.Set("jwt-payload", payload)
+
+	return true
+}
+
+// Handler 用于路由或路由组的认证处理函数
+func (a *JWTAuth) Handler() HandlerFunc {
+	return func(ctx *Context) {
+		a.authenticate(ctx)
+	}
+}
+
+func JWTMiddleware(auth *JWTAuth) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		ctx := &Context{Context: c}
+
+		if !auth.authenticate(ctx) {
+			return
+		}
+
+		c.Next()
+	}
+}
+
+// JWTClaims 获取当前请求的 JWT 标准声明，未认证时返回 nil
+func (c *Context) JWTClaims() *JWTClaims {
+	if v, ok := c.Context.Get("jwt-claims"); ok {
+		if claims, ok := v.(*JWTClaims); ok {
+			return claims
+		}
+	}
+	return nil
+}
+
+// BindJWTClaims 将当前请求的 JWT 声明解码到自定义结构体
+func (c *Context) BindJWTClaims(claims any) error {
+	v, ok := c.Context.Get("jwt-payload")
+	if !ok {
+		return ErrJWTMissing
+	}
+
+	payload, ok := v.([]byte)
+	if !ok {
+		return ErrJWTMalformed
+	}
+
+	return json.Unmarshal(payload, claims)
+}
+
+// JWTClaimsAs 将当前请求的 JWT 声明解码为 T
+func JWTClaimsAs[T any](c *Context) (*T, error) {
+	claims := new(T)
+	if err := c.BindJWTClaims(claims); err != nil {
+		return nil, err
+	}
+	return claims, nil
+}
+
+func (k *JWTKey) sign(input []byte) ([]byte, error) {
+	switch k.Alg {
+	case JWTAlgHS256:
+		if len(k.Secret) == 0 {
+			return nil, ErrJWTKeyNotFound
+		}
+		mac := hmac.New(sha256.New, k.Secret)
+		mac.Write(input)
+		return mac.Sum(nil), nil
+
+	case JWTAlgRS256:
+		priv, ok := k.PrivateKey.(*rsa.PrivateKey)
+		if !ok {
+			return nil, ErrJWTKeyNotFound
+		}
+		digest := sha256.Sum256(input)
+		return rsa.SignPKCS1v15(rand.Reader, priv, crypto.SHA256, digest[:])
+
+	case JWTAlgES256:
+		priv, ok := k.PrivateKey.(*ecdsa.PrivateKey)
+		if !ok {
+			return nil, ErrJWTKeyNotFound
+		}
+		digest := sha256.Sum256(input)
+		r, s, err := ecdsa.Sign(rand.Reader, priv, digest[:])
+		if err != nil {
+			return nil, err
+		}
+		// JWS 使用定长 r||s 格式
+		signature := make([]byte, 64)
+		r.FillBytes(signature[:32])
+		s.FillBytes(signature[32:])
+		return signature, nil
+	}
+
+	return nil, ErrJWTUnsupportedAlg
+}
+
+func (k *JWTKey) verify(input, signature []byte) error {
+	switch k.Alg {
+	case JWTAlgHS256:
+		expected, err := k.sign(input)
+		if err != nil {
+			return err
+		}
+		if !hmac.Equal(expected, signature) {
+			return ErrJWTInvalidSignature
+		}
+		return nil
+
+	case JWTAlgRS256:
+		pub, ok := k.PublicKey.(*rsa.PublicKey)
+		if !ok {
+			return ErrJWTKeyNotFound
+		}
+		digest := sha256.Sum256(input)
+		if err := rsa.VerifyPKCS1v15(pub, crypto.SHA256, digest[:], signature); err != nil {
+			return ErrJWTInvalidSignature
+		}
+		return nil
+
+	case JWTAlgES256:
+		pub, ok := k.PublicKey.(*ecdsa.PublicKey)
+		if !ok {
+			return ErrJWTKeyNotFound
+		}
+		if len(signature) != 64 {
+			return ErrJWTInvalidSignature
+		}
+		digest := sha256.Sum256(input)
+		r := new(big.Int).SetBytes(signature[:32])
+		s := new(big.Int).SetBytes(signature[32:])
+		if !ecdsa.Verify(pub, digest[:], r, s) {
+			return ErrJWTInvalidSignature
+		}
+		return nil
+	}
+
+	return ErrJWTUnsupportedAlg
+}
+
+func b64Encode(data []byte) string {
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+func b64Decode(s string) ([]byte, error) {
+	return base64.RawURLEncoding.DecodeString(strings.TrimRight(s, "="))
+}
@@ -0,0 +1,49 @@
+package goohttp
+
+import (
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// NewJWTAuth 复制配置后填充默认值，不修改调用方的配置
+func TestNewJWTAuthAppliesDefaultsToCopy(t *testing.T) {
+	config := &JWTConfig{SigningKeys: NewJWTKeySet(NewHS256Key("k1", []byte("secret")))}
+	auth := NewJWTAuth(config)
+
+	if auth.config.Leeway != 30*time.Second {
+		t.Fatalf("Leeway = %v, want 30s", auth.config.Leeway)
+	}
+	if config.Leeway != 0 || config.TokenHeader != "" || config.Keys != nil {
+		t.Fatalf("caller config modified: %+v", config)
+	}
+}
+
+func TestJWTAuthExtractTokenRequiresScheme(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	auth := NewJWTAuth(&JWTConfig{})
+
+	tests := []struct {
+		header string
+		want   string
+	}{
+		{"Bearer abc", "abc"},
+		{"bearer abc", "abc"},
+		{"BEARER  abc", "abc"},
+		{"Bearerabc", ""},
+		{"Bearer", ""},
+		{"Basic abc", ""},
+	}
+
+	for _, tt := range tests {
+		c, _ := gin.CreateTestContext(httptest.NewRecorder())
+		c.Request = httptest.NewRequest("GET", "/", nil)
+		c.Request.Header.Set("Authorization", tt.header)
+
+		if got := auth.extractToken(c); got != tt.want {
+			t.Errorf("extractToken(%q) = %q, want %q", tt.header, got, tt.want)
+		}
+	}
+}
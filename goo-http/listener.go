@@ -0,0 +1,154 @@
+package goohttp
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"os"
+)
+
+// 监听网络类型
+const (
+	NetworkTCP  = "tcp"
+	NetworkUnix = "unix"
+)
+
+var (
+	ErrNoListener = errors.New("没有配置监听地址")
+)
+
+// ListenerConfig 监听配置，一个 Server 可以同时监听多个地址（如公网 TLS 端口和内网 h2c 端口、unix socket）
+type ListenerConfig struct {
+	Network    string      `yaml:"network" json:"network"`         // 网络类型：tcp（默认）、unix
+	Addr       string      `yaml:"addr" json:"addr"`               // 监听地址，如 ":8443"、"/var/run/app.sock"
+	TLS        *TLSConfig  `yaml:"tls" json:"tls"`                 // TLS 配置，为空时使用明文 HTTP
+	H2C        bool        `yaml:"h2c" json:"h2c"`                 // 明文 HTTP/2（h2c，仅支持 prior knowledge），配置 TLS 时忽略
+	SocketMode os.FileMode `yaml:"socket_mode" json:"socket_mode"` // unix socket 文件权限（如 0660），为 0 时不修改
+
+	handler http.Handler // 处理请求的 Handler，为空时使用 Server 的路由（管理接口独立端口使用）
+}
+
+// serverListener 已打开的监听
+type serverListener struct {
+	config   *ListenerConfig
+	listener net.Listener
+	server   *http.Server
+	reloader *CertReloader
+}
+
+// listenerConfigs Addr 和 Listeners 合并后的监听配置
+func (s *Server) listenerConfigs() []*ListenerConfig {
+	var configs []*ListenerConfig
+	if s.config.Addr != "" {
+		config := &ListenerConfig{
+			Network: NetworkTCP,
+			Addr:    s.config.Addr,
+			H2C:     s.config.EnableH2C,
+		}
+		if s.config.EnableTLS {
+			config.TLS = s.config.TLSConfig
+			if config.TLS == nil {
+				config.TLS = DefaultTLSConfig
+			}
+		}
+		configs = append(configs, config)
+	}
+	configs = append(configs, s.config.Listeners...)
+
+	// 管理接口独立端口
+	if s.admin != nil && s.admin.engine != nil {
+		configs = append(configs, &ListenerConfig{
+			Network: NetworkTCP,
+			Addr:    s.admin.config.Addr,
+			handler: s.admin.engine,
+		})
+	}
+	return configs
+}
+
+func (s *Server) listen(config *ListenerConfig) (*serverListener, error) {
+	network := config.Network
+	if network == "" {
+		network = NetworkTCP
+	}
+
+	var handler http.Handler = s.engine
+	if config.handler != nil {
+		handler = config.handler
+	}
+
+	l := &serverListener{
+		config: config,
+		server: &http.Server{
+			Handler: handler,
+		},
+	}
+
+	var protocols http.Protocols
+	protocols.SetHTTP1(true)
+
+	if config.TLS != nil {
+		reloader, err := NewCertReloader(config.TLS)
+		if err != nil {
+			return nil, err
+		}
+		l.reloader = reloader
+		l.server.TLSConfig = reloader.TLSConfig()
+		protocols.SetHTTP2(!config.TLS.DisableHTTP2)
+	} else if config.H2C {
+		protocols.SetUnencryptedHTTP2(true)
+	}
+	l.server.Protocols = &protocols
+
+	// 清理上次异常退出残留的 socket 文件
+	if network == NetworkUnix {
+		if info, err := os.Stat(config.Addr); err == nil && info.Mode()&os.ModeSocket != 0 {
+			os.Remove(config.Addr)
+		}
+	}
+
+	listener, err := net.Listen(network, config.Addr)
+	if err != nil {
+		l.stop()
+		return nil, err
+	}
+	l.listener = listener
+
+	if network == NetworkUnix && config.SocketMode != 0 {
+		if err := os.Chmod(config.Addr, config.SocketMode); err != nil {
+			listener.Close()
+			l.stop()
+			return nil, err
+		}
+	}
+
+	return l, nil
+}
+
+func (l *serverListener) serve() error {
+	if l.reloader != nil {
+		return l.server.ServeTLS(l.listener, "", "")
+	}
+	return l.server.Serve(l.listener)
+}
+
+func (l *serverListener) stop() {
+	if l.reloader != nil {
+		l.reloader.Stop()
+	}
+}
+
+func (l *serverListener) String() string {
+	scheme := "http"
+	switch {
+	case l.reloader != nil:
+		scheme = "https"
+	case l.config.H2C:
+		scheme = "h2c"
+	}
+	if l.config.Network == NetworkUnix {
+		return fmt.Sprintf("%s+unix://%s", scheme, l.listener.Addr())
+	}
+	return fmt.Sprintf("%s://%s", scheme, l.listener.Addr())
+}
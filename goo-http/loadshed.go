@@ -0,0 +1,347 @@
+package goohttp
+
+import (
+	"context"
+	"errors"
+	"math"
+	"net/http"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// 并发限制算法
+const (
+	LoadShedGradient = "gradient" // Gradient2：比较短期与长期延迟，延迟上升时降低并发限制
+	LoadShedVegas    = "vegas"    // Vegas：根据最小延迟估算排队长度，排队增加时降低并发限制
+)
+
+// Priority 请求优先级，负载升高时按优先级从低到高拒绝
+type Priority int
+
+const (
+	PriorityNormal   Priority = iota // 普通请求，并发达到限制时拒绝
+	PriorityLow                      // 低优先级（如报表导出），并发达到限制的 LowRatio 时拒绝
+	PriorityHigh                     // 高优先级（如支付回调），允许超出限制的 HighRatio 倍
+	PriorityCritical                 // 关键请求（如健康检查、管理接口），不拒绝
+)
+
+var (
+	DefaultLoadShedConfig = &LoadShedConfig{
+		Algorithm:    LoadShedGradient,
+		InitialLimit: 100,
+		MinLimit:     10,
+		MaxLimit:     1000,
+		Tolerance:    1.5,
+		Smoothing:    0.2,
+		LowRatio:     0.8,
+		HighRatio:    1.2,
+		RetryAfter:   time.Second,
+	}
+)
+
+type LoadShedConfig struct {
+	Algorithm    string                        // 并发限制算法：gradient（默认）、vegas
+	InitialLimit int                           // 初始并发限制（默认 100）
+	MinLimit     int                           // 最小并发限制（默认 10）
+	MaxLimit     int                           // 最大并发限制（默认 1000）
+	Tolerance    float64                       // Gradient 允许的延迟增长倍数，超出后开始降低限制（默认 1.5）
+	Smoothing    float64                       // 限制调整的平滑系数，0~1，越大调整越快（默认 0.2）
+	LowRatio     float64                       // 低优先级请求在并发达到限制的该比例时被拒绝（默认 0.8）
+	HighRatio    float64                       // 高优先级请求允许达到限制的该倍数（默认 1.2）
+	RetryAfter   time.Duration                 // 拒绝时的 Retry-After（默认 1 秒）
+	Routes       map[string]Priority           // 路由优先级，如 "POST /api/pay/notify" 或 "/admin/*"（前缀匹配）
+	PriorityFunc func(ctx *Context) Priority   // 自定义优先级，优先于 Routes
+	OnShed       func(ctx *Context, limit int) // 拒绝请求时的回调（如记录日志、告警）
+}
+
+// AdaptiveLimiter 自适应并发限制器
+// 根据请求延迟动态调整并发限制，在服务被压垮之前拒绝请求（503 + Retry-After），
+// 与 RateLimiter 按速率限制不同，并发限制直接反映服务当前的处理能力
+type AdaptiveLimiter struct {
+	config    *LoadShedConfig
+	algorithm limitAlgorithm
+	inflight  atomic.Int64
+	limit     atomic.Int64
+	mu        sync.Mutex
+	estimated float64 // 估算的并发限制
+}
+
+func NewAdaptiveLimiter(config *LoadShedConfig) *AdaptiveLimiter {
+	if config == nil {
+		config = DefaultLoadShedConfig
+	}
+
+	c := *config
+	if c.Algorithm == "" {
+		c.Algorithm = DefaultLoadShedConfig.Algorithm
+	}
+	if c.MinLimit <= 0 {
+		c.MinLimit = DefaultLoadShedConfig.MinLimit
+	}
+	if c.MaxLimit <= 0 {
+		c.MaxLimit = DefaultLoadShedConfig.MaxLimit
+	}
+	if c.MaxLimit < c.MinLimit {
+		c.MaxLimit = c.MinLimit
+	}
+	if c.InitialLimit <= 0 {
+		c.InitialLimit = DefaultLoadShedConfig.InitialLimit
+	}
+	c.InitialLimit = min(max(c.InitialLimit, c.MinLimit), c.MaxLimit)
+	if c.Tolerance < 1 {
+		c.Tolerance = DefaultLoadShedConfig.Tolerance
+	}
+	if c.Smoothing <= 0 || c.Smoothing > 1 {
+		c.Smoothing = DefaultLoadShedConfig.Smoothing
+	}
+	if c.LowRatio <= 0 {
+		c.LowRatio = DefaultLoadShedConfig.LowRatio
+	}
+	if c.HighRatio <= 0 {
+		c.HighRatio = DefaultLoadShedConfig.HighRatio
+	}
+	if c.RetryAfter <= 0 {
+		c.RetryAfter = DefaultLoadShedConfig.RetryAfter
+	}
+
+	l := &AdaptiveLimiter{
+		config:    &c,
+		estimated: float64(c.InitialLimit),
+	}
+	l.limit.Store(int64(c.InitialLimit))
+
+	switch c.Algorithm {
+	case LoadShedVegas:
+		l.algorithm = &vegasLimit{}
+	default:
+		l.algorithm = &gradientLimit{
+			longRtt: newExpAvg(600, 10),
+		}
+	}
+
+	return l
+}
+
+// Limit 当前并发限制
+func (l *AdaptiveLimiter) Limit() int {
+	return int(l.limit.Load())
+}
+
+// Inflight 当前处理中的请求数
+func (l *AdaptiveLimiter) Inflight() int {
+	return int(l.inflight.Load())
+}
+
+// Acquire 获取执行许可，返回 false 时应拒绝请求；获取成功后必须调用 Release
+func (l *AdaptiveLimiter) Acquire(priority Priority) bool {
+	inflight := l.inflight.Add(1)
+	if priority == PriorityCritical {
+		return true
+	}
+
+	limit := float64(l.limit.Load())
+	switch priority {
+	case PriorityLow:
+		limit *= l.config.LowRatio
+	case PriorityHigh:
+		limit *= l.config.HighRatio
+	}
+
+	if float64(inflight) > limit {
+		l.inflight.Add(-1)
+		return false
+	}
+	return true
+}
+
+// Release 释放许可并根据请求延迟调整并发限制，dropped 表示请求失败（超时、5xx）
+func (l *AdaptiveLimiter) Release(rtt time.Duration, dropped bool) {
+	inflight := int(l.inflight.Add(-1)) + 1
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	limit := l.algorithm.update(l.config, l.estimated, rtt, inflight, dropped)
+	limit = math.Min(math.Max(limit, float64(l.config.MinLimit)), float64(l.config.MaxLimit))
+
+	l.estimated = limit
+	l.limit.Store(int64(limit))
+}
+
+func (l *AdaptiveLimiter) priority(ctx *Context) Priority {
+	if l.config.PriorityFunc != nil {
+		return l.config.PriorityFunc(ctx)
+	}
+	if p, ok := lookupRoute(l.config.Routes, ctx); ok {
+		return p
+	}
+	return PriorityNormal
+}
+
+// Wrap 路由级并发限制，包装处理函数
+func (l *AdaptiveLimiter) Wrap(handler HandlerFunc) HandlerFunc {
+	return func(ctx *Context) {
+		l.serve(ctx, func() {
+			handler(ctx)
+		})
+	}
+}
+
+func (l *AdaptiveLimiter) serve(ctx *Context, next func()) {
+	if !l.Acquire(l.priority(ctx)) {
+		if l.config.OnShed != nil {
+			l.config.OnShed(ctx, l.Limit())
+		}
+
+		retryAfter := int(math.Ceil(l.config.RetryAfter.Seconds()))
+		ctx.Header("Retry-After", strconv.Itoa(retryAfter))
+		ctx.writeResponse(http.StatusServiceUnavailable, BizErrOverload.Response(ctx))
+		ctx.Context.Abort()
+		return
+	}
+
+	start := time.Now()
+	dropped := true
+	defer func() {
+		l.Release(time.Since(start), dropped)
+	}()
+
+	next()
+
+	// 超时和服务端错误视为请求失败，降低并发限制
+	dropped = ctx.Writer.Status() >= http.StatusInternalServerError ||
+		errors.Is(ctx.Request.Context().Err(), context.DeadlineExceeded)
+}
+
+// LoadShedMiddleware 自适应过载保护中间件
+func LoadShedMiddleware(limiter *AdaptiveLimiter) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		limiter.serve(&Context{Context: c}, c.Next)
+	}
+}
+
+type limitAlgorithm interface {
+	update(config *LoadShedConfig, limit float64, rtt time.Duration, inflight int, dropped bool) float64
+}
+
+// gradientLimit Gradient2 算法
+// 长期延迟（指数移动平均）与当前延迟的比值作为梯度，延迟上升时梯度小于 1，按比例降低并发限制
+type gradientLimit struct {
+	longRtt *expAvg
+}
+
+func (g *gradientLimit) update(config *LoadShedConfig, limit float64, rtt time.Duration, inflight int, dropped bool) float64 {
+	shortRtt := float64(rtt)
+	if shortRtt <= 0 {
+		return limit
+	}
+	longRtt := g.longRtt.add(shortRtt)
+
+	// 延迟恢复后长期延迟下降较慢，加快收敛
+	if longRtt/shortRtt > 2 {
+		longRtt = g.longRtt.set(longRtt * 0.95)
+	}
+
+	// 并发远低于限制时延迟不能反映容量，不调整
+	if !dropped && float64(inflight) < limit/2 {
+		return limit
+	}
+
+	gradient := math.Max(0.5, math.Min(1, config.Tolerance*longRtt/shortRtt))
+	if dropped {
+		gradient = 0.5
+	}
+
+	queueSize := math.Sqrt(limit)
+	newLimit := limit*gradient + queueSize
+
+	return limit*(1-config.Smoothing) + newLimit*config.Smoothing
+}
+
+// vegasLimit Vegas 算法
+// 以最小延迟作为无负载延迟，limit * (1 - 最小延迟 / 当前延迟) 估算排队长度，排队少时增加、排队多时减少
+type vegasLimit struct {
+	rttNoLoad float64
+	samples   int
+}
+
+func (v *vegasLimit) update(config *LoadShedConfig, limit float64, rtt time.Duration, inflight int, dropped bool) float64 {
+	r := float64(rtt)
+	if r <= 0 {
+		return limit
+	}
+
+	// 定期重新探测无负载延迟，避免依赖过期的最小值
+	v.samples++
+	if v.samples > int(30*limit) {
+		v.samples = 0
+		v.rttNoLoad = 0
+	}
+
+	if v.rttNoLoad == 0 || r < v.rttNoLoad {
+		v.rttNoLoad = r
+		return limit
+	}
+
+	step := math.Max(1, math.Log10(limit))
+
+	var newLimit float64
+	switch {
+	case dropped:
+		newLimit = limit - step
+	case float64(inflight)*2 < limit:
+		return limit
+	default:
+		queueSize := math.Ceil(limit * (1 - v.rttNoLoad/r))
+		alpha, beta := 3*step, 6*step
+
+		switch {
+		case queueSize <= step:
+			newLimit = limit + beta
+		case queueSize < alpha:
+			newLimit = limit + step
+		case queueSize > beta:
+			newLimit = limit - step
+		default:
+			return limit
+		}
+	}
+
+	return limit*(1-config.Smoothing) + newLimit*config.Smoothing
+}
+
+// expAvg 指数移动平均，预热阶段使用算术平均
+type expAvg struct {
+	window int
+	warmup int
+	count  int
+	value  float64
+}
+
+func newExpAvg(window int, warmup int) *expAvg {
+	return &expAvg{
+		window: window,
+		warmup: warmup,
+	}
+}
+
+func (e *expAvg) add(sample float64) float64 {
+	if e.count < e.warmup {
+		e.count++
+		e.value += (sample - e.value) / float64(e.count)
+		return e.value
+	}
+
+	factor := 2 / float64(e.window+1)
+	e.value = e.value*(1-factor) + sample*factor
+	return e.value
+}
+
+func (e *expAvg) set(value float64) float64 {
+	e.value = value
+	return value
+}
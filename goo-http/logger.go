@@ -33,6 +33,9 @@ func (l *DefaultLogger) Warn(ctx context.Context, msg string, fields ...interfac
 	fmt.Printf("[WARN] %s %v\n", msg, fields)
 }
 
+// LogMiddleware 请求日志中间件，请求开始和结束时各输出一条日志
+//
+// Deprecated: 使用 AccessLogMiddleware，每个请求通过 goolog 输出一条记录
 func LogMiddleware(logger Logger) gin.HandlerFunc {
 	if logger == nil {
 		logger = &DefaultLogger{}
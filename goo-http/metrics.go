@@ -0,0 +1,208 @@
+package goohttp
+
+import (
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	goometrics "v2.googo.io/goo-metrics"
+)
+
+const (
+	// MetricsUnmatchedRoute 未匹配到路由时的 route 标签，避免原始地址导致标签数量失控
+	MetricsUnmatchedRoute = "unmatched"
+)
+
+var (
+	DefaultMetricsConfig = &MetricsConfig{
+		Path:        "/metrics",
+		Namespace:   "goohttp",
+		Buckets:     goometrics.DefBuckets,
+		SizeBuckets: goometrics.ExponentialBuckets(128, 4, 8),
+	}
+)
+
+type MetricsConfig struct {
+	Registry    *goometrics.Registry `yaml:"-" json:"-"`                       // 指标注册表（默认使用 goometrics.Default()）
+	Path        string               `yaml:"path" json:"path"`                 // 指标输出地址（默认 /metrics），为空时不注册
+	Namespace   string               `yaml:"namespace" json:"namespace"`       // 指标名称前缀（默认 goohttp）
+	Buckets     []float64            `yaml:"buckets" json:"buckets"`           // 请求耗时分桶（秒）
+	SizeBuckets []float64            `yaml:"size_buckets" json:"size_buckets"` // 响应大小分桶（字节）
+}
+
+// Metrics 请求指标
+type Metrics struct {
+	requests       *goometrics.CounterVec   // 请求数
+	duration       *goometrics.HistogramVec // 请求耗时
+	inflight       *goometrics.GaugeVec     // 处理中的请求数
+	responseSize   *goometrics.HistogramVec // 响应大小
+	rateLimited    *goometrics.CounterVec   // 限流拒绝数
+	decryptFailure *goometrics.CounterVec   // 解密失败数
+}
+
+var (
+	metricsCache   = make(map[metricsKey]*Metrics)
+	metricsCacheMu sync.Mutex
+)
+
+type metricsKey struct {
+	registry  *goometrics.Registry
+	namespace string
+}
+
+// NewMetrics 创建请求指标并注册到注册表
+// 同一注册表和前缀只注册一次，多个 Server 共用同一组指标
+func NewMetrics(config *MetricsConfig) (*Metrics, error) {
+	if config == nil {
+		config = DefaultMetricsConfig
+	}
+
+	registry := config.Registry
+	if registry == nil {
+		registry = goometrics.Default()
+	}
+
+	key := metricsKey{registry: registry, namespace: config.Namespace}
+
+	metricsCacheMu.Lock()
+	defer metricsCacheMu.Unlock()
+
+	if m, ok := metricsCache[key]; ok {
+		return m, nil
+	}
+
+	opts := func(name string, help string) goometrics.Opts {
+		return goometrics.Opts{Namespace: config.Namespace, Name: name, Help: help}
+	}
+
+	m := &Metrics{}
+	var err error
+
+	if m.requests, err = goometrics.NewCounterVec(opts("requests_total", "请求数"), "method", "route", "status", "code"); err != nil {
+		return nil, err
+	}
+	if m.duration, err = goometrics.NewHistogramVec(opts("request_duration_seconds", "请求耗时（秒）"), config.Buckets, "method", "route", "status"); err != nil {
+		return nil, err
+	}
+	if m.inflight, err = goometrics.NewGaugeVec(opts("requests_in_flight", "处理中的请求数"), "method", "route"); err != nil {
+		return nil, err
+	}
+	if m.responseSize, err = goometrics.NewHistogramVec(opts("response_size_bytes", "响应大小（字节）"), config.SizeBuckets, "method", "route"); err != nil {
+		return nil, err
+	}
+	if m.rateLimited, err = goometrics.NewCounterVec(opts("ratelimit_rejected_total", "限流拒绝数"), "limiter", "route", "reason"); err != nil {
+		return nil, err
+	}
+	if m.decryptFailure, err = goometrics.NewCounterVec(opts("decrypt_failures_total", "请求解密失败数"), "route", "reason"); err != nil {
+		return nil, err
+	}
+
+	collectors := []goometrics.Collector{m.requests, m.duration, m.inflight, m.responseSize, m.rateLimited, m.decryptFailure}
+	for i, c := range collectors {
+		if err := registry.Register(c); err != nil {
+			for _, registered := range collectors[:i] {
+				registry.Unregister(registered)
+			}
+			return nil, err
+		}
+	}
+
+	metricsCache[key] = m
+
+	return m, nil
+}
+
+// Middleware 记录请求指标
+func (m *Metrics) Middleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		ctx := &Context{Context: c}
+		ctx.Set("metrics", m)
+
+		start := time.Now()
+		writer := c.Writer
+		method := c.Request.Method
+		route := metricsRoute(ctx)
+
+		inflight := m.inflight.WithLabelValues(method, route)
+		inflight.Inc()
+		defer inflight.Dec()
+
+		c.Next()
+
+		status := strconv.Itoa(writer.Status())
+		code := ""
+		if v, ok := ctx.ResponseCode(); ok {
+			code = strconv.Itoa(v)
+		}
+
+		m.requests.WithLabelValues(method, route, status, code).Inc()
+		m.duration.WithLabelValues(method, route, status).Observe(time.Since(start).Seconds())
+
+		size := writer.Size()
+		if size < 0 {
+			size = 0
+		}
+		m.responseSize.WithLabelValues(method, route).Observe(float64(size))
+	}
+}
+
+// metricsRoute 使用路由模板（如 /users/:id）作为标签，而不是原始地址
+func metricsRoute(ctx *Context) string {
+	if route := ctx.FullPath(); route != "" {
+		return route
+	}
+	return MetricsUnmatchedRoute
+}
+
+// contextMetrics 获取指标中间件设置的指标对象，未启用指标时返回 nil
+func contextMetrics(ctx *Context) *Metrics {
+	if v, ok := ctx.Get("metrics"); ok {
+		if m, ok := v.(*Metrics); ok {
+			return m
+		}
+	}
+	return nil
+}
+
+// recordRateLimited 记录限流拒绝，reason 为 exceeded（超出限制）或 unavailable（存储不可用）
+func recordRateLimited(ctx *Context, limiter string, reason string) {
+	if m := contextMetrics(ctx); m != nil {
+		m.rateLimited.WithLabelValues(limiter, metricsRoute(ctx), reason).Inc()
+	}
+}
+
+// recordDecryptFailure 记录解密失败，reason 为 read（读取请求体失败）、decrypt（解密失败）、envelope（信封格式错误）、expired（信封已过期）或 key（密钥不存在）
+func recordDecryptFailure(ctx *Context, reason string) {
+	if m := contextMetrics(ctx); m != nil {
+		m.decryptFailure.WithLabelValues(metricsRoute(ctx), reason).Inc()
+	}
+}
+
+// setupMetrics 注册指标中间件和指标输出地址
+// 指标地址在中间件之前注册，抓取请求不计入指标，也不经过限流、认证等中间件
+func (s *Server) setupMetrics() {
+	config := s.config.MetricsConfig
+	if config == nil {
+		config = DefaultMetricsConfig
+	}
+
+	m, err := NewMetrics(config)
+	if err != nil {
+		panic(err)
+	}
+	s.metrics = m
+
+	if config.Path != "" {
+		registry := config.Registry
+		if registry == nil {
+			registry = goometrics.Default()
+		}
+		s.engine.GET(config.Path, gin.WrapH(registry.Handler()))
+	}
+}
+
+// Metrics 获取请求指标，未启用指标时返回 nil
+func (s *Server) Metrics() *Metrics {
+	return s.metrics
+}
@@ -0,0 +1,100 @@
+package goohttp
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	gooredis "v2.googo.io/goo-redis"
+)
+
+// NonceStore 防重放 nonce 存储
+type NonceStore interface {
+	// Use 记录 nonce，ttl 内重复使用时返回 false
+	Use(ctx context.Context, key string, ttl time.Duration) (bool, error)
+}
+
+// MemoryNonceStore 进程内 nonce 存储（单实例部署使用）
+type MemoryNonceStore struct {
+	entries map[string]time.Time
+	mu      sync.Mutex
+	stopCh  chan struct{}
+	wg      sync.WaitGroup
+}
+
+func NewMemoryNonceStore(cleanupInterval time.Duration) *MemoryNonceStore {
+	if cleanupInterval <= 0 {
+		cleanupInterval = time.Minute
+	}
+
+	s := &MemoryNonceStore{
+		entries: make(map[string]time.Time),
+		stopCh:  make(chan struct{}),
+	}
+
+	s.wg.Add(1)
+	go s.cleanup(cleanupInterval)
+
+	return s
+}
+
+func (s *MemoryNonceStore) Use(ctx context.Context, key string, ttl time.Duration) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := time.Now()
+	if expireAt, exists := s.entries[key]; exists && now.Before(expireAt) {
+		return false, nil
+	}
+
+	s.entries[key] = now.Add(ttl)
+	return true, nil
+}
+
+func (s *MemoryNonceStore) cleanup(interval time.Duration) {
+	defer s.wg.Done()
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case now := <-ticker.C:
+			s.mu.Lock()
+			for key, expireAt := range s.entries {
+				if now.After(expireAt) {
+					delete(s.entries, key)
+				}
+			}
+			s.mu.Unlock()
+		case <-s.stopCh:
+			return
+		}
+	}
+}
+
+func (s *MemoryNonceStore) Stop() {
+	close(s.stopCh)
+	s.wg.Wait()
+}
+
+// RedisNonceStore 基于 goo-redis 的 nonce 存储（多实例部署使用）
+type RedisNonceStore struct {
+	client *gooredis.Client
+	prefix string
+}
+
+func NewRedisNonceStore(client *gooredis.Client, prefix string) *RedisNonceStore {
+	if prefix == "" {
+		prefix = "goohttp:nonce:"
+	}
+
+	return &RedisNonceStore{
+		client: client,
+		prefix: prefix,
+	}
+}
+
+func (s *RedisNonceStore) Use(ctx context.Context, key string, ttl time.Duration) (bool, error) {
+	return s.client.Client().SetNX(ctx, s.prefix+key, 1, ttl).Result()
+}
@@ -0,0 +1,476 @@
+package goohttp
+
+import (
+	"errors"
+	"fmt"
+	"html/template"
+	"net/http"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	swaggerFiles "github.com/swaggo/files/v2"
+)
+
+const (
+	OpenAPIUISwagger = "swagger"
+	OpenAPIUIRedoc   = "redoc"
+
+	// UICDN 为 true 时从 CDN 加载的固定版本
+	OpenAPISwaggerUIVersion = "5.17.14"
+	OpenAPIRedocVersion     = "2.1.5"
+)
+
+var ErrOpenAPIUIAssetsRequired = errors.New("redoc 文档页面需要设置 UIAssetsDir、UIAssetsURL 或 UICDN")
+
+var (
+	DefaultOpenAPIConfig = &OpenAPIConfig{
+		Title:   "API",
+		Version: "1.0.0",
+		Path:    "/openapi.json",
+		UIPath:  "/docs",
+		UI:      OpenAPIUISwagger,
+	}
+)
+
+type OpenAPIConfig struct {
+	Title           string                            `yaml:"title" json:"title"`                 // 文档标题
+	Description     string                            `yaml:"description" json:"description"`     // 文档说明
+	Version         string                            `yaml:"version" json:"version"`             // 接口版本
+	Servers         []string                          `yaml:"servers" json:"servers"`             // 服务地址
+	Path            string                            `yaml:"path" json:"path"`                   // 文档地址（默认 /openapi.json）
+	UIPath          string                            `yaml:"ui_path" json:"ui_path"`             // 文档页面地址（默认 /docs，为空时不提供页面）
+	UI              string                            `yaml:"ui" json:"ui"`                       // 文档页面：swagger / redoc
+	UIAssetsDir     string                            `yaml:"ui_assets_dir" json:"ui_assets_dir"` // 页面静态资源目录（swagger-ui-dist 或 redoc bundles 的文件），挂载到 UIPath/assets 下；Swagger UI 为空时使用内置资源
+	UIAssetsURL     string                            `yaml:"ui_assets_url" json:"ui_assets_url"` // 页面静态资源地址（自建的 swagger-ui-dist / redoc 地址）
+	UICDN           bool                              `yaml:"ui_cdn" json:"ui_cdn"`               // 是否从 unpkg 加载固定版本的静态资源，UIAssetsDir 和 UIAssetsURL 都为空时才生效
+	SecuritySchemes map[string]*OpenAPISecurityScheme `yaml:"-" json:"-"`                         // 安全方案
+	DefaultSecurity []string                          `yaml:"-" json:"-"`                         // 默认安全方案（路由未设置时使用）
+}
+
+// OpenAPIDocument OpenAPI 3.1 文档
+type OpenAPIDocument struct {
+	OpenAPI    string                                  `json:"openapi"`
+	Info       OpenAPIInfo                             `json:"info"`
+	Servers    []OpenAPIServer                         `json:"servers,omitempty"`
+	Tags       []OpenAPITag                            `json:"tags,omitempty"`
+	Paths      map[string]map[string]*OpenAPIOperation `json:"paths"`
+	Components OpenAPIComponents                       `json:"components"`
+}
+
+type OpenAPIInfo struct {
+	Title       string `json:"title"`
+	Description string `json:"description,omitempty"`
+	Version     string `json:"version"`
+}
+
+type OpenAPIServer struct {
+	URL string `json:"url"`
+}
+
+type OpenAPITag struct {
+	Name string `json:"name"`
+}
+
+type OpenAPIOperation struct {
+	Tags        []string                    `json:"tags,omitempty"`
+	Summary     string                      `json:"summary,omitempty"`
+	Description string                      `json:"description,omitempty"`
+	OperationId string                      `json:"operationId,omitempty"`
+	Parameters  []*OpenAPIParameter         `json:"parameters,omitempty"`
+	RequestBody *OpenAPIRequestBody         `json:"requestBody,omitempty"`
+	Responses   map[string]*OpenAPIResponse `json:"responses"`
+	Deprecated  bool                        `json:"deprecated,omitempty"`
+	Security    []map[string][]string       `json:"security,omitempty"`
+}
+
+type OpenAPIParameter struct {
+	Name        string         `json:"name"`
+	In          string         `json:"in"`
+	Description string         `json:"description,omitempty"`
+	Required    bool           `json:"required,omitempty"`
+	Schema      *OpenAPISchema `json:"schema,omitempty"`
+}
+
+type OpenAPIRequestBody struct {
+	Required bool                         `json:"required,omitempty"`
+	Content  map[string]*OpenAPIMediaType `json:"content"`
+}
+
+type OpenAPIMediaType struct {
+	Schema *OpenAPISchema `json:"schema,omitempty"`
+}
+
+type OpenAPIResponse struct {
+	Description string                       `json:"description"`
+	Content     map[string]*OpenAPIMediaType `json:"content,omitempty"`
+}
+
+type OpenAPIComponents struct {
+	Schemas         map[string]*OpenAPISchema         `json:"schemas,omitempty"`
+	SecuritySchemes map[string]*OpenAPISecurityScheme `json:"securitySchemes,omitempty"`
+}
+
+// OpenAPISecurityScheme 安全方案，如 {Type: "http", Scheme: "bearer", BearerFormat: "JWT"}
+type OpenAPISecurityScheme struct {
+	Type         string `json:"type"`
+	Description  string `json:"description,omitempty"`
+	Name         string `json:"name,omitempty"`
+	In           string `json:"in,omitempty"`
+	Scheme       string `json:"scheme,omitempty"`
+	BearerFormat string `json:"bearerFormat,omitempty"`
+}
+
+var routeParamRegexp = regexp.MustCompile(`[:*]([A-Za-z0-9_]+)`)
+
+// OpenAPI 根据已注册的路由生成文档
+func (s *Server) OpenAPI() *OpenAPIDocument {
+	config := s.config.OpenAPIConfig
+	if config == nil {
+		config = DefaultOpenAPIConfig
+	}
+
+	doc := &OpenAPIDocument{
+		OpenAPI: "3.1.0",
+		Info: OpenAPIInfo{
+			Title:       config.Title,
+			Description: config.Description,
+			Version:     config.Version,
+		},
+		Paths: make(map[string]map[string]*OpenAPIOperation),
+	}
+
+	for _, url := range config.Servers {
+		doc.Servers = append(doc.Servers, OpenAPIServer{URL: url})
+	}
+
+	g := newSchemaGenerator()
+	g.schemas["ErrorResponse"] = &OpenAPISchema{
+		Type: "object",
+		Properties: map[string]*OpenAPISchema{
+			"code":     {Type: "integer", Description: "业务状态码"},
+			"message":  {Type: "string", Description: "错误消息"},
+			"data":     {Description: "附加数据"},
+			"trace_id": {Type: "string", Description: "追踪ID"},
+		},
+		Required: []string{"code", "message"},
+	}
+
+	tags := make(map[string]bool)
+
+	for _, route := range s.Routes() {
+		if route.hidden {
+			continue
+		}
+
+		path := routeParamRegexp.ReplaceAllString(route.path, "{$1}")
+		if doc.Paths[path] == nil {
+			doc.Paths[path] = make(map[string]*OpenAPIOperation)
+		}
+
+		op := buildOperation(g, route, config)
+		doc.Paths[path][strings.ToLower(route.method)] = op
+
+		for _, tag := range op.Tags {
+			if !tags[tag] {
+				tags[tag] = true
+				doc.Tags = append(doc.Tags, OpenAPITag{Name: tag})
+			}
+		}
+	}
+
+	doc.Components.Schemas = g.schemas
+	doc.Components.SecuritySchemes = config.SecuritySchemes
+
+	return doc
+}
+
+func buildOperation(g *schemaGenerator, route *Route, config *OpenAPIConfig) *OpenAPIOperation {
+	op := &OpenAPIOperation{
+		Tags:        route.tags,
+		Summary:     route.summary,
+		Description: route.description,
+		OperationId: route.operationId,
+		Deprecated:  route.deprecated,
+		Responses:   make(map[string]*OpenAPIResponse),
+	}
+
+	if op.OperationId == "" {
+		op.OperationId = defaultOperationId(route)
+	}
+
+	security := route.security
+	if security == nil {
+		security = config.DefaultSecurity
+	}
+	for _, name := range security {
+		op.Security = append(op.Security, map[string][]string{name: {}})
+	}
+
+	// 路径参数（未声明请求类型时也要输出）
+	declared := make(map[string]bool)
+
+	if route.requestType != nil {
+		op.Parameters, op.RequestBody = buildRequest(g, route)
+		for _, param := range op.Parameters {
+			if param.In == "path" {
+				declared[param.Name] = true
+			}
+		}
+	}
+
+	for _, match := range routeParamRegexp.FindAllStringSubmatch(route.path, -1) {
+		if !declared[match[1]] {
+			op.Parameters = append(op.Parameters, &OpenAPIParameter{
+				Name:     match[1],
+				In:       "path",
+				Required: true,
+				Schema:   &OpenAPISchema{Type: "string"},
+			})
+		}
+	}
+
+	// 成功响应：{code, message, data, trace_id}，成功时 code 为 0 会被省略
+	envelope := &OpenAPISchema{
+		Type: "object",
+		Properties: map[string]*OpenAPISchema{
+			"code":     {Type: "integer", Description: "业务状态码，成功为 0（省略）"},
+			"message":  {Type: "string", Description: "响应消息", Examples: []any{SuccessMessage}},
+			"trace_id": {Type: "string", Description: "追踪ID"},
+		},
+		Required: []string{"message"},
+	}
+	if route.responseType != nil {
+		envelope.Properties["data"] = g.schemaOf(route.responseType)
+	}
+	op.Responses[strconv.Itoa(http.StatusOK)] = &OpenAPIResponse{
+		Description: "成功",
+		Content:     jsonContent(envelope),
+	}
+
+	// 业务错误按 HTTP 状态码分组，未设置状态码时与 Fail 一致按 200 返回
+	errs := append([]*BizError(nil), route.errors...)
+	if route.requestType != nil {
+		errs = append(errs, BizErrInvalidParams)
+	}
+
+	groups := make(map[int][]string)
+	for _, err := range errs {
+		status := err.HTTPStatus
+		if status == 0 {
+			status = http.StatusOK
+		}
+		line := fmt.Sprintf("%d: %s", err.Code, err.Message(DefaultLanguage))
+		if !containsString(groups[status], line) {
+			groups[status] = append(groups[status], line)
+		}
+	}
+	for status, lines := range groups {
+		errorResponse := &OpenAPISchema{Ref: "#/components/schemas/ErrorResponse"}
+
+		// 与成功响应状态码相同的业务错误合并到成功响应中
+		if status == http.StatusOK {
+			success := op.Responses[strconv.Itoa(status)]
+			success.Description += "\n\n" + strings.Join(lines, "\n\n")
+			success.Content = jsonContent(&OpenAPISchema{OneOf: []*OpenAPISchema{envelope, errorResponse}})
+			continue
+		}
+
+		op.Responses[strconv.Itoa(status)] = &OpenAPIResponse{
+			Description: strings.Join(lines, "\n\n"),
+			Content:     jsonContent(errorResponse),
+		}
+	}
+
+	op.Responses["default"] = &OpenAPIResponse{
+		Description: "错误",
+		Content:     jsonContent(&OpenAPISchema{Ref: "#/components/schemas/ErrorResponse"}),
+	}
+
+	return op
+}
+
+// buildRequest 按 uri/form/header 标签生成参数，其余字段作为 JSON 请求体
+func buildRequest(g *schemaGenerator, route *Route) ([]*OpenAPIParameter, *OpenAPIRequestBody) {
+	t := route.requestType
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	if t.Kind() != reflect.Struct {
+		return nil, nil
+	}
+
+	var params []*OpenAPIParameter
+	var hasParams, hasBody bool
+
+	for _, field := range structFields(t) {
+		in, name := "", ""
+		if n, ok := tagName(field, "uri"); ok {
+			in, name = "path", n
+		} else if n, ok := tagName(field, "form"); ok {
+			in, name = "query", n
+		} else if n, ok := tagName(field, "header"); ok {
+			in, name = "header", n
+		}
+
+		if in == "" {
+			if _, ok := jsonFieldName(field); ok {
+				hasBody = true
+			}
+			continue
+		}
+
+		hasParams = true
+		params = append(params, &OpenAPIParameter{
+			Name:        name,
+			In:          in,
+			Description: fieldDescription(field),
+			Required:    in == "path" || fieldRequired(field),
+			Schema:      g.fieldSchema(field),
+		})
+	}
+
+	switch route.method {
+	case http.MethodGet, http.MethodHead, http.MethodDelete, http.MethodOptions:
+		hasBody = false
+	}
+	if !hasBody {
+		return params, nil
+	}
+
+	var schema *OpenAPISchema
+	if !hasParams && t.Name() != "" {
+		schema = g.refOf(t)
+	} else {
+		schema = g.structSchema(t, func(field reflect.StructField) bool {
+			_, uri := field.Tag.Lookup("uri")
+			_, form := field.Tag.Lookup("form")
+			_, header := field.Tag.Lookup("header")
+			return uri || form || header
+		})
+	}
+
+	return params, &OpenAPIRequestBody{
+		Required: true,
+		Content:  jsonContent(schema),
+	}
+}
+
+func jsonContent(schema *OpenAPISchema) map[string]*OpenAPIMediaType {
+	return map[string]*OpenAPIMediaType{
+		"application/json": {Schema: schema},
+	}
+}
+
+func defaultOperationId(route *Route) string {
+	var parts []string
+	for _, part := range strings.Split(route.path, "/") {
+		part = strings.TrimLeft(part, ":*")
+		if part != "" {
+			parts = append(parts, part)
+		}
+	}
+
+	return strings.ToLower(route.method) + "_" + strings.Join(parts, "_")
+}
+
+func containsString(items []string, s string) bool {
+	for _, item := range items {
+		if item == s {
+			return true
+		}
+	}
+	return false
+}
+
+// setupOpenAPI 注册文档地址和文档页面，在中间件之前注册，不经过认证、签名校验和加解密
+// Redoc 页面没有可用的静态资源时 panic
+func (s *Server) setupOpenAPI() {
+	config := s.config.OpenAPIConfig
+	if config == nil {
+		config = DefaultOpenAPIConfig
+	}
+
+	path := config.Path
+	if path == "" {
+		path = DefaultOpenAPIConfig.Path
+	}
+
+	s.engine.GET(path, func(c *gin.Context) {
+		c.JSON(http.StatusOK, s.OpenAPI())
+	})
+
+	if config.UIPath == "" {
+		return
+	}
+
+	page, file, version := openAPISwaggerPage, "swagger-ui-dist", OpenAPISwaggerUIVersion
+	if config.UI == OpenAPIUIRedoc {
+		page, file, version = openAPIRedocPage, "redoc", OpenAPIRedocVersion
+	}
+
+	// 默认使用自建的静态资源，CDN 需要显式开启且固定版本
+	var assets string
+	switch {
+	case config.UIAssetsDir != "":
+		assets = strings.TrimRight(config.UIPath, "/") + "/assets"
+		s.engine.Static(assets, config.UIAssetsDir)
+	case config.UIAssetsURL != "":
+		assets = strings.TrimRight(config.UIAssetsURL, "/")
+	case config.UICDN:
+		assets = "https://unpkg.com/" + file + "@" + version
+		if config.UI == OpenAPIUIRedoc {
+			assets += "/bundles"
+		}
+	case config.UI != OpenAPIUIRedoc:
+		// Swagger UI 使用内置（go:embed）的 swagger-ui-dist
+		assets = strings.TrimRight(config.UIPath, "/") + "/assets"
+		s.engine.StaticFS(assets, http.FS(swaggerFiles.FS))
+	default:
+		panic(ErrOpenAPIUIAssetsRequired)
+	}
+
+	var buf strings.Builder
+	_ = page.Execute(&buf, map[string]string{
+		"Title":  config.Title,
+		"Spec":   path,
+		"Assets": assets,
+	})
+	html := buf.String()
+
+	s.engine.GET(config.UIPath, func(c *gin.Context) {
+		c.Data(http.StatusOK, "text/html; charset=utf-8", []byte(html))
+	})
+}
+
+var openAPISwaggerPage = template.Must(template.New("swagger").Parse(`<!DOCTYPE html>
+<html>
+<head>
+<meta charset="utf-8">
+<title>{{.Title}}</title>
+<link rel="stylesheet" href="{{.Assets}}/swagger-ui.css">
+</head>
+<body>
+<div id="swagger-ui"></div>
+<script src="{{.Assets}}/swagger-ui-bundle.js"></script>
+<script>
+window.ui = SwaggerUIBundle({url: "{{.Spec}}", dom_id: "#swagger-ui", deepLinking: true});
+</script>
+</body>
+</html>
+`))
+
+var openAPIRedocPage = template.Must(template.New("redoc").Parse(`<!DOCTYPE html>
+<html>
+<head>
+<meta charset="utf-8">
+<title>{{.Title}}</title>
+</head>
+<body>
+<redoc spec-url="{{.Spec}}"></redoc>
+<script src="{{.Assets}}/redoc.standalone.js"></script>
+</body>
+</html>
+`))
@@ -0,0 +1,404 @@
+package goohttp
+
+import (
+	"encoding/json"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// OpenAPISchema JSON Schema（OpenAPI 3.1）
+type OpenAPISchema struct {
+	Ref                  string                    `json:"$ref,omitempty"`
+	Type                 string                    `json:"type,omitempty"`
+	Format               string                    `json:"format,omitempty"`
+	Title                string                    `json:"title,omitempty"`
+	Description          string                    `json:"description,omitempty"`
+	Properties           map[string]*OpenAPISchema `json:"properties,omitempty"`
+	Required             []string                  `json:"required,omitempty"`
+	Items                *OpenAPISchema            `json:"items,omitempty"`
+	AdditionalProperties *OpenAPISchema            `json:"additionalProperties,omitempty"`
+	OneOf                []*OpenAPISchema          `json:"oneOf,omitempty"`
+	Enum                 []any                     `json:"enum,omitempty"`
+	Default              any                       `json:"default,omitempty"`
+	Examples             []any                     `json:"examples,omitempty"`
+	Minimum              *float64                  `json:"minimum,omitempty"`
+	Maximum              *float64                  `json:"maximum,omitempty"`
+	ExclusiveMinimum     *float64                  `json:"exclusiveMinimum,omitempty"`
+	ExclusiveMaximum     *float64                  `json:"exclusiveMaximum,omitempty"`
+	MinLength            *int                      `json:"minLength,omitempty"`
+	MaxLength            *int                      `json:"maxLength,omitempty"`
+	MinItems             *int                      `json:"minItems,omitempty"`
+	MaxItems             *int                      `json:"maxItems,omitempty"`
+	ContentEncoding      string                    `json:"contentEncoding,omitempty"`
+}
+
+var (
+	timeType       = reflect.TypeOf(time.Time{})
+	durationType   = reflect.TypeOf(time.Duration(0))
+	rawMessageType = reflect.TypeOf(json.RawMessage{})
+	bytesType      = reflect.TypeOf([]byte{})
+
+	schemaNameRegexp = regexp.MustCompile(`[^A-Za-z0-9_.-]+`)
+)
+
+// schemaGenerator 根据 Go 类型生成 schema，具名结构体放入 components
+type schemaGenerator struct {
+	schemas map[string]*OpenAPISchema
+	names   map[reflect.Type]string
+}
+
+func newSchemaGenerator() *schemaGenerator {
+	return &schemaGenerator{
+		schemas: make(map[string]*OpenAPISchema),
+		names:   make(map[reflect.Type]string),
+	}
+}
+
+func (g *schemaGenerator) schemaOf(t reflect.Type) *OpenAPISchema {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+
+	switch t {
+	case timeType:
+		return &OpenAPISchema{Type: "string", Format: "date-time"}
+	case durationType:
+		return &OpenAPISchema{Type: "integer", Format: "int64", Description: "纳秒"}
+	case rawMessageType:
+		return &OpenAPISchema{}
+	case bytesType:
+		return &OpenAPISchema{Type: "string", ContentEncoding: "base64"}
+	}
+
+	switch t.Kind() {
+	case reflect.Bool:
+		return &OpenAPISchema{Type: "boolean"}
+	case reflect.Int, reflect.Int64:
+		return &OpenAPISchema{Type: "integer", Format: "int64"}
+	case reflect.Int8, reflect.Int16, reflect.Int32:
+		return &OpenAPISchema{Type: "integer", Format: "int32"}
+	case reflect.Uint, reflect.Uint64, reflect.Uintptr:
+		return &OpenAPISchema{Type: "integer", Format: "int64", Minimum: float64Ptr(0)}
+	case reflect.Uint8, reflect.Uint16, reflect.Uint32:
+		return &OpenAPISchema{Type: "integer", Format: "int32", Minimum: float64Ptr(0)}
+	case reflect.Float32:
+		return &OpenAPISchema{Type: "number", Format: "float"}
+	case reflect.Float64:
+		return &OpenAPISchema{Type: "number", Format: "double"}
+	case reflect.String:
+		return &OpenAPISchema{Type: "string"}
+	case reflect.Slice, reflect.Array:
+		return &OpenAPISchema{Type: "array", Items: g.schemaOf(t.Elem())}
+	case reflect.Map:
+		return &OpenAPISchema{Type: "object", AdditionalProperties: g.schemaOf(t.Elem())}
+	case reflect.Struct:
+		if t.Name() == "" {
+			return g.structSchema(t, nil)
+		}
+		return g.refOf(t)
+	}
+
+	// interface 等任意类型
+	return &OpenAPISchema{}
+}
+
+// refOf 具名结构体生成 components 引用
+func (g *schemaGenerator) refOf(t reflect.Type) *OpenAPISchema {
+	if name, ok := g.names[t]; ok {
+		return &OpenAPISchema{Ref: "#/components/schemas/" + name}
+	}
+
+	name := g.schemaName(t)
+	g.names[t] = name
+	// 先占位，避免递归类型无限展开
+	g.schemas[name] = &OpenAPISchema{}
+	*g.schemas[name] = *g.structSchema(t, nil)
+
+	return &OpenAPISchema{Ref: "#/components/schemas/" + name}
+}
+
+func (g *schemaGenerator) schemaName(t reflect.Type) string {
+	name := schemaNameRegexp.ReplaceAllString(t.Name(), "_")
+	name = strings.Trim(name, "_")
+
+	if _, exists := g.schemas[name]; !exists {
+		return name
+	}
+
+	// 不同包下的同名类型
+	pkg := t.PkgPath()
+	if i := strings.LastIndex(pkg, "/"); i >= 0 {
+		pkg = pkg[i+1:]
+	}
+	name = schemaNameRegexp.ReplaceAllString(pkg, "_") + "." + name
+
+	for i := 2; ; i++ {
+		if _, exists := g.schemas[name]; !exists {
+			return name
+		}
+		name = strings.TrimRight(name, "0123456789") + strconv.Itoa(i)
+	}
+}
+
+// structSchema 生成结构体 schema，skip 返回 true 的字段不输出
+func (g *schemaGenerator) structSchema(t reflect.Type, skip func(field reflect.StructField) bool) *OpenAPISchema {
+	schema := &OpenAPISchema{
+		Type:       "object",
+		Properties: make(map[string]*OpenAPISchema),
+	}
+
+	for _, field := range structFields(t) {
+		if skip != nil && skip(field) {
+			continue
+		}
+
+		name, ok := jsonFieldName(field)
+		if !ok {
+			continue
+		}
+
+		prop := g.fieldSchema(field)
+		schema.Properties[name] = prop
+
+		if fieldRequired(field) {
+			schema.Required = append(schema.Required, name)
+		}
+	}
+
+	return schema
+}
+
+func (g *schemaGenerator) fieldSchema(field reflect.StructField) *OpenAPISchema {
+	prop := g.schemaOf(field.Type)
+
+	// 引用类型不能附加其他关键字（兼容 3.0 工具），只保留说明
+	if prop.Ref != "" {
+		if desc := fieldDescription(field); desc != "" {
+			return &OpenAPISchema{Ref: prop.Ref, Description: desc}
+		}
+		return prop
+	}
+
+	prop.Description = fieldDescription(field)
+	applyValidationRules(prop, field)
+
+	if def := formDefault(field); def != "" {
+		prop.Default = parseSchemaValue(prop.Type, def)
+	}
+	if example := field.Tag.Get("example"); example != "" {
+		prop.Examples = []any{parseSchemaValue(prop.Type, example)}
+	}
+
+	return prop
+}
+
+// structFields 展开匿名嵌入字段
+func structFields(t reflect.Type) []reflect.StructField {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	if t.Kind() != reflect.Struct {
+		return nil
+	}
+
+	var fields []reflect.StructField
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+
+		if field.Anonymous && field.Tag.Get("json") == "" {
+			ft := field.Type
+			for ft.Kind() == reflect.Ptr {
+				ft = ft.Elem()
+			}
+			if ft.Kind() == reflect.Struct {
+				fields = append(fields, structFields(ft)...)
+				continue
+			}
+		}
+
+		if !field.IsExported() {
+			continue
+		}
+
+		fields = append(fields, field)
+	}
+
+	return fields
+}
+
+func jsonFieldName(field reflect.StructField) (string, bool) {
+	tag := field.Tag.Get("json")
+	if tag == "-" {
+		return "", false
+	}
+
+	name := strings.SplitN(tag, ",", 2)[0]
+	if name == "" {
+		name = field.Name
+	}
+
+	return name, true
+}
+
+func tagName(field reflect.StructField, key string) (string, bool) {
+	tag, ok := field.Tag.Lookup(key)
+	if !ok {
+		return "", false
+	}
+
+	name := strings.SplitN(tag, ",", 2)[0]
+	if name == "-" {
+		return "", false
+	}
+	if name == "" {
+		name = field.Name
+	}
+
+	return name, true
+}
+
+func fieldDescription(field reflect.StructField) string {
+	if desc := field.Tag.Get("description"); desc != "" {
+		return desc
+	}
+	return field.Tag.Get("label")
+}
+
+func fieldRules(field reflect.StructField) []string {
+	tag := field.Tag.Get("binding")
+	if tag == "" {
+		tag = field.Tag.Get("validate")
+	}
+	if tag == "" {
+		return nil
+	}
+
+	var rules []string
+	for _, rule := range strings.Split(tag, ",") {
+		// dive 之后的规则作用于元素
+		if rule == "dive" {
+			break
+		}
+		rules = append(rules, rule)
+	}
+
+	return rules
+}
+
+func fieldRequired(field reflect.StructField) bool {
+	for _, rule := range fieldRules(field) {
+		if rule == "required" {
+			return true
+		}
+	}
+	return false
+}
+
+// formDefault 读取 form 标签中的默认值，如 form:"page,default=1"
+func formDefault(field reflect.StructField) string {
+	for _, opt := range strings.Split(field.Tag.Get("form"), ",")[1:] {
+		if strings.HasPrefix(opt, "default=") {
+			return strings.TrimPrefix(opt, "default=")
+		}
+	}
+	return ""
+}
+
+// applyValidationRules 将 binding 校验规则转换为 schema 约束
+func applyValidationRules(schema *OpenAPISchema, field reflect.StructField) {
+	for _, rule := range fieldRules(field) {
+		name, param, _ := strings.Cut(rule, "=")
+
+		switch name {
+		case "min", "max", "len":
+			n, err := strconv.ParseFloat(param, 64)
+			if err != nil {
+				continue
+			}
+			switch schema.Type {
+			case "string":
+				if name != "max" {
+					schema.MinLength = intPtr(int(n))
+				}
+				if name != "min" {
+					schema.MaxLength = intPtr(int(n))
+				}
+			case "array":
+				if name != "max" {
+					schema.MinItems = intPtr(int(n))
+				}
+				if name != "min" {
+					schema.MaxItems = intPtr(int(n))
+				}
+			case "integer", "number":
+				if name != "max" {
+					schema.Minimum = float64Ptr(n)
+				}
+				if name != "min" {
+					schema.Maximum = float64Ptr(n)
+				}
+			}
+		case "gte", "lte", "gt", "lt":
+			n, err := strconv.ParseFloat(param, 64)
+			if err != nil || (schema.Type != "integer" && schema.Type != "number") {
+				continue
+			}
+			switch name {
+			case "gte":
+				schema.Minimum = float64Ptr(n)
+			case "lte":
+				schema.Maximum = float64Ptr(n)
+			case "gt":
+				schema.ExclusiveMinimum = float64Ptr(n)
+			case "lt":
+				schema.ExclusiveMaximum = float64Ptr(n)
+			}
+		case "oneof":
+			schema.Enum = nil
+			for _, v := range strings.Fields(param) {
+				schema.Enum = append(schema.Enum, parseSchemaValue(schema.Type, v))
+			}
+		case "email":
+			schema.Format = "email"
+		case "url", "uri":
+			schema.Format = "uri"
+		case "uuid", "uuid4":
+			schema.Format = "uuid"
+		case "ip", "ipv4":
+			schema.Format = "ipv4"
+		case "ipv6", "hostname":
+			schema.Format = name
+		case "datetime":
+			schema.Format = "date-time"
+		}
+	}
+}
+
+func parseSchemaValue(typ string, v string) any {
+	switch typ {
+	case "integer":
+		if n, err := strconv.ParseInt(v, 10, 64); err == nil {
+			return n
+		}
+	case "number":
+		if n, err := strconv.ParseFloat(v, 64); err == nil {
+			return n
+		}
+	case "boolean":
+		if b, err := strconv.ParseBool(v); err == nil {
+			return b
+		}
+	}
+	return v
+}
+
+func float64Ptr(v float64) *float64 {
+	return &v
+}
+
+func intPtr(v int) *int {
+	return &v
+}
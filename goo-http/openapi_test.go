@@ -0,0 +1,46 @@
+package goohttp
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+)
+
+// 默认配置下文档页面使用内置的 Swagger UI 资源，且不经过 JWT 认证
+func TestOpenAPIDefaultUIServesEmbeddedAssets(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	server := New(
+		WithEnableLog(false),
+		WithEnableOpenAPI(true),
+		WithEnableJWT(true),
+		WithJWTAuth(NewJWTAuth(&JWTConfig{SigningKeys: NewJWTKeySet(NewHS256Key("k1", []byte("secret")))})),
+	)
+
+	for _, path := range []string{"/openapi.json", "/docs", "/docs/assets/swagger-ui-bundle.js", "/docs/assets/swagger-ui.css"} {
+		w := httptest.NewRecorder()
+		server.ServeHTTP(w, httptest.NewRequest(http.MethodGet, path, nil))
+		if w.Code != http.StatusOK {
+			t.Fatalf("GET %s status = %d", path, w.Code)
+		}
+	}
+
+	w := httptest.NewRecorder()
+	server.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/docs", nil))
+	if !strings.Contains(w.Body.String(), `src="/docs/assets/swagger-ui-bundle.js"`) {
+		t.Fatalf("page = %s", w.Body)
+	}
+}
+
+func TestOpenAPIRedocRequiresAssets(t *testing.T) {
+	defer func() {
+		if r := recover(); r != ErrOpenAPIUIAssetsRequired {
+			t.Fatalf("recover = %v, want %v", r, ErrOpenAPIUIAssetsRequired)
+		}
+	}()
+
+	New(WithEnableOpenAPI(true), WithOpenAPIConfig(&OpenAPIConfig{UIPath: "/docs", UI: OpenAPIUIRedoc}))
+}
@@ -1,12 +1,28 @@
 package goohttp
 
 import (
+	"context"
+	"errors"
+	"math"
 	"net/http"
+	"strconv"
 	"sync"
 	"time"
 
 	"github.com/gin-gonic/gin"
-	"golang.org/x/time/rate"
+)
+
+// RateLimitFallback 限流存储不可用时的降级策略
+type RateLimitFallback string
+
+const (
+	RateLimitFallbackLocal RateLimitFallback = "local" // 降级为进程内限流（默认）
+	RateLimitFallbackAllow RateLimitFallback = "allow" // 放行
+	RateLimitFallbackDeny  RateLimitFallback = "deny"  // 拒绝
+)
+
+var (
+	ErrRateLimitStoreReply = errors.New("限流存储返回数据格式错误")
 )
 
 var (
@@ -18,31 +34,31 @@ var (
 		},
 		CleanupInterval: 5 * time.Minute,
 		MaxIdleTime:     10 * time.Minute,
+		Fallback:        RateLimitFallbackLocal,
 	}
 )
 
 type RateLimitKeyFunc func(*Context) string
 
 type RateLimitConfig struct {
-	Rate            float64          // 每秒允许的请求数
-	Burst           int              // 突发请求数
-	KeyFunc         RateLimitKeyFunc // 提取限流key的函数
-	CleanupInterval time.Duration    // 清理不活跃限流器的间隔（默认 5 分钟）
-	MaxIdleTime     time.Duration    // 限流器最大空闲时间（默认 10 分钟）
-}
-
-type RateLimiterEntry struct {
-	limiter    *rate.Limiter
-	lastAccess time.Time
-	mu         sync.RWMutex
+	Name            string                        // 限流器名称，多个限流器共用存储时用于区分 key
+	Rate            float64                       // 每秒允许的请求数
+	Burst           int                           // 突发请求数
+	KeyFunc         RateLimitKeyFunc              // 提取限流key的函数，返回空字符串时跳过限流
+	CleanupInterval time.Duration                 // 清理不活跃限流器的间隔（默认 5 分钟）
+	MaxIdleTime     time.Duration                 // 限流器最大空闲时间（默认 10 分钟）
+	Store           RateLimitStore                // 限流存储（默认进程内存储，多实例部署使用 RedisRateLimitStore）
+	Routes          map[string]RateLimit          // 按路由限流，key 为 "POST /api/orders" 或 "/api/orders"（匹配所有方法）
+	Fallback        RateLimitFallback             // 限流存储不可用时的降级策略（默认 local）
+	OnStoreError    func(ctx *Context, err error) // 限流存储出错时的回调（如记录日志、告警）
+	DisableHeaders  bool                          // 不输出 X-RateLimit-* 响应头
 }
 
 type RateLimiter struct {
-	config  *RateLimitConfig
-	entries map[string]*RateLimiterEntry
-	mu      sync.RWMutex
-	stopCh  chan struct{}
-	wg      sync.WaitGroup
+	config *RateLimitConfig
+	store  RateLimitStore
+	local  *MemoryRateLimitStore // 进程内存储（默认存储或降级存储）
+	mu     sync.RWMutex
 }
 
 func NewRateLimiter(config *RateLimitConfig) *RateLimiter {
@@ -50,115 +66,231 @@ func NewRateLimiter(config *RateLimitConfig) *RateLimiter {
 		config = DefaultRateLimitConfig
 	}
 
+	config = normalizeRateLimitConfig(config)
+
 	rl := &RateLimiter{
-		config:  config,
-		entries: make(map[string]*RateLimiterEntry),
-		stopCh:  make(chan struct{}),
+		config: config,
+		local:  NewMemoryRateLimitStore(config.CleanupInterval, config.MaxIdleTime),
 	}
 
-	rl.wg.Add(1)
-	go rl.cleanup()
+	rl.store = config.Store
+	if rl.store == nil {
+		rl.store = rl.local
+	}
 
 	return rl
 }
 
-func (rl *RateLimiter) getLimiterEntry(key string) *RateLimiterEntry {
+func normalizeRateLimitConfig(config *RateLimitConfig) *RateLimitConfig {
+	c := *config
+
+	if c.Rate <= 0 {
+		c.Rate = DefaultRateLimitConfig.Rate
+	}
+	if c.Burst <= 0 {
+		c.Burst = int(math.Ceil(c.Rate))
+	}
+	if c.KeyFunc == nil {
+		c.KeyFunc = DefaultRateLimitConfig.KeyFunc
+	}
+	if c.CleanupInterval <= 0 {
+		c.CleanupInterval = DefaultRateLimitConfig.CleanupInterval
+	}
+	if c.MaxIdleTime <= 0 {
+		c.MaxIdleTime = DefaultRateLimitConfig.MaxIdleTime
+	}
+	if c.Fallback == "" {
+		c.Fallback = RateLimitFallbackLocal
+	}
+
+	if len(c.Routes) > 0 {
+		routes := make(map[string]RateLimit, len(c.Routes))
+		for route, limit := range c.Routes {
+			if limit.Rate <= 0 {
+				limit.Rate = c.Rate
+			}
+			if limit.Burst <= 0 {
+				limit.Burst = int(math.Ceil(limit.Rate))
+			}
+			routes[route] = limit
+		}
+		c.Routes = routes
+	}
+
+	return &c
+}
+
+func (rl *RateLimiter) getConfig() *RateLimitConfig {
 	rl.mu.RLock()
-	entry, exists := rl.entries[key]
-	rl.mu.RUnlock()
+	defer rl.mu.RUnlock()
+	return rl.config
+}
 
-	if exists {
-		return entry
+// Allow 按默认限额判断 key 是否放行
+func (rl *RateLimiter) Allow(key string) bool {
+	config := rl.getConfig()
+
+	result, err := rl.take(context.Background(), config, key, RateLimit{Rate: config.Rate, Burst: config.Burst})
+	if result == nil {
+		return err == nil || config.Fallback != RateLimitFallbackDeny
 	}
 
-	rl.mu.Lock()
-	defer rl.mu.Unlock()
+	return result.Allowed
+}
 
-	if entry, exists = rl.entries[key]; exists {
-		return entry
+// Take 按当前请求的路由限额取令牌，KeyFunc 返回空字符串时返回 nil
+func (rl *RateLimiter) Take(ctx *Context) (*RateLimitResult, error) {
+	config := rl.getConfig()
+
+	key := config.KeyFunc(ctx)
+	if key == "" {
+		return nil, nil
 	}
 
-	entry = &RateLimiterEntry{
-		limiter:    rate.NewLimiter(rate.Limit(rl.config.Rate), rl.config.Burst),
-		lastAccess: time.Now(),
+	limit := RateLimit{Rate: config.Rate, Burst: config.Burst}
+
+	if route, routeLimit, ok := config.matchRoute(ctx); ok {
+		key = route + "|" + key
+		limit = routeLimit
 	}
 
-	rl.entries[key] = entry
+	if config.Name != "" {
+		key = config.Name + ":" + key
+	}
 
-	return entry
+	result, err := rl.take(ctx.Request.Context(), config, key, limit)
+	if err != nil && config.OnStoreError != nil {
+		config.OnStoreError(ctx, err)
+	}
+
+	return result, err
 }
 
-func (rl *RateLimiter) Allow(key string) bool {
-	entry := rl.getLimiterEntry(key)
+func (rl *RateLimiter) take(ctx context.Context, config *RateLimitConfig, key string, limit RateLimit) (*RateLimitResult, error) {
+	rl.mu.RLock()
+	store := rl.store
+	rl.mu.RUnlock()
+
+	result, err := store.Take(ctx, key, limit)
+	if err == nil {
+		return result, nil
+	}
+
+	if config.Fallback == RateLimitFallbackLocal && store != RateLimitStore(rl.local) {
+		if result, localErr := rl.local.Take(ctx, key, limit); localErr == nil {
+			return result, err
+		}
+	}
 
-	entry.mu.RLock()
-	allowed := entry.limiter.Allow()
-	entry.mu.RUnlock()
+	return nil, err
+}
 
-	entry.mu.Lock()
-	entry.lastAccess = time.Now()
-	entry.mu.Unlock()
+func (c *RateLimitConfig) matchRoute(ctx *Context) (string, RateLimit, bool) {
+	if len(c.Routes) == 0 {
+		return "", RateLimit{}, false
+	}
 
-	return allowed
+	path := ctx.FullPath()
+	if path == "" {
+		return "", RateLimit{}, false
+	}
+
+	route := ctx.Request.Method + " " + path
+	if limit, ok := c.Routes[route]; ok {
+		return route, limit, true
+	}
+	if limit, ok := c.Routes[path]; ok {
+		return path, limit, true
+	}
+
+	return "", RateLimit{}, false
 }
 
+// UpdateConfig 更新限流配置，新配置未指定 Store 时沿用原存储
 func (rl *RateLimiter) UpdateConfig(config *RateLimitConfig) {
+	config = normalizeRateLimitConfig(config)
+
 	rl.mu.Lock()
 	defer rl.mu.Unlock()
 
-	for _, entry := range rl.entries {
-		entry.mu.Lock()
-		entry.limiter = rate.NewLimiter(rate.Limit(config.Rate), config.Burst)
-		entry.mu.Unlock()
+	if config.Store != nil {
+		rl.store = config.Store
 	}
+
+	// 进程内令牌桶在下次取令牌时按新的速率和容量调整
+	rl.config = config
 }
 
-func (rl *RateLimiter) cleanup() {
-	defer rl.wg.Done()
+func (rl *RateLimiter) Stop() {
+	rl.local.Stop()
+}
 
-	ticker := time.NewTicker(rl.config.CleanupInterval)
-	defer ticker.Stop()
+// Handler 路由级限流
+func (rl *RateLimiter) Handler() HandlerFunc {
+	limiters := []*RateLimiter{rl}
+	return func(ctx *Context) {
+		applyRateLimit(ctx, limiters)
+	}
+}
 
-	handler := func(now time.Time) {
-		rl.mu.Lock()
-		defer rl.mu.Unlock()
+// applyRateLimit 依次检查限流器，响应头取剩余令牌最少的结果
+func applyRateLimit(ctx *Context, limiters []*RateLimiter) bool {
+	var tightest *RateLimitResult
 
-		for key, entry := range rl.entries {
-			entry.mu.RLock()
-			idleTime := now.Sub(entry.lastAccess)
-			entry.mu.RUnlock()
+	for _, limiter := range limiters {
+		config := limiter.getConfig()
 
-			if idleTime > rl.config.MaxIdleTime {
-				delete(rl.entries, key)
+		result, err := limiter.Take(ctx)
+		if err != nil && result == nil {
+			if config.Fallback == RateLimitFallbackDeny {
+				recordRateLimited(ctx, config.Name, "unavailable")
+				ctx.Abort(http.StatusServiceUnavailable, 5030, "Rate limit unavailable")
+				return false
 			}
+			continue
+		}
+		if result == nil {
+			continue
 		}
-	}
 
-	for {
-		select {
-		case <-ticker.C:
-			handler(time.Now())
-		case <-rl.stopCh:
-			return
+		if !result.Allowed {
+			if !config.DisableHeaders {
+				setRateLimitHeaders(ctx, result)
+			}
+			retryAfter := int(math.Ceil(result.RetryAfter.Seconds()))
+			if retryAfter < 1 {
+				retryAfter = 1
+			}
+			ctx.Header("Retry-After", strconv.Itoa(retryAfter))
+			recordRateLimited(ctx, config.Name, "exceeded")
+			ctx.Abort(http.StatusTooManyRequests, 4290, "Rate limit exceeded")
+			return false
 		}
+
+		if !config.DisableHeaders && (tightest == nil || result.Remaining < tightest.Remaining) {
+			tightest = result
+		}
+	}
+
+	if tightest != nil {
+		setRateLimitHeaders(ctx, tightest)
 	}
+
+	return true
 }
 
-func (rl *RateLimiter) Stop() {
-	close(rl.stopCh)
-	rl.wg.Wait()
+func setRateLimitHeaders(ctx *Context, result *RateLimitResult) {
+	ctx.Header("X-RateLimit-Limit", strconv.Itoa(result.Limit))
+	ctx.Header("X-RateLimit-Remaining", strconv.Itoa(result.Remaining))
+	ctx.Header("X-RateLimit-Reset", strconv.Itoa(int(math.Ceil(result.ResetAfter.Seconds()))))
 }
 
 func RateLimitMiddleware(limiters []*RateLimiter) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		ctx := &Context{Context: c}
 
-		for _, limiter := range limiters {
-			key := limiter.config.KeyFunc(ctx)
-			if !limiter.Allow(key) {
-				ctx.Abort(http.StatusTooManyRequests, 4290, "Rate limit exceeded")
-				return
-			}
+		if !applyRateLimit(ctx, limiters) {
+			return
 		}
 
 		c.Next()
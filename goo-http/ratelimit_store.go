@@ -0,0 +1,246 @@
+package goohttp
+
+import (
+	"context"
+	"math"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/go-redis/redis/v8"
+	"golang.org/x/time/rate"
+	gooredis "v2.googo.io/goo-redis"
+)
+
+// RateLimit 令牌桶参数
+type RateLimit struct {
+	Rate  float64 `yaml:"rate" json:"rate"`   // 每秒允许的请求数
+	Burst int     `yaml:"burst" json:"burst"` // 突发请求数（桶容量）
+}
+
+// RateLimitResult 限流结果
+type RateLimitResult struct {
+	Allowed    bool          // 是否放行
+	Limit      int           // 桶容量
+	Remaining  int           // 剩余令牌数
+	ResetAfter time.Duration // 令牌桶恢复满额所需时间
+	RetryAfter time.Duration // 被拒绝时，距离下一个可用令牌的时间
+}
+
+// RateLimitStore 限流存储
+type RateLimitStore interface {
+	// Take 从 key 对应的令牌桶中取一个令牌
+	Take(ctx context.Context, key string, limit RateLimit) (*RateLimitResult, error)
+}
+
+type RateLimiterEntry struct {
+	limiter    *rate.Limiter
+	lastAccess time.Time
+	mu         sync.RWMutex
+}
+
+// MemoryRateLimitStore 进程内令牌桶（单实例部署使用）
+type MemoryRateLimitStore struct {
+	entries     map[string]*RateLimiterEntry
+	maxIdleTime time.Duration
+	mu          sync.RWMutex
+	stopCh      chan struct{}
+	stopOnce    sync.Once
+	wg          sync.WaitGroup
+}
+
+// NewMemoryRateLimitStore 创建进程内限流存储，空闲超过 maxIdleTime 的令牌桶会被定期清理
+func NewMemoryRateLimitStore(cleanupInterval, maxIdleTime time.Duration) *MemoryRateLimitStore {
+	if cleanupInterval <= 0 {
+		cleanupInterval = DefaultRateLimitConfig.CleanupInterval
+	}
+	if maxIdleTime <= 0 {
+		maxIdleTime = DefaultRateLimitConfig.MaxIdleTime
+	}
+
+	s := &MemoryRateLimitStore{
+		entries:     make(map[string]*RateLimiterEntry),
+		maxIdleTime: maxIdleTime,
+		stopCh:      make(chan struct{}),
+	}
+
+	s.wg.Add(1)
+	go s.cleanup(cleanupInterval)
+
+	return s
+}
+
+func (s *MemoryRateLimitStore) getLimiterEntry(key string, limit RateLimit) *RateLimiterEntry {
+	s.mu.RLock()
+	entry, exists := s.entries[key]
+	s.mu.RUnlock()
+
+	if !exists {
+		s.mu.Lock()
+		if entry, exists = s.entries[key]; !exists {
+			entry = &RateLimiterEntry{
+				limiter:    rate.NewLimiter(rate.Limit(limit.Rate), limit.Burst),
+				lastAccess: time.Now(),
+			}
+			s.entries[key] = entry
+		}
+		s.mu.Unlock()
+	}
+
+	return entry
+}
+
+func (s *MemoryRateLimitStore) Take(ctx context.Context, key string, limit RateLimit) (*RateLimitResult, error) {
+	entry := s.getLimiterEntry(key, limit)
+
+	entry.mu.Lock()
+	defer entry.mu.Unlock()
+
+	// 配置变更后沿用当前令牌数，只调整速率和容量
+	if entry.limiter.Limit() != rate.Limit(limit.Rate) {
+		entry.limiter.SetLimit(rate.Limit(limit.Rate))
+	}
+	if entry.limiter.Burst() != limit.Burst {
+		entry.limiter.SetBurst(limit.Burst)
+	}
+
+	now := time.Now()
+	entry.lastAccess = now
+
+	allowed := entry.limiter.AllowN(now, 1)
+	tokens := entry.limiter.TokensAt(now)
+
+	return newRateLimitResult(allowed, tokens, limit), nil
+}
+
+func (s *MemoryRateLimitStore) cleanup(interval time.Duration) {
+	defer s.wg.Done()
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	handler := func(now time.Time) {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+
+		for key, entry := range s.entries {
+			entry.mu.RLock()
+			idleTime := now.Sub(entry.lastAccess)
+			entry.mu.RUnlock()
+
+			if idleTime > s.maxIdleTime {
+				delete(s.entries, key)
+			}
+		}
+	}
+
+	for {
+		select {
+		case <-ticker.C:
+			handler(time.Now())
+		case <-s.stopCh:
+			return
+		}
+	}
+}
+
+func (s *MemoryRateLimitStore) Stop() {
+	s.stopOnce.Do(func() {
+		close(s.stopCh)
+	})
+	s.wg.Wait()
+}
+
+// redisTokenBucketScript 原子令牌桶
+// 使用 redis 服务器时间，避免各实例时钟不一致；剩余令牌以字符串返回，避免小数被截断
+// TIME 是非确定性命令，Redis 5 之前按脚本复制时会拒绝之后的写命令，先切换为按命令复制（Redis 5+ 默认如此，调用无副作用）
+var redisTokenBucketScript = redis.NewScript(`
+redis.replicate_commands()
+
+local key = KEYS[1]
+local rate = tonumber(ARGV[1])
+local burst = tonumber(ARGV[2])
+
+local t = redis.call('TIME')
+local now = tonumber(t[1]) * 1000000 + tonumber(t[2])
+
+local data = redis.call('HMGET', key, 'tokens', 'ts')
+local tokens = tonumber(data[1])
+local ts = tonumber(data[2])
+if tokens == nil or ts == nil then
+	tokens = burst
+	ts = now
+end
+
+local elapsed = math.max(0, now - ts)
+tokens = math.min(burst, tokens + elapsed * rate / 1000000)
+
+local allowed = 0
+if tokens >= 1 then
+	tokens = tokens - 1
+	allowed = 1
+end
+
+redis.call('HMSET', key, 'tokens', tostring(tokens), 'ts', tostring(now))
+redis.call('PEXPIRE', key, math.ceil(burst / rate * 1000) + 1000)
+
+return {allowed, tostring(tokens)}
+`)
+
+// RedisRateLimitStore 基于 goo-redis 的分布式令牌桶（多实例部署使用）
+type RedisRateLimitStore struct {
+	client *gooredis.Client
+	prefix string
+}
+
+func NewRedisRateLimitStore(client *gooredis.Client, prefix string) *RedisRateLimitStore {
+	if prefix == "" {
+		prefix = "goohttp:ratelimit:"
+	}
+
+	return &RedisRateLimitStore{
+		client: client,
+		prefix: prefix,
+	}
+}
+
+func (s *RedisRateLimitStore) Take(ctx context.Context, key string, limit RateLimit) (*RateLimitResult, error) {
+	values, err := redisTokenBucketScript.Run(ctx, s.client.Client(), []string{s.prefix + key}, limit.Rate, limit.Burst).Slice()
+	if err != nil {
+		return nil, err
+	}
+
+	if len(values) != 2 {
+		return nil, ErrRateLimitStoreReply
+	}
+
+	allowed, _ := values[0].(int64)
+	str, _ := values[1].(string)
+	tokens, err := strconv.ParseFloat(str, 64)
+	if err != nil {
+		return nil, ErrRateLimitStoreReply
+	}
+
+	return newRateLimitResult(allowed == 1, tokens, limit), nil
+}
+
+func newRateLimitResult(allowed bool, tokens float64, limit RateLimit) *RateLimitResult {
+	if tokens < 0 {
+		tokens = 0
+	}
+
+	result := &RateLimitResult{
+		Allowed:   allowed,
+		Limit:     limit.Burst,
+		Remaining: int(math.Floor(tokens)),
+	}
+
+	if limit.Rate > 0 {
+		result.ResetAfter = time.Duration((float64(limit.Burst) - tokens) / limit.Rate * float64(time.Second))
+		if !allowed {
+			result.RetryAfter = time.Duration((1 - tokens) / limit.Rate * float64(time.Second))
+		}
+	}
+
+	return result
+}
@@ -0,0 +1,190 @@
+package goohttp
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"net/url"
+	"runtime/debug"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	goolog "v2.googo.io/goo-log"
+)
+
+var (
+	DefaultRecoveryConfig = &RecoveryConfig{
+		SensitiveHeaders: []string{"Authorization", "Cookie", "Proxy-Authorization", "X-Api-Key", "X-Sign", "X-Signature", "X-Admin-Token", "X-CSRF-Token"},
+		SensitiveQuery:   []string{"token", "access_token", "refresh_token", "password", "secret", "sign", "signature"},
+	}
+)
+
+type RecoveryConfig struct {
+	Error            *BizError                               // 返回的业务错误（默认 BizErrInternalFailure，业务码 5000）
+	Logger           *goolog.Logger                          // 日志对象（默认 goolog.Default()）
+	PanicMapper      func(ctx *Context, recovered any) error // 将 panic 转换为错误（如 BizError），返回 nil 时使用 Error
+	OnPanic          func(info *PanicInfo)                   // 崩溃钩子（如告警），在独立的 goroutine 中执行
+	SensitiveHeaders []string                                // 日志中脱敏的请求头
+	SensitiveQuery   []string                                // 日志中脱敏的查询参数
+}
+
+// PanicInfo panic 信息
+type PanicInfo struct {
+	Value    any         // panic 的值
+	Stack    string      // 调用栈
+	TraceId  string      // 追踪ID
+	Method   string      // 请求方法
+	Route    string      // 路由模板
+	Path     string      // 请求地址（查询参数已脱敏）
+	ClientIP string      // 客户端IP
+	Header   http.Header // 请求头（已脱敏）
+	Time     time.Time   // 发生时间
+}
+
+// RecoveryMiddleware 恢复 panic，记录日志并返回统一响应
+func RecoveryMiddleware(config *RecoveryConfig) gin.HandlerFunc {
+	if config == nil {
+		config = DefaultRecoveryConfig
+	}
+
+	sensitiveHeaders := make(map[string]bool, len(config.SensitiveHeaders))
+	for _, name := range config.SensitiveHeaders {
+		sensitiveHeaders[http.CanonicalHeaderKey(name)] = true
+	}
+	sensitiveQuery := make(map[string]bool, len(config.SensitiveQuery))
+	for _, name := range config.SensitiveQuery {
+		sensitiveQuery[strings.ToLower(name)] = true
+	}
+
+	return func(c *gin.Context) {
+		defer func() {
+			r := recover()
+			if r == nil {
+				return
+			}
+
+			// http.ErrAbortHandler 用于主动中断响应，交给 net/http 处理
+			if r == http.ErrAbortHandler {
+				panic(r)
+			}
+
+			ctx := &Context{Context: c}
+
+			info := &PanicInfo{
+				Value:    r,
+				Stack:    string(debug.Stack()),
+				TraceId:  ctx.TraceId(),
+				Method:   c.Request.Method,
+				Route:    c.FullPath(),
+				Path:     sanitizeURL(c.Request.URL, sensitiveQuery),
+				ClientIP: ctx.ClientIP(),
+				Header:   sanitizeHeader(c.Request.Header, sensitiveHeaders),
+				Time:     time.Now(),
+			}
+
+			logger := config.Logger
+			if logger == nil {
+				logger = goolog.Default()
+			}
+
+			// 客户端已断开连接，无法写入响应
+			brokenPipe := isBrokenPipe(r)
+
+			entry := logger.WithField("trace-id", info.TraceId).
+				WithField("method", info.Method).
+				WithField("route", info.Route).
+				WithField("path", info.Path).
+				WithField("ip", info.ClientIP)
+
+			if brokenPipe {
+				entry.WarnF("[goo-http] connection broken: %v", r)
+				c.Abort()
+				return
+			}
+
+			entry.WithField("header", info.Header).
+				WithField("stack", info.Stack).
+				ErrorF("[goo-http] panic recovered: %v", r)
+
+			if config.OnPanic != nil {
+				go func() {
+					defer func() {
+						if r := recover(); r != nil {
+							log.Println(r)
+						}
+					}()
+					config.OnPanic(info)
+				}()
+			}
+
+			// 已经开始输出响应时只能中止
+			if c.Writer.Written() {
+				c.Abort()
+				return
+			}
+
+			var err error
+			if config.PanicMapper != nil {
+				err = config.PanicMapper(ctx, r)
+			}
+			if err == nil {
+				bizErr := config.Error
+				if bizErr == nil {
+					bizErr = BizErrInternalFailure
+				}
+				err = bizErr.Wrap(fmt.Errorf("panic: %v", r))
+			}
+
+			httpErr := MapError(ctx, err)
+
+			status := httpErr.Status
+			if status == 0 {
+				status = http.StatusInternalServerError
+			}
+
+			c.Error(err)
+			ctx.writeResponse(status, ErrorWithData(ctx, httpErr.Code, httpErr.Message, httpErr.Data))
+			c.Abort()
+		}()
+
+		c.Next()
+	}
+}
+
+func isBrokenPipe(r any) bool {
+	err, ok := r.(error)
+	if !ok {
+		return false
+	}
+	return errors.Is(err, syscall.EPIPE) || errors.Is(err, syscall.ECONNRESET)
+}
+
+func sanitizeHeader(header http.Header, sensitive map[string]bool) http.Header {
+	sanitized := make(http.Header, len(header))
+	for name, values := range header {
+		if sensitive[name] {
+			sanitized[name] = []string{"***"}
+			continue
+		}
+		sanitized[name] = append([]string(nil), values...)
+	}
+	return sanitized
+}
+
+func sanitizeURL(u *url.URL, sensitive map[string]bool) string {
+	if u.RawQuery == "" {
+		return u.Path
+	}
+
+	query := u.Query()
+	for name := range query {
+		if sensitive[strings.ToLower(name)] {
+			query[name] = []string{"***"}
+		}
+	}
+
+	return u.Path + "?" + query.Encode()
+}
@@ -0,0 +1,207 @@
+package goohttp
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"strconv"
+	"time"
+
+	goolog "v2.googo.io/goo-log"
+)
+
+var (
+	ErrEmptyServiceName = errors.New("服务名称为空")
+)
+
+var (
+	DefaultRegistryConfig = &RegistryConfig{
+		RetryInterval: 3 * time.Second,
+	}
+)
+
+// ServiceInstance 注册到注册中心的服务实例
+type ServiceInstance struct {
+	Id        string            `json:"id"`
+	Name      string            `json:"name"`
+	Address   string            `json:"address"`
+	Port      int               `json:"port"`
+	Scheme    string            `json:"scheme"` // http、https
+	Tags      []string          `json:"tags,omitempty"`
+	Metadata  map[string]string `json:"metadata,omitempty"`
+	Version   string            `json:"version,omitempty"`
+	HealthURL string            `json:"health_url,omitempty"` // 健康检查地址，未启用健康检查时为空
+}
+
+// Endpoint 实例地址，如 http://10.0.0.12:8080
+func (i *ServiceInstance) Endpoint() string {
+	return fmt.Sprintf("%s://%s", i.Scheme, net.JoinHostPort(i.Address, strconv.Itoa(i.Port)))
+}
+
+// Registry 注册中心
+// goo-http/registry 提供 consul、etcd 实现
+type Registry interface {
+	// Register 注册实例
+	Register(ctx context.Context, instance *ServiceInstance) error
+	// Deregister 注销实例
+	Deregister(ctx context.Context, instance *ServiceInstance) error
+	// Watch 阻塞直到实例从注册中心丢失（如 etcd 租约过期、consul agent 重启后丢失注册）或 ctx 取消，返回后重新注册
+	Watch(ctx context.Context, instance *ServiceInstance) error
+}
+
+type RegistryConfig struct {
+	Registry      Registry          `yaml:"-" json:"-"`                           // 注册中心
+	Name          string            `yaml:"name" json:"name"`                     // 服务名称
+	Id            string            `yaml:"id" json:"id"`                         // 实例 ID（默认 {Name}-{Address}-{Port}）
+	Address       string            `yaml:"address" json:"address"`               // 注册的地址（默认监听地址，监听所有地址时使用本机第一个非回环 IP）
+	Port          int               `yaml:"port" json:"port"`                     // 注册的端口（默认监听端口）
+	Tags          []string          `yaml:"tags" json:"tags"`                     // 标签
+	Metadata      map[string]string `yaml:"metadata" json:"metadata"`             // 元数据
+	Version       string            `yaml:"version" json:"version"`               // 服务版本
+	HealthPath    string            `yaml:"health_path" json:"health_path"`       // 健康检查路径（默认启用健康检查时使用 HealthConfig.ReadyPath）
+	RetryInterval time.Duration     `yaml:"retry_interval" json:"retry_interval"` // 注册失败或实例丢失后重新注册的间隔（默认 3 秒）
+	Logger        *goolog.Logger    `yaml:"-" json:"-"`                           // 日志对象（默认 goolog.Default()）
+}
+
+// registrar 维护实例注册：注册后监听实例状态，丢失后重新注册，关闭时注销
+type registrar struct {
+	config   *RegistryConfig
+	instance *ServiceInstance
+	logger   *goolog.Logger
+	cancel   context.CancelFunc
+	done     chan struct{}
+}
+
+// newRegistrar 根据监听地址生成实例信息
+func (s *Server) newRegistrar(l *serverListener) (*registrar, error) {
+	config := *s.config.Registry
+	if config.Name == "" {
+		return nil, ErrEmptyServiceName
+	}
+	if config.RetryInterval <= 0 {
+		config.RetryInterval = DefaultRegistryConfig.RetryInterval
+	}
+
+	host, port := "", 0
+	if addr, ok := l.listener.Addr().(*net.TCPAddr); ok {
+		port = addr.Port
+		if !addr.IP.IsUnspecified() {
+			host = addr.IP.String()
+		}
+	}
+	if config.Address != "" {
+		host = config.Address
+	}
+	if host == "" {
+		ip, err := localIP()
+		if err != nil {
+			return nil, err
+		}
+		host = ip
+	}
+	if config.Port > 0 {
+		port = config.Port
+	}
+
+	instance := &ServiceInstance{
+		Id:       config.Id,
+		Name:     config.Name,
+		Address:  host,
+		Port:     port,
+		Scheme:   "http",
+		Tags:     config.Tags,
+		Metadata: config.Metadata,
+		Version:  config.Version,
+	}
+	if instance.Id == "" {
+		instance.Id = fmt.Sprintf("%s-%s-%d", instance.Name, instance.Address, instance.Port)
+	}
+	if l.reloader != nil {
+		instance.Scheme = "https"
+	}
+
+	healthPath := config.HealthPath
+	if healthPath == "" && s.config.EnableHealth {
+		healthPath = s.healthConfig().ReadyPath
+		if healthPath == "" {
+			healthPath = s.healthConfig().HealthPath
+		}
+	}
+	if healthPath != "" {
+		instance.HealthURL = instance.Endpoint() + healthPath
+	}
+
+	logger := config.Logger
+	if logger == nil {
+		logger = goolog.Default()
+	}
+
+	return &registrar{
+		config:   &config,
+		instance: instance,
+		logger:   logger,
+		done:     make(chan struct{}),
+	}, nil
+}
+
+func (r *registrar) start() {
+	ctx, cancel := context.WithCancel(context.Background())
+	r.cancel = cancel
+	go r.run(ctx)
+}
+
+func (r *registrar) run(ctx context.Context) {
+	defer close(r.done)
+
+	registry := r.config.Registry
+	for {
+		err := registry.Register(ctx, r.instance)
+		if err == nil {
+			r.logger.InfoF("[goo-http] service %s registered: %s", r.instance.Id, r.instance.Endpoint())
+
+			err = registry.Watch(ctx, r.instance)
+			if ctx.Err() != nil {
+				return
+			}
+			r.logger.WithField("err", err).WarnF("[goo-http] service %s lost from registry, re-registering", r.instance.Id)
+		} else if ctx.Err() == nil {
+			r.logger.WithField("err", err).ErrorF("[goo-http] service %s register failed", r.instance.Id)
+		}
+
+		timer := time.NewTimer(r.config.RetryInterval)
+		select {
+		case <-timer.C:
+		case <-ctx.Done():
+			timer.Stop()
+			return
+		}
+	}
+}
+
+// stop 停止重新注册并注销实例
+func (r *registrar) stop(ctx context.Context) error {
+	r.cancel()
+	<-r.done
+
+	if err := r.config.Registry.Deregister(ctx, r.instance); err != nil {
+		r.logger.WithField("err", err).ErrorF("[goo-http] service %s deregister failed", r.instance.Id)
+		return err
+	}
+	r.logger.InfoF("[goo-http] service %s deregistered", r.instance.Id)
+	return nil
+}
+
+// localIP 本机第一个非回环 IPv4 地址
+func localIP() (string, error) {
+	addrs, err := net.InterfaceAddrs()
+	if err != nil {
+		return "", err
+	}
+	for _, addr := range addrs {
+		if ipNet, ok := addr.(*net.IPNet); ok && !ipNet.IP.IsLoopback() && ipNet.IP.To4() != nil {
+			return ipNet.IP.String(), nil
+		}
+	}
+	return "", errors.New("no available local ip")
+}
@@ -0,0 +1,118 @@
+package registry
+
+import (
+	"context"
+	"maps"
+	"net"
+	"strconv"
+	"time"
+
+	"github.com/hashicorp/consul/api"
+	gooconsul "v2.googo.io/goo-consul"
+	goohttp "v2.googo.io/goo-http"
+)
+
+var (
+	DefaultConsulConfig = &ConsulConfig{
+		CheckInterval:   10 * time.Second,
+		CheckTimeout:    5 * time.Second,
+		DeregisterAfter: time.Minute,
+		WatchInterval:   10 * time.Second,
+	}
+)
+
+type ConsulConfig struct {
+	CheckInterval   time.Duration // 健康检查间隔（默认 10 秒）
+	CheckTimeout    time.Duration // 健康检查超时（默认 5 秒）
+	DeregisterAfter time.Duration // 健康检查持续失败超过该时间后 consul 自动注销实例（默认 1 分钟）
+	TLSSkipVerify   bool          // https 健康检查不校验证书（自签名证书、mTLS 时使用）
+	WatchInterval   time.Duration // 检查实例是否仍在 consul agent 中的间隔（默认 10 秒）
+}
+
+type consulRegistry struct {
+	client *gooconsul.Client
+	config *ConsulConfig
+}
+
+// Consul consul 注册中心
+// 注册时附加指向实例健康检查地址的 HTTP 检查（未启用健康检查时使用 TCP 检查），consul agent 重启丢失注册后自动重新注册
+func Consul(client *gooconsul.Client, config *ConsulConfig) goohttp.Registry {
+	if config == nil {
+		config = DefaultConsulConfig
+	}
+
+	c := *config
+	if c.CheckInterval <= 0 {
+		c.CheckInterval = DefaultConsulConfig.CheckInterval
+	}
+	if c.CheckTimeout <= 0 {
+		c.CheckTimeout = DefaultConsulConfig.CheckTimeout
+	}
+	if c.DeregisterAfter <= 0 {
+		c.DeregisterAfter = DefaultConsulConfig.DeregisterAfter
+	}
+	if c.WatchInterval <= 0 {
+		c.WatchInterval = DefaultConsulConfig.WatchInterval
+	}
+
+	return &consulRegistry{
+		client: client,
+		config: &c,
+	}
+}
+
+func (r *consulRegistry) Register(ctx context.Context, instance *goohttp.ServiceInstance) error {
+	meta := make(map[string]string, len(instance.Metadata)+2)
+	maps.Copy(meta, instance.Metadata)
+	meta["scheme"] = instance.Scheme
+	if instance.Version != "" {
+		meta["version"] = instance.Version
+	}
+
+	check := &api.AgentServiceCheck{
+		Interval:                       r.config.CheckInterval.String(),
+		Timeout:                        r.config.CheckTimeout.String(),
+		DeregisterCriticalServiceAfter: r.config.DeregisterAfter.String(),
+	}
+	if instance.HealthURL != "" {
+		check.HTTP = instance.HealthURL
+		check.TLSSkipVerify = r.config.TLSSkipVerify
+	} else {
+		check.TCP = net.JoinHostPort(instance.Address, strconv.Itoa(instance.Port))
+	}
+
+	return r.client.RegisterService(&gooconsul.ServiceRegistration{
+		ID:      instance.Id,
+		Name:    instance.Name,
+		Tags:    instance.Tags,
+		Address: instance.Address,
+		Port:    instance.Port,
+		Meta:    meta,
+		Check:   check,
+	})
+}
+
+func (r *consulRegistry) Deregister(ctx context.Context, instance *goohttp.ServiceInstance) error {
+	return r.client.DeregisterService(instance.Id)
+}
+
+// Watch 定期检查实例是否仍在 agent 中，agent 不可用时继续等待
+func (r *consulRegistry) Watch(ctx context.Context, instance *goohttp.ServiceInstance) error {
+	ticker := time.NewTicker(r.config.WatchInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			services, err := r.client.Services(nil)
+			if err != nil {
+				continue
+			}
+			if _, ok := services[instance.Id]; !ok {
+				return nil
+			}
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+}
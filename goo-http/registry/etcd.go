@@ -0,0 +1,141 @@
+package registry
+
+import (
+	"context"
+	"encoding/json"
+	"path"
+	"sync"
+
+	clientv3 "go.etcd.io/etcd/client/v3"
+	gooetcd "v2.googo.io/goo-etcd"
+	goohttp "v2.googo.io/goo-http"
+)
+
+var (
+	DefaultEtcdConfig = &EtcdConfig{
+		Prefix: "/services",
+		TTL:    10,
+	}
+)
+
+type EtcdConfig struct {
+	Prefix string // 键前缀（默认 /services），实例保存在 {Prefix}/{服务名称}/{实例 ID}
+	TTL    int64  // 租约 TTL（秒，默认 10），进程退出未注销时实例在 TTL 后过期
+}
+
+type etcdRegistry struct {
+	client *gooetcd.Client
+	config *EtcdConfig
+	mu     sync.Mutex
+	leases map[string]*etcdLease
+}
+
+type etcdLease struct {
+	id        clientv3.LeaseID
+	keepAlive <-chan *clientv3.LeaseKeepAliveResponse
+	cancel    context.CancelFunc
+}
+
+// Etcd etcd 注册中心
+// 实例信息（JSON）写入带租约的键并自动续约，租约丢失（如 etcd 长时间不可用）后自动重新注册
+func Etcd(client *gooetcd.Client, config *EtcdConfig) goohttp.Registry {
+	if config == nil {
+		config = DefaultEtcdConfig
+	}
+
+	c := *config
+	if c.Prefix == "" {
+		c.Prefix = DefaultEtcdConfig.Prefix
+	}
+	if c.TTL <= 0 {
+		c.TTL = DefaultEtcdConfig.TTL
+	}
+
+	return &etcdRegistry{
+		client: client,
+		config: &c,
+		leases: make(map[string]*etcdLease),
+	}
+}
+
+func (r *etcdRegistry) key(instance *goohttp.ServiceInstance) string {
+	return path.Join(r.config.Prefix, instance.Name, instance.Id)
+}
+
+func (r *etcdRegistry) Register(ctx context.Context, instance *goohttp.ServiceInstance) error {
+	value, err := json.Marshal(instance)
+	if err != nil {
+		return err
+	}
+
+	grant, err := r.client.Grant(ctx, r.config.TTL)
+	if err != nil {
+		return err
+	}
+	if _, err = r.client.Put(ctx, r.key(instance), string(value), clientv3.WithLease(grant.ID)); err != nil {
+		r.client.Revoke(context.WithoutCancel(ctx), grant.ID)
+		return err
+	}
+
+	// 续约不随注册请求的 ctx 取消，注销时停止
+	keepCtx, cancel := context.WithCancel(context.WithoutCancel(ctx))
+	keepAlive, err := r.client.KeepAlive(keepCtx, grant.ID)
+	if err != nil {
+		cancel()
+		r.client.Revoke(context.WithoutCancel(ctx), grant.ID)
+		return err
+	}
+
+	r.mu.Lock()
+	if old, ok := r.leases[instance.Id]; ok {
+		old.cancel()
+	}
+	r.leases[instance.Id] = &etcdLease{
+		id:        grant.ID,
+		keepAlive: keepAlive,
+		cancel:    cancel,
+	}
+	r.mu.Unlock()
+
+	return nil
+}
+
+func (r *etcdRegistry) Deregister(ctx context.Context, instance *goohttp.ServiceInstance) error {
+	r.mu.Lock()
+	lease, ok := r.leases[instance.Id]
+	delete(r.leases, instance.Id)
+	r.mu.Unlock()
+
+	if ok {
+		lease.cancel()
+		// 撤销租约同时删除键
+		if _, err := r.client.Revoke(ctx, lease.id); err == nil {
+			return nil
+		}
+	}
+
+	_, err := r.client.Delete(ctx, r.key(instance))
+	return err
+}
+
+// Watch 等待续约结束，续约通道关闭表示租约已丢失
+func (r *etcdRegistry) Watch(ctx context.Context, instance *goohttp.ServiceInstance) error {
+	r.mu.Lock()
+	lease, ok := r.leases[instance.Id]
+	r.mu.Unlock()
+
+	if !ok {
+		return nil
+	}
+
+	for {
+		select {
+		case _, ok := <-lease.keepAlive:
+			if !ok {
+				return nil
+			}
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+}
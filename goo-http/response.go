@@ -3,6 +3,7 @@ package goohttp
 import (
 	"bytes"
 	"encoding/json"
+	"net/http"
 	"sync"
 
 	"github.com/gin-gonic/gin"
@@ -74,18 +75,34 @@ type ResponseHook func(ctx *Context, resp *Response)
 // 响应钩子写入器
 type hookResponseWriter struct {
 	gin.ResponseWriter
-	buffer *bytes.Buffer
-	mu     sync.Mutex
+	buffer    *bytes.Buffer
+	mu        sync.Mutex
+	streaming bool // 流式输出不再缓冲，也不调用钩子
 }
 
 func (w *hookResponseWriter) Write(data []byte) (int, error) {
 	w.mu.Lock()
 	defer w.mu.Unlock()
 
-	w.buffer.Write(data)
+	if !w.streaming {
+		w.buffer.Write(data)
+	}
 	return w.ResponseWriter.Write(data)
 }
 
+func (w *hookResponseWriter) WriteString(s string) (int, error) {
+	return w.Write([]byte(s))
+}
+
+func (w *hookResponseWriter) Flush() {
+	w.mu.Lock()
+	w.streaming = true
+	w.buffer.Reset()
+	w.mu.Unlock()
+
+	w.ResponseWriter.Flush()
+}
+
 func (w *hookResponseWriter) WriteHeader(statusCode int) {
 	w.ResponseWriter.WriteHeader(statusCode)
 }
@@ -103,6 +120,11 @@ func ResponseHookMiddleware(hooks []ResponseHook) gin.HandlerFunc {
 
 		c.Next()
 
+		// 304 没有响应体，流式输出没有完整的响应
+		if writer.Status() == http.StatusNotModified || writer.streaming || isStreaming(c) {
+			return
+		}
+
 		var rsp *Response
 		if err := json.Unmarshal(writer.buffer.Bytes(), &rsp); err != nil {
 			rsp = Error(ctx, 5001, err.Error())
@@ -0,0 +1,203 @@
+package goohttp
+
+import (
+	"path"
+	"reflect"
+	"slices"
+	"strings"
+)
+
+// Route 已注册的路由，可链式设置接口文档信息
+type Route struct {
+	method       string
+	path         string
+	summary      string
+	description  string
+	tags         []string
+	operationId  string
+	deprecated   bool
+	hidden       bool
+	requestType  reflect.Type
+	responseType reflect.Type
+	errors       []*BizError
+	security     []string
+}
+
+func (s *Server) addRoute(method string, basePath string, relativePath string, tags []string) *Route {
+	route := &Route{
+		method: method,
+		path:   joinRoutePath(basePath, relativePath),
+		tags:   append([]string(nil), tags...),
+	}
+
+	s.routesMu.Lock()
+	s.routes = append(s.routes, route)
+	s.routesMu.Unlock()
+
+	return route
+}
+
+// Routes 获取已注册的路由
+func (s *Server) Routes() []*Route {
+	s.routesMu.RLock()
+	defer s.routesMu.RUnlock()
+	return append([]*Route(nil), s.routes...)
+}
+
+// Route 按请求方法和路径查找匹配的路由，优先级与 gin 一致：逐段依次优先静态路径、:name 参数、*name 通配
+// 如同时注册 /users/me 和 /users/:id 时，/users/me 匹配前者
+func (s *Server) Route(method string, path string) (*Route, bool) {
+	segments := splitRoutePath(path)
+
+	var (
+		matched *Route
+		best    []int
+	)
+	for _, route := range s.Routes() {
+		if route.method != method {
+			continue
+		}
+		rank, ok := matchRoutePath(splitRoutePath(route.path), segments)
+		if ok && (matched == nil || slices.Compare(rank, best) < 0) {
+			matched, best = route, rank
+		}
+	}
+
+	return matched, matched != nil
+}
+
+func splitRoutePath(path string) []string {
+	return strings.Split(strings.Trim(path, "/"), "/")
+}
+
+// matchRoutePath 按 gin 路由规则匹配：:name 匹配一段，*name 匹配剩余部分
+// 返回每段的匹配类型（0 静态、1 参数、2 通配），用于比较优先级
+func matchRoutePath(pattern []string, segments []string) ([]int, bool) {
+	rank := make([]int, 0, len(pattern))
+	for i, p := range pattern {
+		if strings.HasPrefix(p, "*") {
+			return append(rank, 2), true
+		}
+		if i >= len(segments) {
+			return nil, false
+		}
+		if strings.HasPrefix(p, ":") {
+			if segments[i] == "" {
+				return nil, false
+			}
+			rank = append(rank, 1)
+			continue
+		}
+		if p != segments[i] {
+			return nil, false
+		}
+		rank = append(rank, 0)
+	}
+	return rank, len(pattern) == len(segments)
+}
+
+func (r *Route) Method() string {
+	return r.method
+}
+
+func (r *Route) Path() string {
+	return r.path
+}
+
+// Summary 接口摘要
+func (r *Route) Summary(summary string) *Route {
+	r.summary = summary
+	return r
+}
+
+// Description 接口说明
+func (r *Route) Description(description string) *Route {
+	r.description = description
+	return r
+}
+
+// Tags 接口标签（覆盖分组标签）
+func (r *Route) Tags(tags ...string) *Route {
+	r.tags = tags
+	return r
+}
+
+// OperationId 接口唯一标识，默认根据方法和路径生成
+func (r *Route) OperationId(operationId string) *Route {
+	r.operationId = operationId
+	return r
+}
+
+// Deprecated 标记接口已废弃
+func (r *Route) Deprecated() *Route {
+	r.deprecated = true
+	return r
+}
+
+// Hidden 不在接口文档中展示
+func (r *Route) Hidden() *Route {
+	r.hidden = true
+	return r
+}
+
+// Request 请求类型，按 uri/form/header/json 标签生成参数和请求体
+func (r *Route) Request(v any) *Route {
+	r.requestType = reflect.TypeOf(v)
+	return r
+}
+
+// Response 响应 data 的类型
+func (r *Route) Response(v any) *Route {
+	r.responseType = reflect.TypeOf(v)
+	return r
+}
+
+// Errors 接口可能返回的业务错误
+func (r *Route) Errors(errs ...*BizError) *Route {
+	r.errors = append(r.errors, errs...)
+	return r
+}
+
+// Security 接口使用的安全方案（对应 OpenAPIConfig.SecuritySchemes 的名称），不传参数表示不需要认证
+func (r *Route) Security(names ...string) *Route {
+	r.security = append([]string{}, names...)
+	return r
+}
+
+func joinRoutePath(basePath string, relativePath string) string {
+	if relativePath == "" {
+		return basePath
+	}
+
+	joined := path.Join(basePath, relativePath)
+	if strings.HasSuffix(relativePath, "/") && !strings.HasSuffix(joined, "/") {
+		joined += "/"
+	}
+
+	return joined
+}
+
+// lookupRoute 按路由查找配置，依次匹配 "METHOD 路由模板"、"路由模板" 和以 * 结尾的路径前缀（如 "/admin/*"）
+func lookupRoute[T any](routes map[string]T, ctx *Context) (T, bool) {
+	var zero T
+	if len(routes) == 0 {
+		return zero, false
+	}
+
+	route := ctx.FullPath()
+	if v, ok := routes[ctx.Request.Method+" "+route]; ok {
+		return v, true
+	}
+	if v, ok := routes[route]; ok {
+		return v, true
+	}
+
+	path := ctx.Request.URL.Path
+	for pattern, v := range routes {
+		if prefix, ok := strings.CutSuffix(pattern, "*"); ok && strings.HasPrefix(path, prefix) {
+			return v, true
+		}
+	}
+
+	return zero, false
+}
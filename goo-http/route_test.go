@@ -0,0 +1,50 @@
+package goohttp
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+)
+
+// 路由查找的优先级应与 gin 一致：静态路径优先于参数，参数优先于通配，与注册顺序无关
+func TestServerRoute(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	server := New(WithEnableLog(false))
+	noop := func(*Context) {}
+	server.Get("/users/:id", noop)
+	server.Get("/users/me", noop)
+	server.Get("/users/:id/orders", noop)
+	server.Get("/files/*path", noop)
+	server.Get("/static/readme", noop)
+	server.Get("/:name/readme", noop)
+	server.Post("/users/:id", noop)
+
+	tests := []struct {
+		method string
+		path   string
+		want   string
+	}{
+		{http.MethodGet, "/users/me", "/users/me"},
+		{http.MethodGet, "/users/42", "/users/:id"},
+		{http.MethodGet, "/users/me/orders", "/users/:id/orders"},
+		{http.MethodGet, "/static/readme", "/static/readme"},
+		{http.MethodGet, "/docs/readme", "/:name/readme"},
+		{http.MethodGet, "/files/a/b.txt", "/files/*path"},
+		{http.MethodPost, "/users/me", "/users/:id"},
+		{http.MethodGet, "/orders", ""},
+		{http.MethodPut, "/users/42", ""},
+	}
+
+	for _, tt := range tests {
+		route, ok := server.Route(tt.method, tt.path)
+		got := ""
+		if ok {
+			got = route.Path()
+		}
+		if got != tt.want {
+			t.Errorf("Route(%s %s) = %q, want %q", tt.method, tt.path, got, tt.want)
+		}
+	}
+}
@@ -2,10 +2,13 @@ package goohttp
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"net"
 	"net/http"
 	"os"
 	"os/signal"
+	"sync"
 	"syscall"
 	"time"
 
@@ -13,24 +16,58 @@ import (
 )
 
 type Server struct {
-	config     *Config
-	engine     *gin.Engine
-	server     *http.Server
-	rateLimits []*RateLimiter // 保存限流器引用，用于优雅关闭
+	config      *Config
+	engine      *gin.Engine
+	listeners   []*serverListener // 已打开的监听，用于优雅关闭
+	registrar   *registrar        // 注册中心自动注册
+	listenersMu sync.Mutex
+	rateLimits  []*RateLimiter // 保存限流器引用，用于优雅关闭
+	routes      []*Route       // 已注册的路由，用于生成接口文档
+	routesMu    sync.RWMutex
+	metrics     *Metrics      // 请求指标
+	admin       *Admin        // 管理接口
+	done        chan struct{} // Shutdown 完成后关闭，Run 等待优雅关闭结束后再返回
+	doneOnce    sync.Once
 }
 
 func New(opts ...ConfigOption) *Server {
-	config := DefaultConfig
+	// 深拷贝默认配置，多个 Server（如测试）之间以及与 DefaultConfig 之间互不影响
+	config := DefaultConfig.Clone()
 	for _, opt := range opts {
 		opt.Apply(config)
 	}
 
 	engine := gin.New()
-	engine.Use(gin.Recovery())
+
+	// 只信任配置的代理，避免客户端伪造 X-Forwarded-For / X-Real-Ip 绕过限流
+	if len(config.RemoteIPHeaders) > 0 {
+		engine.RemoteIPHeaders = config.RemoteIPHeaders
+	}
+	if err := engine.SetTrustedProxies(config.TrustedProxies); err != nil {
+		panic(fmt.Errorf("%w: %v", ErrInvalidCIDR, err))
+	}
+	engine.Use(RecoveryMiddleware(config.RecoveryConfig))
 
 	server := &Server{
 		config: config,
 		engine: engine,
+		done:   make(chan struct{}),
+	}
+
+	if config.EnableHealth {
+		server.setupHealth()
+	}
+
+	if config.EnableMetrics {
+		server.setupMetrics()
+	}
+
+	if config.EnableAdmin {
+		server.setupAdmin()
+	}
+
+	if config.EnableOpenAPI {
+		server.setupOpenAPI()
 	}
 
 	server.setupMiddlewares()
@@ -39,62 +76,246 @@ func New(opts ...ConfigOption) *Server {
 }
 
 func (s *Server) setupMiddlewares() {
+	// 请求指标（最外层，限流、解密失败等被拦截的请求也会记录）
+	if s.metrics != nil {
+		s.engine.Use(s.metrics.Middleware())
+	}
+
 	// TraceId
-	s.engine.Use(TraceMiddleware(s.config.TraceIdHeader))
+	s.engine.Use(traceMiddleware(s.config.TraceIdHeader, s.config.TraceIdGenerator))
 
-	// 日志
-	if s.config.EnableLog {
+	// 日志（访问日志优先）
+	if s.config.EnableAccessLog {
+		s.engine.Use(AccessLogMiddleware(s.config.AccessLogConfig))
+	} else if s.config.EnableLog {
 		s.engine.Use(LogMiddleware(s.config.Logger))
 	}
 
+	// IP黑白名单
+	if s.config.EnableIPFilter && s.config.IPFilter != nil {
+		s.engine.Use(IPFilterMiddleware(s.config.IPFilter))
+	}
+
 	// CORS
 	if s.config.EnableCORS {
 		s.engine.Use(CORSMiddleware(s.config.CORSConfig))
 	}
 
+	// 自适应过载保护（在限流之前，过载时尽早拒绝）
+	if s.config.EnableLoadShed && s.config.LoadShedder != nil {
+		s.engine.Use(LoadShedMiddleware(s.config.LoadShedder))
+	}
+
 	// 限流
 	if s.config.EnableRateLimit && len(s.config.RateLimiters) > 0 {
 		s.rateLimits = s.config.RateLimiters
 		s.engine.Use(RateLimitMiddleware(s.config.RateLimiters))
 	}
 
+	// 请求超时（覆盖签名校验、认证和处理函数）
+	if s.config.EnableTimeout {
+		s.engine.Use(TimeoutMiddleware(s.config.TimeoutConfig))
+	}
+
+	// 会话和 CSRF 校验
+	if s.config.EnableSession && s.config.SessionManager != nil {
+		s.engine.Use(SessionMiddleware(s.config.SessionManager))
+	}
+
+	// 签名校验
+	if s.config.EnableSign && s.config.SignVerifier != nil {
+		s.engine.Use(SignMiddleware(s.config.SignVerifier))
+	}
+
+	// JWT认证
+	if s.config.EnableJWT && s.config.JWTAuth != nil {
+		s.engine.Use(JWTMiddleware(s.config.JWTAuth))
+	}
+
 	// 响应钩子
 	if s.config.ResponseHooks != nil && len(s.config.ResponseHooks) > 0 {
 		s.engine.Use(ResponseHookMiddleware(s.config.ResponseHooks))
 	}
 
 	// 加解密
-	if s.config.EnableEncrypt && s.config.Encryptor != nil {
-		s.engine.Use(EncryptMiddleware(s.config.Encryptor))
+	if s.config.EnableEncrypt {
+		if s.config.Encryption != nil {
+			s.engine.Use(EncryptionMiddleware(s.config.Encryption))
+		} else if s.config.Encryptor != nil {
+			s.engine.Use(EncryptMiddleware(s.config.Encryptor))
+		}
 	}
+
+	// 幂等处理（在加解密之后，保存和重放的都是明文响应）
+	if s.config.EnableIdempotency && s.config.Idempotency != nil {
+		s.engine.Use(IdempotencyMiddleware(s.config.Idempotency))
+	}
+
+	// 响应缓存（在加解密和响应钩子之后，缓存明文响应，命中时同样经过加密和钩子）
+	if s.config.EnableCache && s.config.ResponseCache != nil {
+		s.engine.Use(ResponseCacheMiddleware(s.config.ResponseCache))
+	}
+}
+
+// Config 获取生效的配置（副本），修改副本不影响服务器
+func (s *Server) Config() *Config {
+	return s.config.Clone()
+}
+
+// ServeHTTP 实现 http.Handler，可以挂载到其他 http.Server 或在测试中直接处理请求
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.engine.ServeHTTP(w, r)
 }
 
+// Run 启动服务器（阻塞），同时监听 Addr 和 Listeners 中的所有地址，收到 SIGINT / SIGTERM 后优雅关闭，
+// 调用 Shutdown 关闭时等待 Shutdown 完成后才返回
+// 任一监听失败时关闭其他监听并返回错误
 func (s *Server) Run() error {
-	s.server = &http.Server{
-		Addr:    s.config.Addr,
-		Handler: s.engine,
+	configs := s.listenerConfigs()
+	if len(configs) == 0 {
+		return ErrNoListener
 	}
 
+	listeners := make([]*serverListener, 0, len(configs))
+	for _, config := range configs {
+		l, err := s.listen(config)
+		if err != nil {
+			for _, opened := range listeners {
+				opened.listener.Close()
+				opened.stop()
+			}
+			return fmt.Errorf("listen %s: %w", config.Addr, err)
+		}
+		listeners = append(listeners, l)
+	}
+
+	var reg *registrar
+	if s.config.Registry != nil && s.config.Registry.Registry != nil {
+		var err error
+		if reg, err = s.setupRegistrar(listeners); err != nil {
+			for _, l := range listeners {
+				l.listener.Close()
+				l.stop()
+			}
+			return err
+		}
+	}
+
+	s.listenersMu.Lock()
+	s.listeners = listeners
+	s.registrar = reg
+	s.listenersMu.Unlock()
+
 	go func() {
 		sigint := make(chan os.Signal, 1)
 		signal.Notify(sigint, os.Interrupt, syscall.SIGTERM)
 		<-sigint
 
-		ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+		ctx, cancel := context.WithTimeout(context.Background(), s.shutdownTimeout())
 		defer cancel()
 
 		s.Shutdown(ctx)
 	}()
 
-	fmt.Printf("Listening on %s\n", s.config.Addr)
+	errCh := make(chan error, len(listeners))
+	for _, l := range listeners {
+		fmt.Printf("Listening on %s\n", l)
+		go func() {
+			errCh <- l.serve()
+		}()
+	}
+
+	// 开始处理请求后再注册，避免网关转发到未就绪的实例
+	if reg != nil {
+		reg.start()
+	}
+
+	var err error
+	for range listeners {
+		serveErr := <-errCh
+		if err != nil {
+			continue
+		}
+		err = serveErr
+
+		// 某个监听异常退出时注销实例并关闭其他监听
+		if !errors.Is(serveErr, http.ErrServerClosed) {
+			ctx, cancel := context.WithTimeout(context.Background(), s.shutdownTimeout())
+			s.deregister(ctx)
+			cancel()
+
+			for _, l := range listeners {
+				l.server.Close()
+			}
+		}
+	}
+
+	// 由 Shutdown 关闭时，等待处理中的请求、限流器等清理完成后再返回，避免进程提前退出
+	if errors.Is(err, http.ErrServerClosed) {
+		<-s.done
+	}
+
+	return err
+}
+
+// setupRegistrar 使用第一个 TCP 监听地址注册实例
+func (s *Server) setupRegistrar(listeners []*serverListener) (*registrar, error) {
+	for _, l := range listeners {
+		if _, ok := l.listener.Addr().(*net.TCPAddr); ok {
+			return s.newRegistrar(l)
+		}
+	}
+	return nil, fmt.Errorf("%w: registry requires a tcp listener", ErrNoListener)
+}
+
+// deregister 从注册中心注销实例，只执行一次
+func (s *Server) deregister(ctx context.Context) error {
+	s.listenersMu.Lock()
+	reg := s.registrar
+	s.registrar = nil
+	s.listenersMu.Unlock()
 
-	return s.server.ListenAndServe()
+	if reg == nil {
+		return nil
+	}
+	return reg.stop(ctx)
+}
+
+func (s *Server) shutdownTimeout() time.Duration {
+	if s.config.ShutdownTimeout > 0 {
+		return s.config.ShutdownTimeout
+	}
+	return 30 * time.Second
 }
 
 func (s *Server) Shutdown(ctx context.Context) error {
+	defer s.doneOnce.Do(func() { close(s.done) })
+
+	// 先从注册中心注销，网关不再发现该实例
+	s.deregister(ctx)
+
+	// 就绪检查返回未就绪，等待负载均衡摘除流量后再关闭
+	s.drain(ctx)
+
 	// 停止所有限流器的清理goroutine
 	for _, limiter := range s.rateLimits {
 		limiter.Stop()
 	}
-	return s.server.Shutdown(ctx)
+
+	if s.config.Idempotency != nil {
+		s.config.Idempotency.Stop()
+	}
+
+	s.listenersMu.Lock()
+	listeners := s.listeners
+	s.listenersMu.Unlock()
+
+	var errs []error
+	for _, l := range listeners {
+		if err := l.server.Shutdown(ctx); err != nil {
+			errs = append(errs, err)
+		}
+		l.stop()
+	}
+	return errors.Join(errs...)
 }
@@ -1,6 +1,8 @@
 package goohttp
 
 import (
+	"net/http"
+
 	"github.com/gin-gonic/gin"
 )
 
@@ -9,6 +11,7 @@ type HandlerFunc func(*Context)
 func wrapHandlers(handlers ...HandlerFunc) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		ctx := &Context{Context: c}
+		defer closeSSE(c)
 
 		for _, handler := range handlers {
 			handler(ctx)
@@ -20,28 +23,39 @@ func wrapHandlers(handlers ...HandlerFunc) gin.HandlerFunc {
 	}
 }
 
-func (s *Server) Get(path string, handlers ...HandlerFunc) {
+func (s *Server) Get(path string, handlers ...HandlerFunc) *Route {
 	s.engine.GET(path, wrapHandlers(handlers...))
+	return s.addRoute(http.MethodGet, s.engine.BasePath(), path, nil)
 }
 
-func (s *Server) Post(path string, handlers ...HandlerFunc) {
+func (s *Server) Post(path string, handlers ...HandlerFunc) *Route {
 	s.engine.POST(path, wrapHandlers(handlers...))
+	return s.addRoute(http.MethodPost, s.engine.BasePath(), path, nil)
 }
 
-func (s *Server) Put(path string, handlers ...HandlerFunc) {
+func (s *Server) Put(path string, handlers ...HandlerFunc) *Route {
 	s.engine.PUT(path, wrapHandlers(handlers...))
+	return s.addRoute(http.MethodPut, s.engine.BasePath(), path, nil)
 }
 
-func (s *Server) Delete(path string, handlers ...HandlerFunc) {
+func (s *Server) Delete(path string, handlers ...HandlerFunc) *Route {
 	s.engine.DELETE(path, wrapHandlers(handlers...))
+	return s.addRoute(http.MethodDelete, s.engine.BasePath(), path, nil)
 }
 
-func (s *Server) Patch(path string, handlers ...HandlerFunc) {
+func (s *Server) Patch(path string, handlers ...HandlerFunc) *Route {
 	s.engine.PATCH(path, wrapHandlers(handlers...))
+	return s.addRoute(http.MethodPatch, s.engine.BasePath(), path, nil)
 }
 
-func (s *Server) Options(path string, handlers ...HandlerFunc) {
+func (s *Server) Options(path string, handlers ...HandlerFunc) *Route {
 	s.engine.OPTIONS(path, wrapHandlers(handlers...))
+	return s.addRoute(http.MethodOptions, s.engine.BasePath(), path, nil)
+}
+
+func (s *Server) Head(path string, handlers ...HandlerFunc) *Route {
+	s.engine.HEAD(path, wrapHandlers(handlers...))
+	return s.addRoute(http.MethodHead, s.engine.BasePath(), path, nil)
 }
 
 func (s *Server) Static(path, root string) {
@@ -53,37 +67,57 @@ func (s *Server) StaticFile(path, filepath string) {
 }
 
 type RouterGroup struct {
-	group *gin.RouterGroup
+	server *Server
+	group  *gin.RouterGroup
+	tags   []string
 }
 
 func (s *Server) Group(path string, handlers ...HandlerFunc) *RouterGroup {
 	return &RouterGroup{
-		group: s.engine.Group(path, wrapHandlers(handlers...)),
+		server: s,
+		group:  s.engine.Group(path, wrapHandlers(handlers...)),
 	}
 }
 
-func (rg *RouterGroup) Get(path string, handlers ...HandlerFunc) {
+// Tags 设置分组内路由的默认文档标签
+func (rg *RouterGroup) Tags(tags ...string) *RouterGroup {
+	rg.tags = tags
+	return rg
+}
+
+func (rg *RouterGroup) Get(path string, handlers ...HandlerFunc) *Route {
 	rg.group.GET(path, wrapHandlers(handlers...))
+	return rg.server.addRoute(http.MethodGet, rg.group.BasePath(), path, rg.tags)
 }
 
-func (rg *RouterGroup) Post(path string, handlers ...HandlerFunc) {
+func (rg *RouterGroup) Post(path string, handlers ...HandlerFunc) *Route {
 	rg.group.POST(path, wrapHandlers(handlers...))
+	return rg.server.addRoute(http.MethodPost, rg.group.BasePath(), path, rg.tags)
 }
 
-func (rg *RouterGroup) Put(path string, handlers ...HandlerFunc) {
+func (rg *RouterGroup) Put(path string, handlers ...HandlerFunc) *Route {
 	rg.group.PUT(path, wrapHandlers(handlers...))
+	return rg.server.addRoute(http.MethodPut, rg.group.BasePath(), path, rg.tags)
 }
 
-func (rg *RouterGroup) Delete(path string, handlers ...HandlerFunc) {
+func (rg *RouterGroup) Delete(path string, handlers ...HandlerFunc) *Route {
 	rg.group.DELETE(path, wrapHandlers(handlers...))
+	return rg.server.addRoute(http.MethodDelete, rg.group.BasePath(), path, rg.tags)
 }
 
-func (rg *RouterGroup) Patch(path string, handlers ...HandlerFunc) {
+func (rg *RouterGroup) Patch(path string, handlers ...HandlerFunc) *Route {
 	rg.group.PATCH(path, wrapHandlers(handlers...))
+	return rg.server.addRoute(http.MethodPatch, rg.group.BasePath(), path, rg.tags)
 }
 
-func (rg *RouterGroup) Options(path string, handlers ...HandlerFunc) {
+func (rg *RouterGroup) Options(path string, handlers ...HandlerFunc) *Route {
 	rg.group.OPTIONS(path, wrapHandlers(handlers...))
+	return rg.server.addRoute(http.MethodOptions, rg.group.BasePath(), path, rg.tags)
+}
+
+func (rg *RouterGroup) Head(path string, handlers ...HandlerFunc) *Route {
+	rg.group.HEAD(path, wrapHandlers(handlers...))
+	return rg.server.addRoute(http.MethodHead, rg.group.BasePath(), path, rg.tags)
 }
 
 func (rg *RouterGroup) Static(path, root string) {
@@ -0,0 +1,487 @@
+package goohttp
+
+import (
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/base64"
+	"errors"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// 会话错误码
+const (
+	SessionCodeCSRFFailed   = 4032 // CSRF 令牌缺失或错误
+	SessionCodeStoreFailure = 5005 // 会话存储不可用
+)
+
+// SameSite 取值
+const (
+	SameSiteLax    = "lax"
+	SameSiteStrict = "strict"
+	SameSiteNone   = "none" // 跨站请求也携带 cookie，强制 Secure
+)
+
+var (
+	ErrSessionStoreRequired = errors.New("没有配置会话存储")
+)
+
+var (
+	DefaultSessionConfig = &SessionConfig{
+		CookieName: "goo_session",
+		Path:       "/",
+		MaxAge:     24 * time.Hour,
+		SameSite:   SameSiteLax,
+		CSRFHeader: "X-CSRF-Token",
+		CSRFField:  "_csrf",
+	}
+)
+
+// 保存 Session 的 gin.Context key
+const sessionContextKey = "goohttp.session"
+
+type SessionConfig struct {
+	Store           SessionStore                  // 会话存储（CookieSessionStore 或 RedisSessionStore）
+	CookieName      string                        // cookie 名称（默认 goo_session）
+	Domain          string                        // cookie 域名
+	Path            string                        // cookie 路径（默认 /）
+	MaxAge          time.Duration                 // 会话空闲过期时间（默认 24 小时），每次访问续期
+	Secure          bool                          // 只通过 HTTPS 发送 cookie，SameSite 为 none 时强制开启
+	DisableHttpOnly bool                          // 允许 JavaScript 读取 cookie（默认 HttpOnly）
+	SameSite        string                        // lax（默认）、strict、none
+	EnableCSRF      bool                          // 是否校验 CSRF 令牌（GET、HEAD、OPTIONS、TRACE 请求不校验）
+	CSRFHeader      string                        // CSRF 令牌请求头（默认 X-CSRF-Token）
+	CSRFField       string                        // CSRF 令牌表单字段（默认 _csrf），请求头没有令牌时读取
+	CSRFSkip        func(ctx *Context) bool       // 跳过 CSRF 校验的请求（如使用签名或 JWT 认证的开放接口）
+	OnStoreError    func(ctx *Context, err error) // 会话存储出错时的回调（如记录日志、告警）
+}
+
+// SessionManager 会话管理：加载、保存会话，写入 cookie，校验 CSRF 令牌
+type SessionManager struct {
+	config   *SessionConfig
+	sameSite http.SameSite
+}
+
+func NewSessionManager(config *SessionConfig) (*SessionManager, error) {
+	if config == nil {
+		config = DefaultSessionConfig
+	}
+	if config.Store == nil {
+		return nil, ErrSessionStoreRequired
+	}
+
+	c := *config
+	if c.CookieName == "" {
+		c.CookieName = DefaultSessionConfig.CookieName
+	}
+	if c.Path == "" {
+		c.Path = DefaultSessionConfig.Path
+	}
+	if c.MaxAge <= 0 {
+		c.MaxAge = DefaultSessionConfig.MaxAge
+	}
+	if c.SameSite == "" {
+		c.SameSite = DefaultSessionConfig.SameSite
+	}
+	if c.CSRFHeader == "" {
+		c.CSRFHeader = DefaultSessionConfig.CSRFHeader
+	}
+	if c.CSRFField == "" {
+		c.CSRFField = DefaultSessionConfig.CSRFField
+	}
+
+	m := &SessionManager{config: &c}
+	switch strings.ToLower(c.SameSite) {
+	case SameSiteStrict:
+		m.sameSite = http.SameSiteStrictMode
+	case SameSiteNone:
+		m.sameSite = http.SameSiteNoneMode
+		m.config.Secure = true
+	default:
+		m.sameSite = http.SameSiteLaxMode
+	}
+
+	return m, nil
+}
+
+// Session 当前请求的会话，修改后在响应写入前保存
+type Session struct {
+	data        *SessionData
+	value       string // 请求携带的 cookie 值
+	isNew       bool
+	modified    bool
+	regenerated bool
+	destroyed   bool
+	mu          sync.Mutex
+}
+
+// Session 获取当前请求的会话，未启用会话时返回 nil
+func (c *Context) Session() *Session {
+	if v, ok := c.Get(sessionContextKey); ok {
+		return v.(*Session)
+	}
+	return nil
+}
+
+// Id 会话 ID
+func (s *Session) Id() string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.data.Id
+}
+
+// IsNew 是否为本次请求新建的会话
+func (s *Session) IsNew() bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.isNew
+}
+
+func (s *Session) Get(key string) (any, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	value, ok := s.data.Values[key]
+	return value, ok
+}
+
+// GetString 获取字符串值，不存在或类型不符时返回空字符串
+func (s *Session) GetString(key string) string {
+	value, _ := s.Get(key)
+	str, _ := value.(string)
+	return str
+}
+
+// GetInt64 获取整数值，从存储加载的数字为 float64，统一转换为 int64
+func (s *Session) GetInt64(key string) int64 {
+	value, _ := s.Get(key)
+	switch v := value.(type) {
+	case int:
+		return int64(v)
+	case int64:
+		return v
+	case float64:
+		return int64(v)
+	}
+	return 0
+}
+
+// GetBool 获取布尔值
+func (s *Session) GetBool(key string) bool {
+	value, _ := s.Get(key)
+	b, _ := value.(bool)
+	return b
+}
+
+// Set 设置值，值需要可以 JSON 序列化
+func (s *Session) Set(key string, value any) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.data.Values == nil {
+		s.data.Values = make(map[string]any)
+	}
+	s.data.Values[key] = value
+	s.modified = true
+}
+
+func (s *Session) Delete(key string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, ok := s.data.Values[key]; ok {
+		delete(s.data.Values, key)
+		s.modified = true
+	}
+}
+
+// Clear 清空所有值和闪存消息，保留会话 ID
+func (s *Session) Clear() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.data.Values = nil
+	s.data.Flashes = nil
+	s.modified = true
+}
+
+// Flash 添加闪存消息，下次读取后删除（如重定向后显示的提示）
+func (s *Session) Flash(key string, value any) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.data.Flashes == nil {
+		s.data.Flashes = make(map[string][]any)
+	}
+	s.data.Flashes[key] = append(s.data.Flashes[key], value)
+	s.modified = true
+}
+
+// Flashes 读取并删除闪存消息
+func (s *Session) Flashes(key string) []any {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	flashes, ok := s.data.Flashes[key]
+	if !ok {
+		return nil
+	}
+	delete(s.data.Flashes, key)
+	s.modified = true
+	return flashes
+}
+
+// Regenerate 轮换会话 ID 和 CSRF 密钥并保留会话值，登录、提权后调用，防止会话固定攻击
+func (s *Session) Regenerate() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.data.Id = newSessionId()
+	s.data.CSRFSecret = newCSRFSecret()
+	s.data.CreatedAt = time.Now()
+	s.regenerated = true
+	s.modified = true
+}
+
+// Destroy 删除会话并清除 cookie，退出登录时调用
+func (s *Session) Destroy() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.data.Values = nil
+	s.data.Flashes = nil
+	s.destroyed = true
+}
+
+// CSRFToken 生成 CSRF 令牌，用于表单隐藏字段或前端请求头
+// 每次生成的令牌不同（随机掩码，防止 BREACH 攻击），都可以通过校验
+func (s *Session) CSRFToken() string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.data.CSRFSecret == "" {
+		s.data.CSRFSecret = newCSRFSecret()
+		s.modified = true
+	}
+	secret, _ := base64.RawURLEncoding.DecodeString(s.data.CSRFSecret)
+
+	mask := make([]byte, len(secret))
+	if _, err := rand.Read(mask); err != nil {
+		panic(err)
+	}
+	token := make([]byte, len(secret)*2)
+	copy(token, mask)
+	for i := range secret {
+		token[len(secret)+i] = secret[i] ^ mask[i]
+	}
+	return base64.RawURLEncoding.EncodeToString(token)
+}
+
+// VerifyCSRFToken 校验 CSRF 令牌
+func (s *Session) VerifyCSRFToken(token string) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.data.CSRFSecret == "" || token == "" {
+		return false
+	}
+	secret, err := base64.RawURLEncoding.DecodeString(s.data.CSRFSecret)
+	if err != nil {
+		return false
+	}
+	raw, err := base64.RawURLEncoding.DecodeString(token)
+	if err != nil || len(raw) != len(secret)*2 {
+		return false
+	}
+
+	unmasked := make([]byte, len(secret))
+	for i := range secret {
+		unmasked[i] = raw[i] ^ raw[len(secret)+i]
+	}
+	return subtle.ConstantTimeCompare(unmasked, secret) == 1
+}
+
+func newCSRFSecret() string {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		panic(err)
+	}
+	return base64.RawURLEncoding.EncodeToString(b)
+}
+
+// load 加载请求携带的会话，不存在时创建新会话（未修改时不保存）
+func (m *SessionManager) load(ctx *Context) (*Session, error) {
+	session := &Session{}
+
+	if cookie, err := ctx.Request.Cookie(m.config.CookieName); err == nil && cookie.Value != "" {
+		session.value = cookie.Value
+
+		data, err := m.config.Store.Load(ctx.Request.Context(), cookie.Value)
+		if err != nil {
+			return nil, err
+		}
+		session.data = data
+	}
+
+	if session.data == nil {
+		now := time.Now()
+		session.data = &SessionData{
+			Id:        newSessionId(),
+			CreatedAt: now,
+			ExpiresAt: now.Add(m.config.MaxAge),
+		}
+		session.isNew = true
+	}
+
+	return session, nil
+}
+
+// save 保存会话并写入 cookie，在响应头写入前调用
+func (m *SessionManager) save(ctx *Context, session *Session) error {
+	session.mu.Lock()
+	defer session.mu.Unlock()
+
+	c := ctx.Request.Context()
+
+	if session.destroyed {
+		if session.value != "" {
+			m.setCookie(ctx, "", -1)
+			return m.config.Store.Delete(c, session.value)
+		}
+		return nil
+	}
+
+	// 会话 ID 轮换后删除旧会话
+	if session.regenerated && session.value != "" {
+		if err := m.config.Store.Delete(c, session.value); err != nil {
+			return err
+		}
+	}
+
+	now := time.Now()
+	switch {
+	case session.modified:
+		session.data.ExpiresAt = now.Add(m.config.MaxAge)
+		value, err := m.config.Store.Save(c, session.data, m.config.MaxAge)
+		if err != nil {
+			return err
+		}
+		m.setCookie(ctx, value, int(m.config.MaxAge.Seconds()))
+	case !session.isNew && session.data.ExpiresAt.Sub(now) < m.config.MaxAge*9/10:
+		// 滑动过期：剩余时间不足 MaxAge 的 90% 时续期，避免每个请求都重写 cookie
+		session.data.ExpiresAt = now.Add(m.config.MaxAge)
+		value, err := m.config.Store.Touch(c, session.value, session.data, m.config.MaxAge)
+		if err != nil {
+			return err
+		}
+		if value == "" {
+			m.setCookie(ctx, "", -1)
+			return nil
+		}
+		m.setCookie(ctx, value, int(m.config.MaxAge.Seconds()))
+	case session.isNew && session.value != "":
+		// 请求携带的会话已失效，清除 cookie
+		m.setCookie(ctx, "", -1)
+	}
+
+	return nil
+}
+
+func (m *SessionManager) setCookie(ctx *Context, value string, maxAge int) {
+	http.SetCookie(ctx.Writer, &http.Cookie{
+		Name:     m.config.CookieName,
+		Value:    value,
+		Path:     m.config.Path,
+		Domain:   m.config.Domain,
+		MaxAge:   maxAge,
+		Secure:   m.config.Secure,
+		HttpOnly: !m.config.DisableHttpOnly,
+		SameSite: m.sameSite,
+	})
+}
+
+func (m *SessionManager) verifyCSRF(ctx *Context, session *Session) bool {
+	switch ctx.Request.Method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions, http.MethodTrace:
+		return true
+	}
+	if m.config.CSRFSkip != nil && m.config.CSRFSkip(ctx) {
+		return true
+	}
+
+	token := ctx.GetHeader(m.config.CSRFHeader)
+	if token == "" {
+		token = ctx.PostForm(m.config.CSRFField)
+	}
+	return session.VerifyCSRFToken(token)
+}
+
+func (m *SessionManager) storeError(ctx *Context, err error) {
+	if m.config.OnStoreError != nil {
+		m.config.OnStoreError(ctx, err)
+	}
+}
+
+// SessionMiddleware 加载会话、校验 CSRF 令牌，处理函数修改会话后在写入响应前保存
+func SessionMiddleware(manager *SessionManager) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		ctx := &Context{Context: c}
+
+		session, err := manager.load(ctx)
+		if err != nil {
+			manager.storeError(ctx, err)
+			ctx.Abort(http.StatusServiceUnavailable, SessionCodeStoreFailure, "会话存储不可用")
+			return
+		}
+
+		if manager.config.EnableCSRF && !manager.verifyCSRF(ctx, session) {
+			ctx.Abort(http.StatusForbidden, SessionCodeCSRFFailed, "CSRF 令牌无效")
+			return
+		}
+
+		c.Set(sessionContextKey, session)
+
+		writer := &sessionResponseWriter{
+			ResponseWriter: c.Writer,
+			commit: func() {
+				if err := manager.save(ctx, session); err != nil {
+					manager.storeError(ctx, err)
+				}
+			},
+		}
+		c.Writer = writer
+
+		c.Next()
+
+		// 处理函数没有写入响应体时，在 gin 写入响应头前保存
+		writer.commitOnce()
+		c.Writer = writer.ResponseWriter
+	}
+}
+
+// sessionResponseWriter 在写入响应头前保存会话，确保 Set-Cookie 能够写入
+type sessionResponseWriter struct {
+	gin.ResponseWriter
+	commit func()
+	once   sync.Once
+}
+
+func (w *sessionResponseWriter) commitOnce() {
+	w.once.Do(w.commit)
+}
+
+func (w *sessionResponseWriter) WriteHeaderNow() {
+	w.commitOnce()
+	w.ResponseWriter.WriteHeaderNow()
+}
+
+func (w *sessionResponseWriter) Write(data []byte) (int, error) {
+	w.commitOnce()
+	return w.ResponseWriter.Write(data)
+}
+
+func (w *sessionResponseWriter) WriteString(s string) (int, error) {
+	w.commitOnce()
+	return w.ResponseWriter.WriteString(s)
+}
+
+func (w *sessionResponseWriter) Flush() {
+	w.commitOnce()
+	w.ResponseWriter.Flush()
+}
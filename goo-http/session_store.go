@@ -0,0 +1,252 @@
+package goohttp
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/go-redis/redis/v8"
+	gooredis "v2.googo.io/goo-redis"
+)
+
+var (
+	ErrSessionTooLarge = errors.New("会话数据超出 cookie 大小限制")
+)
+
+// cookie 值的最大长度，浏览器限制单个 cookie 约 4KB
+const maxCookieValueSize = 4000
+
+// SessionData 会话数据
+type SessionData struct {
+	Id         string           `json:"id"`                // 会话 ID，登录后轮换
+	Values     map[string]any   `json:"values,omitempty"`  // 会话值，经过 JSON 序列化，数字读取时为 float64
+	Flashes    map[string][]any `json:"flashes,omitempty"` // 闪存消息，读取后删除
+	CSRFSecret string           `json:"csrf,omitempty"`    // CSRF 令牌密钥
+	CreatedAt  time.Time        `json:"created_at"`        // 创建时间
+	ExpiresAt  time.Time        `json:"expires_at"`        // 过期时间，每次续期后更新
+}
+
+// SessionStore 会话存储
+type SessionStore interface {
+	// Load 根据 cookie 值加载会话，不存在、已过期或校验失败时返回 nil
+	Load(ctx context.Context, value string) (*SessionData, error)
+	// Save 保存会话，返回写入 cookie 的值
+	Save(ctx context.Context, data *SessionData, ttl time.Duration) (string, error)
+	// Touch 会话未修改时续期，返回写入 cookie 的值
+	Touch(ctx context.Context, value string, data *SessionData, ttl time.Duration) (string, error)
+	// Delete 删除会话
+	Delete(ctx context.Context, value string) error
+}
+
+type CookieSessionStoreConfig struct {
+	HashKey   []byte    // 签名密钥（HMAC-SHA256，至少 32 字节）
+	OldKeys   [][]byte  // 轮换前的签名密钥，只用于校验
+	Encryptor Encryptor // 加密器（如 AES256GCMEncryptor、SM4GCMEncryptor），为空时只签名不加密
+	MaxSize   int       // cookie 值最大长度（默认 4000）
+}
+
+// CookieSessionStore 会话数据保存在签名（可选加密）的 cookie 中，服务端无状态
+// 无法在服务端主动使会话失效，需要强制下线时使用 RedisSessionStore
+type CookieSessionStore struct {
+	hashKey   []byte
+	oldKeys   [][]byte
+	encryptor Encryptor
+	maxSize   int
+}
+
+func NewCookieSessionStore(config *CookieSessionStoreConfig) (*CookieSessionStore, error) {
+	if config == nil || len(config.HashKey) < 32 {
+		return nil, ErrInvalidKey
+	}
+
+	maxSize := config.MaxSize
+	if maxSize <= 0 {
+		maxSize = maxCookieValueSize
+	}
+
+	return &CookieSessionStore{
+		hashKey:   config.HashKey,
+		oldKeys:   config.OldKeys,
+		encryptor: config.Encryptor,
+		maxSize:   maxSize,
+	}, nil
+}
+
+func (s *CookieSessionStore) Load(ctx context.Context, value string) (*SessionData, error) {
+	payload, sign, ok := strings.Cut(value, ".")
+	if !ok {
+		return nil, nil
+	}
+
+	mac, err := base64.RawURLEncoding.DecodeString(sign)
+	if err != nil || !s.verify([]byte(payload), mac) {
+		return nil, nil
+	}
+
+	data, err := base64.RawURLEncoding.DecodeString(payload)
+	if err != nil {
+		return nil, nil
+	}
+	if s.encryptor != nil {
+		if data, err = s.encryptor.Decrypt(data); err != nil {
+			return nil, nil
+		}
+	}
+
+	var session SessionData
+	if err := json.Unmarshal(data, &session); err != nil {
+		return nil, nil
+	}
+	if !time.Now().Before(session.ExpiresAt) {
+		return nil, nil
+	}
+
+	return &session, nil
+}
+
+func (s *CookieSessionStore) Save(ctx context.Context, data *SessionData, ttl time.Duration) (string, error) {
+	plaintext, err := json.Marshal(data)
+	if err != nil {
+		return "", err
+	}
+	if s.encryptor != nil {
+		if plaintext, err = s.encryptor.Encrypt(plaintext); err != nil {
+			return "", err
+		}
+	}
+
+	payload := base64.RawURLEncoding.EncodeToString(plaintext)
+	value := payload + "." + base64.RawURLEncoding.EncodeToString(s.sign(s.hashKey, []byte(payload)))
+	if len(value) > s.maxSize {
+		return "", ErrSessionTooLarge
+	}
+
+	return value, nil
+}
+
+// Touch cookie 中保存了过期时间，续期需要重新生成 cookie
+func (s *CookieSessionStore) Touch(ctx context.Context, value string, data *SessionData, ttl time.Duration) (string, error) {
+	return s.Save(ctx, data, ttl)
+}
+
+// Delete cookie 由 SessionManager 清除，服务端没有需要删除的数据
+func (s *CookieSessionStore) Delete(ctx context.Context, value string) error {
+	return nil
+}
+
+func (s *CookieSessionStore) sign(key, payload []byte) []byte {
+	h := hmac.New(sha256.New, key)
+	h.Write(payload)
+	return h.Sum(nil)
+}
+
+func (s *CookieSessionStore) verify(payload, mac []byte) bool {
+	if hmac.Equal(s.sign(s.hashKey, payload), mac) {
+		return true
+	}
+	for _, key := range s.oldKeys {
+		if hmac.Equal(s.sign(key, payload), mac) {
+			return true
+		}
+	}
+	return false
+}
+
+// RedisSessionStore 基于 goo-redis 的会话存储，cookie 中只保存随机会话 ID
+// 每次访问续期（滑动过期），可以在服务端删除会话强制下线
+type RedisSessionStore struct {
+	client *gooredis.Client
+	prefix string
+}
+
+func NewRedisSessionStore(client *gooredis.Client, prefix string) *RedisSessionStore {
+	if prefix == "" {
+		prefix = "goohttp:session:"
+	}
+
+	return &RedisSessionStore{
+		client: client,
+		prefix: prefix,
+	}
+}
+
+func (s *RedisSessionStore) Load(ctx context.Context, value string) (*SessionData, error) {
+	if value == "" {
+		return nil, nil
+	}
+
+	// 同时读取剩余时间，Touch 只续期不重写数据，ExpiresAt 以 redis 中的过期时间为准
+	pipe := s.client.Client().Pipeline()
+	get := pipe.Get(ctx, s.prefix+value)
+	ttl := pipe.PTTL(ctx, s.prefix+value)
+	if _, err := pipe.Exec(ctx); err != nil && err != redis.Nil {
+		return nil, err
+	}
+
+	data, err := get.Bytes()
+	if err == redis.Nil {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var session SessionData
+	if err := json.Unmarshal(data, &session); err != nil {
+		return nil, err
+	}
+	if remaining := ttl.Val(); remaining > 0 {
+		session.ExpiresAt = time.Now().Add(remaining)
+	}
+
+	return &session, nil
+}
+
+// Save 以会话 ID 为 key 保存，会话 ID 轮换后旧 key 由 SessionManager 调用 Delete 删除
+func (s *RedisSessionStore) Save(ctx context.Context, data *SessionData, ttl time.Duration) (string, error) {
+	value, err := json.Marshal(data)
+	if err != nil {
+		return "", err
+	}
+
+	if err := s.client.Client().Set(ctx, s.prefix+data.Id, value, ttl).Err(); err != nil {
+		return "", err
+	}
+
+	return data.Id, nil
+}
+
+func (s *RedisSessionStore) Touch(ctx context.Context, value string, data *SessionData, ttl time.Duration) (string, error) {
+	ok, err := s.client.Client().Expire(ctx, s.prefix+value, ttl).Result()
+	if err != nil {
+		return "", err
+	}
+	// 会话在此期间被删除（如强制下线），不再续期
+	if !ok {
+		return "", nil
+	}
+
+	return value, nil
+}
+
+func (s *RedisSessionStore) Delete(ctx context.Context, value string) error {
+	if value == "" {
+		return nil
+	}
+	return s.client.Client().Del(ctx, s.prefix+value).Err()
+}
+
+// newSessionId 生成 256 位随机会话 ID
+func newSessionId() string {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		panic(err)
+	}
+	return base64.RawURLEncoding.EncodeToString(b)
+}
@@ -0,0 +1,421 @@
+package goohttp
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"errors"
+	"hash"
+	"io"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	SignAlgHMACSHA256 = "HMAC-SHA256"
+	SignAlgHMACSM3    = "HMAC-SM3"
+)
+
+// 签名校验错误码
+const (
+	SignCodeMissingParams   = 4020 // 缺少签名参数
+	SignCodeInvalidTime     = 4021 // 时间戳无效或超出时间窗口
+	SignCodeUnknownApp      = 4022 // 应用不存在
+	SignCodeInvalidSign     = 4023 // 签名错误
+	SignCodeReplay          = 4024 // 重复请求
+	SignCodeBodyTooLarge    = 4025 // 请求体过大
+	SignCodeUnsupportedAlg  = 4026 // 不支持的签名算法
+	SignCodeInternalFailure = 5020 // 签名校验内部错误
+)
+
+var (
+	ErrAppNotFound             = errors.New("应用不存在")
+	ErrSignSecretStoreRequired = errors.New("没有配置应用密钥存储")
+)
+
+var (
+	DefaultSignConfig = &SignConfig{
+		Algorithms:      []string{SignAlgHMACSHA256},
+		AppIdHeader:     "X-AppId",
+		TimestampHeader: "X-Timestamp",
+		NonceHeader:     "X-Nonce",
+		SignHeader:      "X-Sign",
+		SignAlgHeader:   "X-Sign-Method",
+		TimeWindow:      5 * time.Minute,
+		MaxBodySize:     10 * 1024 * 1024,
+	}
+)
+
+// AppSecretStore 应用密钥存储
+type AppSecretStore interface {
+	GetSecret(ctx context.Context, appId string) (string, error)
+}
+
+// AppSecretStoreFunc 函数形式的应用密钥存储
+type AppSecretStoreFunc func(ctx context.Context, appId string) (string, error)
+
+func (f AppSecretStoreFunc) GetSecret(ctx context.Context, appId string) (string, error) {
+	return f(ctx, appId)
+}
+
+// StaticAppSecretStore 静态应用密钥（appId => secret）
+type StaticAppSecretStore map[string]string
+
+func (s StaticAppSecretStore) GetSecret(ctx context.Context, appId string) (string, error) {
+	if secret, ok := s[appId]; ok {
+		return secret, nil
+	}
+	return "", ErrAppNotFound
+}
+
+type SignConfig struct {
+	SecretStore     AppSecretStore // 应用密钥存储
+	NonceStore      NonceStore     // nonce 存储，为空时不做防重放
+	Algorithms      []string       // 允许的签名算法，第一个为默认算法（默认 HMAC-SHA256）
+	AppIdHeader     string         // 应用 ID 请求头（默认 X-AppId）
+	TimestampHeader string         // 时间戳请求头（默认 X-Timestamp，秒或毫秒）
+	NonceHeader     string         // 随机串请求头（默认 X-Nonce）
+	SignHeader      string         // 签名请求头（默认 X-Sign）
+	SignAlgHeader   string         // 签名算法请求头（默认 X-Sign-Method）
+	RequireNonce    bool           // 是否必须携带 nonce，否则使用签名本身防重放
+	TimeWindow      time.Duration  // 时间戳允许偏差（默认 5 分钟）
+	MaxBodySize     int64          // 参与签名的最大请求体（默认 10MB）
+	SkipPaths       []string       // 跳过校验的路径，以 * 结尾表示前缀匹配
+}
+
+// SignParams 参与签名的参数
+type SignParams struct {
+	Method    string
+	Path      string
+	Query     url.Values
+	AppId     string
+	Timestamp string
+	Nonce     string
+	Body      []byte
+}
+
+// CanonicalString 构造待签名字符串
+//
+//	METHOD\n
+//	PATH\n
+//	排序后的查询参数（k=v&k=v，URL 编码）\n
+//	APPID\n
+//	TIMESTAMP\n
+//	NONCE\n
+//	HEX(HASH(BODY))
+func CanonicalString(alg string, params *SignParams) string {
+	var bodyHash []byte
+	if alg == SignAlgHMACSM3 {
+		sum := SM3Sum(params.Body)
+		bodyHash = sum[:]
+	} else {
+		sum := sha256.Sum256(params.Body)
+		bodyHash = sum[:]
+	}
+
+	var buf strings.Builder
+	buf.WriteString(strings.ToUpper(params.Method))
+	buf.WriteByte('\n')
+	buf.WriteString(params.Path)
+	buf.WriteByte('\n')
+	buf.WriteString(canonicalQuery(params.Query))
+	buf.WriteByte('\n')
+	buf.WriteString(params.AppId)
+	buf.WriteByte('\n')
+	buf.WriteString(params.Timestamp)
+	buf.WriteByte('\n')
+	buf.WriteString(params.Nonce)
+	buf.WriteByte('\n')
+	buf.WriteString(hex.EncodeToString(bodyHash))
+
+	return buf.String()
+}
+
+// Sign 计算签名（十六进制小写）
+func Sign(alg string, secret string, params *SignParams) (string, error) {
+	var h func() hash.Hash
+	switch alg {
+	case SignAlgHMACSHA256, "":
+		h = sha256.New
+	case SignAlgHMACSM3:
+		h = NewSM3
+	default:
+		return "", errors.New("unsupported sign algorithm")
+	}
+
+	mac := hmac.New(h, []byte(secret))
+	mac.Write([]byte(CanonicalString(alg, params)))
+	return hex.EncodeToString(mac.Sum(nil)), nil
+}
+
+// canonicalQuery 按 key 排序，同名参数按值排序
+func canonicalQuery(query url.Values) string {
+	if len(query) == 0 {
+		return ""
+	}
+
+	keys := make([]string, 0, len(query))
+	for k := range query {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	var pairs []string
+	for _, k := range keys {
+		values := append([]string(nil), query[k]...)
+		sort.Strings(values)
+		for _, v := range values {
+			pairs = append(pairs, url.QueryEscape(k)+"="+url.QueryEscape(v))
+		}
+	}
+
+	return strings.Join(pairs, "&")
+}
+
+type SignVerifier struct {
+	config *SignConfig
+	algs   map[string]bool
+}
+
+func NewSignVerifier(config *SignConfig) (*SignVerifier, error) {
+	if config == nil || config.SecretStore == nil {
+		return nil, ErrSignSecretStoreRequired
+	}
+
+	// 复制配置，填充默认值时不修改调用方的配置
+	c := *config
+	config = &c
+
+	if len(config.Algorithms) == 0 {
+		config.Algorithms = DefaultSignConfig.Algorithms
+	}
+	if config.AppIdHeader == "" {
+		config.AppIdHeader = DefaultSignConfig.AppIdHeader
+	}
+	if config.TimestampHeader == "" {
+		config.TimestampHeader = DefaultSignConfig.TimestampHeader
+	}
+	if config.NonceHeader == "" {
+		config.NonceHeader = DefaultSignConfig.NonceHeader
+	}
+	if config.SignHeader == "" {
+		config.SignHeader = DefaultSignConfig.SignHeader
+	}
+	if config.SignAlgHeader == "" {
+		config.SignAlgHeader = DefaultSignConfig.SignAlgHeader
+	}
+	if config.TimeWindow == 0 {
+		config.TimeWindow = DefaultSignConfig.TimeWindow
+	}
+	if config.MaxBodySize == 0 {
+		config.MaxBodySize = DefaultSignConfig.MaxBodySize
+	}
+
+	algs := make(map[string]bool)
+	for _, alg := range config.Algorithms {
+		algs[alg] = true
+	}
+
+	return &SignVerifier{
+		config: config,
+		algs:   algs,
+	}, nil
+}
+
+func (v *SignVerifier) skip(path string) bool {
+	for _, p := range v.config.SkipPaths {
+		if strings.HasSuffix(p, "*") {
+			if strings.HasPrefix(path, strings.TrimSuffix(p, "*")) {
+				return true
+			}
+		} else if p == path {
+			return true
+		}
+	}
+	return false
+}
+
+func (v *SignVerifier) verify(ctx *Context) bool {
+	if v.skip(ctx.Request.URL.Path) {
+		return true
+	}
+
+	c := ctx.Context
+	appId := c.GetHeader(v.config.AppIdHeader)
+	timestamp := c.GetHeader(v.config.TimestampHeader)
+	nonce := c.GetHeader(v.config.NonceHeader)
+	signature := c.GetHeader(v.config.SignHeader)
+
+	if appId == "" || timestamp == "" || signature == "" || (v.config.RequireNonce && nonce == "") {
+		ctx.Abort(http.StatusUnauthorized, SignCodeMissingParams, "缺少签名参数")
+		return false
+	}
+
+	alg := c.GetHeader(v.config.SignAlgHeader)
+	if alg == "" {
+		alg = v.config.Algorithms[0]
+	}
+	if !v.algs[alg] {
+		ctx.Abort(http.StatusUnauthorized, SignCodeUnsupportedAlg, "不支持的签名算法")
+		return false
+	}
+
+	// 时间窗口校验
+	ts, err := strconv.ParseInt(timestamp, 10, 64)
+	if err != nil {
+		ctx.Abort(http.StatusUnauthorized, SignCodeInvalidTime, "时间戳无效")
+		return false
+	}
+	var requestTime time.Time
+	if ts > 1e12 {
+		requestTime = time.UnixMilli(ts)
+	} else {
+		requestTime = time.Unix(ts, 0)
+	}
+	if diff := time.Since(requestTime); diff > v.config.TimeWindow || diff < -v.config.TimeWindow {
+		ctx.Abort(http.StatusUnauthorized, SignCodeInvalidTime, "请求已过期")
+		return false
+	}
+
+	secret, err := v.config.SecretStore.GetSecret(c.Request.Context(), appId)
+	if err != nil {
+		if errors.Is(err, ErrAppNotFound) {
+			ctx.Abort(http.StatusUnauthorized, SignCodeUnknownApp, "应用不存在")
+		} else {
+			ctx.Abort(http.StatusInternalServerError, SignCodeInternalFailure, "获取应用密钥失败")
+		}
+		return false
+	}
+
+	// 读取请求体并还原
+	var body []byte
+	if c.Request.Body != nil {
+		body, err = io.ReadAll(io.LimitReader(c.Request.Body, v.config.MaxBodySize+1))
+		if err != nil {
+			ctx.Abort(http.StatusBadRequest, SignCodeMissingParams, "获取请求数据失败")
+			return false
+		}
+		if int64(len(body)) > v.config.MaxBodySize {
+			ctx.Abort(http.StatusRequestEntityTooLarge, SignCodeBodyTooLarge, "请求体过大")
+			return false
+		}
+		c.Request.Body = io.NopCloser(bytes.NewReader(body))
+	}
+
+	expected, err := Sign(alg, secret, &SignParams{
+		Method:    c.Request.Method,
+		Path:      c.Request.URL.Path,
+		Query:     c.Request.URL.Query(),
+		AppId:     appId,
+		Timestamp: timestamp,
+		Nonce:     nonce,
+		Body:      body,
+	})
+	if err != nil {
+		ctx.Abort(http.StatusUnauthorized, SignCodeUnsupportedAlg, "不支持的签名算法")
+		return false
+	}
+
+	if subtle.ConstantTimeCompare([]byte(expected), []byte(strings.ToLower(signature))) != 1 {
+		ctx.Abort(http.StatusUnauthorized, SignCodeInvalidSign, "签名错误")
+		return false
+	}
+
+	// 防重放：签名通过后再记录，避免伪造请求占用 nonce
+	if v.config.NonceStore != nil {
+		key := appId + ":" + nonce
+		if nonce == "" {
+			key = appId + ":" + expected
+		}
+
+		ok, err := v.config.NonceStore.Use(c.Request.Context(), key, 2*v.config.TimeWindow)
+		if err != nil {
+			ctx.Abort(http.StatusInternalServerError, SignCodeInternalFailure, "防重放校验失败")
+			return false
+		}
+		if !ok {
+			ctx.Abort(http.StatusUnauthorized, SignCodeReplay, "重复请求")
+			return false
+		}
+	}
+
+	ctx.Set("app-id", appId)
+
+	return true
+}
+
+// Handler 用于路由或路由组的签名校验处理函数
+func (v *SignVerifier) Handler() HandlerFunc {
+	return func(ctx *Context) {
+		v.verify(ctx)
+	}
+}
+
+func SignMiddleware(verifier *SignVerifier) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		ctx := &Context{Context: c}
+
+		if !verifier.verify(ctx) {
+			return
+		}
+
+		c.Next()
+	}
+}
+
+// AppId 获取签名校验通过的应用 ID
+func (c *Context) AppId() string {
+	return c.Context.GetString("app-id")
+}
+
+// SignRequest 为客户端请求添加签名请求头（X-AppId、X-Timestamp、X-Nonce、X-Sign、X-Sign-Method）
+// 会读取并还原 req.Body
+func SignRequest(req *http.Request, alg, appId, secret, nonce string) error {
+	if alg == "" {
+		alg = SignAlgHMACSHA256
+	}
+
+	var body []byte
+	if req.Body != nil {
+		var err error
+		if body, err = io.ReadAll(req.Body); err != nil {
+			return err
+		}
+		req.Body.Close()
+		req.Body = io.NopCloser(bytes.NewReader(body))
+	}
+
+	timestamp := strconv.FormatInt(time.Now().Unix(), 10)
+
+	signature, err := Sign(alg, secret, &SignParams{
+		Method:    req.Method,
+		Path:      req.URL.Path,
+		Query:     req.URL.Query(),
+		AppId:     appId,
+		Timestamp: timestamp,
+		Nonce:     nonce,
+		Body:      body,
+	})
+	if err != nil {
+		return err
+	}
+
+	req.Header.Set(DefaultSignConfig.AppIdHeader, appId)
+	req.Header.Set(DefaultSignConfig.TimestampHeader, timestamp)
+	req.Header.Set(DefaultSignConfig.SignHeader, signature)
+	req.Header.Set(DefaultSignConfig.SignAlgHeader, alg)
+	if nonce != "" {
+		req.Header.Set(DefaultSignConfig.NonceHeader, nonce)
+	}
+
+	return nil
+}
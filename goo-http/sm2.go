@@ -0,0 +1,289 @@
+package goohttp
+
+import (
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/binary"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"math/big"
+	"strings"
+
+	"github.com/emmansun/gmsm/sm2/sm2ec"
+)
+
+// SM2 国密椭圆曲线公钥密码算法（GB/T 32918-2016），使用推荐曲线 sm2p256v1
+// 密文格式为 C1C3C2（GB/T 32918.4-2016），C1 为带 04 前缀的非压缩点
+
+var (
+	ErrSM2InvalidPublicKey  = errors.New("无效的 SM2 公钥")
+	ErrSM2InvalidPrivateKey = errors.New("无效的 SM2 私钥")
+	ErrSM2InvalidCiphertext = errors.New("无效的 SM2 密文")
+)
+
+const (
+	sm2CoordSize = 32
+	sm2PointSize = 1 + 2*sm2CoordSize
+
+	// SM2Overhead SM2 密文比明文多出的长度：C1（65 字节）+ C3（32 字节）
+	SM2Overhead = sm2PointSize + SM3Size
+)
+
+// SM2P256 SM2 推荐曲线
+// 使用 gmsm 的常数时间实现（定长域元素运算，amd64/arm64 使用汇编），避免 elliptic.CurveParams 通用实现的时序侧信道
+func SM2P256() elliptic.Curve {
+	return sm2ec.P256()
+}
+
+// SM2PublicKey SM2 公钥
+type SM2PublicKey struct {
+	X, Y *big.Int
+}
+
+// SM2PrivateKey SM2 私钥
+type SM2PrivateKey struct {
+	SM2PublicKey
+	D *big.Int
+}
+
+// GenerateSM2Key 生成 SM2 密钥对
+func GenerateSM2Key(random io.Reader) (*SM2PrivateKey, error) {
+	if random == nil {
+		random = rand.Reader
+	}
+
+	curve := SM2P256()
+
+	// 私钥取值范围 [1, n-2]
+	max := new(big.Int).Sub(curve.Params().N, big.NewInt(2))
+	d, err := rand.Int(random, max)
+	if err != nil {
+		return nil, err
+	}
+	d.Add(d, big.NewInt(1))
+
+	return newSM2PrivateKey(d), nil
+}
+
+func newSM2PrivateKey(d *big.Int) *SM2PrivateKey {
+	x, y := SM2P256().ScalarBaseMult(sm2Bytes(d))
+	return &SM2PrivateKey{
+		SM2PublicKey: SM2PublicKey{X: x, Y: y},
+		D:            d,
+	}
+}
+
+// ParseSM2PrivateKey 解析十六进制私钥（64 位十六进制字符）
+func ParseSM2PrivateKey(s string) (*SM2PrivateKey, error) {
+	buf, err := hex.DecodeString(strings.TrimSpace(s))
+	if err != nil || len(buf) != sm2CoordSize {
+		return nil, ErrSM2InvalidPrivateKey
+	}
+
+	d := new(big.Int).SetBytes(buf)
+	max := new(big.Int).Sub(SM2P256().Params().N, big.NewInt(1))
+	if d.Sign() <= 0 || d.Cmp(max) >= 0 {
+		return nil, ErrSM2InvalidPrivateKey
+	}
+
+	return newSM2PrivateKey(d), nil
+}
+
+// ParseSM2PublicKey 解析十六进制公钥，支持带 04 前缀的非压缩格式（130 位）和不带前缀的格式（128 位）
+func ParseSM2PublicKey(s string) (*SM2PublicKey, error) {
+	buf, err := hex.DecodeString(strings.TrimSpace(s))
+	if err != nil {
+		return nil, ErrSM2InvalidPublicKey
+	}
+	if len(buf) == 2*sm2CoordSize {
+		buf = append([]byte{4}, buf...)
+	}
+
+	x, y, ok := sm2UnmarshalPoint(buf)
+	if !ok {
+		return nil, ErrSM2InvalidPublicKey
+	}
+
+	return &SM2PublicKey{X: x, Y: y}, nil
+}
+
+// Hex 十六进制私钥
+func (k *SM2PrivateKey) Hex() string {
+	return hex.EncodeToString(sm2Bytes(k.D))
+}
+
+// Public 公钥
+func (k *SM2PrivateKey) Public() *SM2PublicKey {
+	return &k.SM2PublicKey
+}
+
+// Format 打印私钥时只输出公钥，对所有格式化动词生效
+func (k *SM2PrivateKey) Format(f fmt.State, verb rune) {
+	fmt.Fprint(f, k.String())
+}
+
+// String 实现 fmt.Stringer
+func (k *SM2PrivateKey) String() string {
+	return "SM2PrivateKey{public:" + k.SM2PublicKey.Hex() + ", d:******}"
+}
+
+// Hex 带 04 前缀的十六进制非压缩公钥
+func (k *SM2PublicKey) Hex() string {
+	return hex.EncodeToString(sm2MarshalPoint(k.X, k.Y))
+}
+
+// SM2Encrypt SM2 公钥加密，输出 C1C3C2 格式的密文
+func SM2Encrypt(random io.Reader, pub *SM2PublicKey, plaintext []byte) ([]byte, error) {
+	if random == nil {
+		random = rand.Reader
+	}
+	if pub == nil || pub.X == nil || pub.Y == nil || !SM2P256().IsOnCurve(pub.X, pub.Y) {
+		return nil, ErrSM2InvalidPublicKey
+	}
+
+	n := SM2P256().Params().N
+	max := new(big.Int).Sub(n, big.NewInt(1))
+
+	for {
+		k, err := rand.Int(random, max)
+		if err != nil {
+			return nil, err
+		}
+		k.Add(k, big.NewInt(1))
+
+		if ciphertext, ok := sm2EncryptWithK(pub, plaintext, k); ok {
+			return ciphertext, nil
+		}
+	}
+}
+
+// sm2EncryptWithK 使用指定的随机数 k 加密，密钥派生结果全为 0 时返回 false，需要重新选择 k
+func sm2EncryptWithK(pub *SM2PublicKey, plaintext []byte, k *big.Int) ([]byte, bool) {
+	curve := SM2P256()
+
+	x1, y1 := curve.ScalarBaseMult(sm2Bytes(k))
+	x2, y2 := curve.ScalarMult(pub.X, pub.Y, sm2Bytes(k))
+
+	x2Bytes, y2Bytes := sm2Bytes(x2), sm2Bytes(y2)
+
+	t := sm2KDF(len(plaintext), x2Bytes, y2Bytes)
+	if len(plaintext) > 0 && sm2AllZero(t) {
+		return nil, false
+	}
+
+	ciphertext := make([]byte, 0, SM2Overhead+len(plaintext))
+	ciphertext = append(ciphertext, sm2MarshalPoint(x1, y1)...)
+
+	h := NewSM3()
+	h.Write(x2Bytes)
+	h.Write(plaintext)
+	h.Write(y2Bytes)
+	ciphertext = h.Sum(ciphertext)
+
+	for i, b := range plaintext {
+		ciphertext = append(ciphertext, b^t[i])
+	}
+
+	return ciphertext, true
+}
+
+// SM2Decrypt SM2 私钥解密，密文为 C1C3C2 格式
+func SM2Decrypt(priv *SM2PrivateKey, ciphertext []byte) ([]byte, error) {
+	if priv == nil || priv.D == nil {
+		return nil, ErrSM2InvalidPrivateKey
+	}
+	if len(ciphertext) < SM2Overhead {
+		return nil, ErrSM2InvalidCiphertext
+	}
+
+	x1, y1, ok := sm2UnmarshalPoint(ciphertext[:sm2PointSize])
+	if !ok {
+		return nil, ErrSM2InvalidCiphertext
+	}
+	c3 := ciphertext[sm2PointSize:SM2Overhead]
+	c2 := ciphertext[SM2Overhead:]
+
+	x2, y2 := SM2P256().ScalarMult(x1, y1, sm2Bytes(priv.D))
+	x2Bytes, y2Bytes := sm2Bytes(x2), sm2Bytes(y2)
+
+	t := sm2KDF(len(c2), x2Bytes, y2Bytes)
+	if len(c2) > 0 && sm2AllZero(t) {
+		return nil, ErrSM2InvalidCiphertext
+	}
+
+	plaintext := make([]byte, len(c2))
+	for i, b := range c2 {
+		plaintext[i] = b ^ t[i]
+	}
+
+	h := NewSM3()
+	h.Write(x2Bytes)
+	h.Write(plaintext)
+	h.Write(y2Bytes)
+	if subtle.ConstantTimeCompare(h.Sum(nil), c3) != 1 {
+		return nil, ErrSM2InvalidCiphertext
+	}
+
+	return plaintext, nil
+}
+
+// sm2KDF 基于 SM3 的密钥派生函数
+func sm2KDF(length int, z ...[]byte) []byte {
+	out := make([]byte, 0, length+SM3Size)
+
+	var ct [4]byte
+	for counter := uint32(1); len(out) < length; counter++ {
+		binary.BigEndian.PutUint32(ct[:], counter)
+
+		h := NewSM3()
+		for _, b := range z {
+			h.Write(b)
+		}
+		h.Write(ct[:])
+		out = h.Sum(out)
+	}
+
+	return out[:length]
+}
+
+func sm2AllZero(b []byte) bool {
+	for _, v := range b {
+		if v != 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// sm2Bytes 定长 32 字节大端编码
+func sm2Bytes(v *big.Int) []byte {
+	buf := make([]byte, sm2CoordSize)
+	return v.FillBytes(buf)
+}
+
+func sm2MarshalPoint(x, y *big.Int) []byte {
+	buf := make([]byte, 0, sm2PointSize)
+	buf = append(buf, 4)
+	buf = append(buf, sm2Bytes(x)...)
+	return append(buf, sm2Bytes(y)...)
+}
+
+func sm2UnmarshalPoint(buf []byte) (*big.Int, *big.Int, bool) {
+	if len(buf) != sm2PointSize || buf[0] != 4 {
+		return nil, nil, false
+	}
+
+	curve := SM2P256()
+	p := curve.Params().P
+
+	x := new(big.Int).SetBytes(buf[1 : 1+sm2CoordSize])
+	y := new(big.Int).SetBytes(buf[1+sm2CoordSize:])
+	if x.Cmp(p) >= 0 || y.Cmp(p) >= 0 || !curve.IsOnCurve(x, y) {
+		return nil, nil, false
+	}
+
+	return x, y, true
+}
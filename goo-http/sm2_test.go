@@ -0,0 +1,103 @@
+package goohttp
+
+import (
+	"bytes"
+	"encoding/hex"
+	"math/big"
+	"strings"
+	"testing"
+)
+
+// GB/T 32918.5-2017 附录 C 公钥加密示例
+const (
+	sm2TestPrivateKey = "3945208F7B2144B13F36E38AC6D39F95889393692860B51A42FB81EF4DF7C5B8"
+	sm2TestPublicKey  = "04" +
+		"09F9DF311E5421A150DD7D161E4BC5C672179FAD1833FC076BB08FF356F35020" +
+		"CCEA490CE26775A52DC6EA718CC1AA600AED05FBF35E084A6632F6072DA9AD13"
+	sm2TestK          = "59276E27D506861A16680F3AD9C02DCCEF3CC1FA3CDBE4CE6D54B80DEAC1BC21"
+	sm2TestPlaintext  = "encryption standard"
+	sm2TestCiphertext = "04" +
+		"04EBFC718E8D1798620432268E77FEB6415E2EDE0E073C0F4F640ECD2E149A73" +
+		"E858F9D81E5430A57B36DAAB8F950A3C64E6EE6A63094D99283AFF767E124DF0" +
+		"59983C18F809E262923C53AEC295D30383B54E39D609D160AFCB1908D0BD8766" +
+		"21886CA989CA9C7D58087307CA93092D651EFA"
+)
+
+func TestSM2Vectors(t *testing.T) {
+	priv, err := ParseSM2PrivateKey(sm2TestPrivateKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := priv.Public().Hex(); !strings.EqualFold(got, sm2TestPublicKey) {
+		t.Fatalf("public key = %s, want %s", got, sm2TestPublicKey)
+	}
+
+	pub, err := ParseSM2PublicKey(sm2TestPublicKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	k, _ := new(big.Int).SetString(sm2TestK, 16)
+	want, _ := hex.DecodeString(sm2TestCiphertext)
+
+	ciphertext, ok := sm2EncryptWithK(pub, []byte(sm2TestPlaintext), k)
+	if !ok {
+		t.Fatal("sm2EncryptWithK rejected k")
+	}
+	if !bytes.Equal(ciphertext, want) {
+		t.Fatalf("ciphertext = %x, want %x", ciphertext, want)
+	}
+
+	plaintext, err := SM2Decrypt(priv, want)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(plaintext) != sm2TestPlaintext {
+		t.Fatalf("plaintext = %q, want %q", plaintext, sm2TestPlaintext)
+	}
+
+	// 篡改密文后 C3 校验失败
+	want[len(want)-1] ^= 1
+	if _, err := SM2Decrypt(priv, want); err != ErrSM2InvalidCiphertext {
+		t.Fatalf("tampered ciphertext err = %v, want %v", err, ErrSM2InvalidCiphertext)
+	}
+}
+
+func TestSM2EncryptRoundTrip(t *testing.T) {
+	priv, err := GenerateSM2Key(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, size := range []int{0, 1, 32, 1000} {
+		message := bytes.Repeat([]byte{0x5a}, size)
+
+		ciphertext, err := SM2Encrypt(nil, priv.Public(), message)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(ciphertext) != SM2Overhead+size {
+			t.Fatalf("len(ciphertext) = %d, want %d", len(ciphertext), SM2Overhead+size)
+		}
+
+		plaintext, err := SM2Decrypt(priv, ciphertext)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !bytes.Equal(plaintext, message) {
+			t.Fatalf("plaintext = %x, want %x", plaintext, message)
+		}
+	}
+}
+
+func TestSM2InvalidPublicKey(t *testing.T) {
+	// 不在曲线上的点
+	if _, err := ParseSM2PublicKey("04" + strings.Repeat("01", 64)); err != ErrSM2InvalidPublicKey {
+		t.Fatalf("ParseSM2PublicKey err = %v, want %v", err, ErrSM2InvalidPublicKey)
+	}
+
+	pub := &SM2PublicKey{X: big.NewInt(1), Y: big.NewInt(1)}
+	if _, err := SM2Encrypt(nil, pub, []byte("data")); err != ErrSM2InvalidPublicKey {
+		t.Fatalf("SM2Encrypt err = %v, want %v", err, ErrSM2InvalidPublicKey)
+	}
+}
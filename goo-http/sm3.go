@@ -0,0 +1,24 @@
+package goohttp
+
+import (
+	"hash"
+
+	"github.com/emmansun/gmsm/sm3"
+)
+
+// SM3 国密杂凑算法（GB/T 32905-2016），使用 gmsm 的实现（amd64/arm64 使用汇编）
+
+const (
+	SM3Size      = sm3.Size      // 摘要长度（字节）
+	SM3BlockSize = sm3.BlockSize // 分组长度（字节）
+)
+
+// NewSM3 创建 SM3 哈希对象
+func NewSM3() hash.Hash {
+	return sm3.New()
+}
+
+// SM3Sum 计算 SM3 摘要
+func SM3Sum(data []byte) [SM3Size]byte {
+	return sm3.Sum(data)
+}
@@ -0,0 +1,35 @@
+package goohttp
+
+import (
+	"bytes"
+	"encoding/hex"
+	"strings"
+	"testing"
+)
+
+// GB/T 32905-2016 附录 A 示例
+func TestSM3Vectors(t *testing.T) {
+	tests := []struct {
+		in   string
+		want string
+	}{
+		{"abc", "66c7f0f462eeedd9d1f2d46bdc10e4e24167c4875cf2f7a2297da02b8f4ba8e0"},
+		{strings.Repeat("abcd", 16), "debe9ff92275b8a138604889c18e5a4d6fdb70e5387e5765293dcba39c0c5732"},
+	}
+
+	for _, tt := range tests {
+		sum := SM3Sum([]byte(tt.in))
+		if got := hex.EncodeToString(sum[:]); got != tt.want {
+			t.Errorf("SM3Sum(%q) = %s, want %s", tt.in, got, tt.want)
+		}
+
+		// 分段写入结果一致
+		h := NewSM3()
+		for _, b := range []byte(tt.in) {
+			h.Write([]byte{b})
+		}
+		if got := h.Sum(nil); !bytes.Equal(got, sum[:]) {
+			t.Errorf("NewSM3 streaming %q = %x, want %s", tt.in, got, tt.want)
+		}
+	}
+}
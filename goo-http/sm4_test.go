@@ -0,0 +1,53 @@
+package goohttp
+
+import (
+	"bytes"
+	"encoding/hex"
+	"testing"
+)
+
+// GB/T 32907-2016 附录 A 示例
+func TestSM4Vectors(t *testing.T) {
+	key, _ := hex.DecodeString("0123456789abcdeffedcba9876543210")
+	want, _ := hex.DecodeString("681edf34d206965e86b3e94f536e4246")
+
+	block, err := NewSM4Cipher(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ciphertext := make([]byte, len(key))
+	block.Encrypt(ciphertext, key)
+	if !bytes.Equal(ciphertext, want) {
+		t.Fatalf("Encrypt = %x, want %x", ciphertext, want)
+	}
+
+	plaintext := make([]byte, len(key))
+	block.Decrypt(plaintext, ciphertext)
+	if !bytes.Equal(plaintext, key) {
+		t.Fatalf("Decrypt = %x, want %x", plaintext, key)
+	}
+}
+
+// GB/T 32907-2016 附录 A 示例 2：同一密钥加密 1000000 次
+func TestSM4VectorsRepeated(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping in short mode")
+	}
+
+	key, _ := hex.DecodeString("0123456789abcdeffedcba9876543210")
+	want, _ := hex.DecodeString("595298c7c6fd271f0402f804c33d3f66")
+
+	block, err := NewSM4Cipher(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	data := append([]byte(nil), key...)
+	for i := 0; i < 1000000; i++ {
+		block.Encrypt(data, data)
+	}
+	if !bytes.Equal(data, want) {
+		t.Fatalf("Encrypt x1000000 = %x, want %x", data, want)
+	}
+}
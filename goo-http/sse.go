@@ -0,0 +1,301 @@
+package goohttp
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+var (
+	ErrSSEClosed = errors.New("SSE 连接已关闭")
+)
+
+var (
+	DefaultSSEConfig = &SSEConfig{
+		Heartbeat: 15 * time.Second,
+	}
+)
+
+type SSEConfig struct {
+	Heartbeat time.Duration // 心跳间隔，防止代理因空闲断开连接（默认 15 秒，小于 0 时不发送）
+	Retry     time.Duration // 建议客户端断线重连的等待时间，为 0 时不发送
+}
+
+type SSEOption func(c *SSEConfig)
+
+func (o SSEOption) Apply(c *SSEConfig) {
+	o(c)
+}
+
+func WithSSEHeartbeat(heartbeat time.Duration) SSEOption {
+	return func(c *SSEConfig) {
+		c.Heartbeat = heartbeat
+	}
+}
+
+func WithSSERetry(retry time.Duration) SSEOption {
+	return func(c *SSEConfig) {
+		c.Retry = retry
+	}
+}
+
+// SSEEvent 事件
+type SSEEvent struct {
+	Id    string        // 事件ID，客户端重连时通过 Last-Event-ID 请求头带回
+	Event string        // 事件类型，为空时客户端触发 message 事件
+	Data  any           // 数据：string / []byte 原样输出，其他类型输出 JSON
+	Retry time.Duration // 建议客户端断线重连的等待时间
+}
+
+// SSEStream Server-Sent Events 输出流
+type SSEStream struct {
+	ctx      *Context
+	mu       sync.Mutex
+	done     chan struct{}
+	closed   bool
+	closeErr error
+	once     sync.Once
+}
+
+// SSE 开始输出 Server-Sent Events，返回的流在客户端断开、调用 Close 或处理函数返回后关闭
+//
+//	stream := ctx.SSE()
+//	defer stream.Close()
+//	for chunk := range chunks {
+//		if err := stream.Send(&goohttp.SSEEvent{Event: "delta", Data: chunk}); err != nil {
+//			return
+//		}
+//	}
+func (c *Context) SSE(opts ...SSEOption) *SSEStream {
+	config := *DefaultSSEConfig
+	for _, opt := range opts {
+		opt.Apply(&config)
+	}
+
+	// 通知缓冲响应的中间件（加解密、响应钩子、缓存、幂等）跳过当前响应
+	c.Context.Set("streaming", true)
+
+	header := c.Writer.Header()
+	header.Set("Content-Type", "text/event-stream; charset=utf-8")
+	header.Set("Cache-Control", "no-cache")
+	header.Set("Connection", "keep-alive")
+	header.Set("X-Accel-Buffering", "no") // 关闭 nginx 缓冲
+	header.Del("Content-Length")
+
+	c.Status(200)
+
+	s := &SSEStream{
+		ctx:  c,
+		done: make(chan struct{}),
+	}
+	// 处理函数返回时由 wrapHandlers 关闭，之后心跳不再写入
+	c.Context.Set("sse-stream", s)
+
+	if config.Retry > 0 {
+		s.write("retry: " + strconv.FormatInt(config.Retry.Milliseconds(), 10) + "\n\n")
+	} else {
+		s.flush()
+	}
+
+	// gin.Context 在请求结束后会被复用，goroutine 中只使用这里取得的请求上下文
+	go s.watch(c.Request.Context(), config.Heartbeat)
+
+	return s
+}
+
+// IsStreaming 当前响应是否为流式输出
+func (c *Context) IsStreaming() bool {
+	return c.Context.GetBool("streaming")
+}
+
+// LastEventId 客户端重连时带回的最后一个事件ID，用于断点续传
+func (s *SSEStream) LastEventId() string {
+	if id := s.ctx.GetHeader("Last-Event-ID"); id != "" {
+		return id
+	}
+	// EventSource 无法设置请求头时，可通过查询参数传递
+	return s.ctx.Query("lastEventId")
+}
+
+// Done 客户端断开或流关闭时关闭
+func (s *SSEStream) Done() <-chan struct{} {
+	return s.done
+}
+
+// Send 发送事件
+func (s *SSEStream) Send(event *SSEEvent) error {
+	var buf strings.Builder
+
+	if event.Id != "" {
+		buf.WriteString("id: ")
+		buf.WriteString(sseSingleLine(event.Id))
+		buf.WriteByte('\n')
+	}
+	if event.Event != "" {
+		buf.WriteString("event: ")
+		buf.WriteString(sseSingleLine(event.Event))
+		buf.WriteByte('\n')
+	}
+	if event.Retry > 0 {
+		buf.WriteString("retry: ")
+		buf.WriteString(strconv.FormatInt(event.Retry.Milliseconds(), 10))
+		buf.WriteByte('\n')
+	}
+
+	data, err := sseData(event.Data)
+	if err != nil {
+		return err
+	}
+	// 多行数据每行一个 data 字段，客户端按换行拼接
+	for _, line := range strings.Split(data, "\n") {
+		buf.WriteString("data: ")
+		buf.WriteString(strings.TrimSuffix(line, "\r"))
+		buf.WriteByte('\n')
+	}
+	buf.WriteByte('\n')
+
+	return s.write(buf.String())
+}
+
+// SendData 发送 message 事件
+func (s *SSEStream) SendData(data any) error {
+	return s.Send(&SSEEvent{Data: data})
+}
+
+// SendEvent 发送指定类型的事件
+func (s *SSEStream) SendEvent(event string, data any) error {
+	return s.Send(&SSEEvent{Event: event, Data: data})
+}
+
+// Comment 发送注释，客户端会忽略，可用于保活
+func (s *SSEStream) Comment(text string) error {
+	var buf strings.Builder
+	for _, line := range strings.Split(text, "\n") {
+		buf.WriteString(": ")
+		buf.WriteString(line)
+		buf.WriteByte('\n')
+	}
+	buf.WriteByte('\n')
+
+	return s.write(buf.String())
+}
+
+// Close 关闭流
+func (s *SSEStream) Close() {
+	s.close(nil)
+}
+
+// Err 流关闭的原因，客户端断开时为请求上下文的错误
+func (s *SSEStream) Err() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.closeErr
+}
+
+func (s *SSEStream) write(data string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.closed {
+		return ErrSSEClosed
+	}
+
+	if _, err := s.ctx.Writer.WriteString(data); err != nil {
+		s.closeLocked(err)
+		return err
+	}
+	s.ctx.Writer.Flush()
+
+	return nil
+}
+
+func (s *SSEStream) flush() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if !s.closed {
+		s.ctx.Writer.Flush()
+	}
+}
+
+// watch 发送心跳并检测客户端断开
+func (s *SSEStream) watch(ctx context.Context, heartbeat time.Duration) {
+	var tick <-chan time.Time
+	if heartbeat > 0 {
+		ticker := time.NewTicker(heartbeat)
+		defer ticker.Stop()
+		tick = ticker.C
+	}
+
+	for {
+		select {
+		case <-tick:
+			if err := s.Comment("ping"); err != nil {
+				return
+			}
+		case <-ctx.Done():
+			s.close(ctx.Err())
+			return
+		case <-s.done:
+			return
+		}
+	}
+}
+
+func (s *SSEStream) close(err error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.closeLocked(err)
+}
+
+func (s *SSEStream) closeLocked(err error) {
+	s.once.Do(func() {
+		s.closed = true
+		s.closeErr = err
+		close(s.done)
+	})
+}
+
+// closeSSE 关闭处理函数中打开且未关闭的流
+func closeSSE(c *gin.Context) {
+	if v, ok := c.Get("sse-stream"); ok {
+		if s, ok := v.(*SSEStream); ok {
+			s.Close()
+		}
+	}
+}
+
+func sseData(data any) (string, error) {
+	switch v := data.(type) {
+	case nil:
+		return "", nil
+	case string:
+		return v, nil
+	case []byte:
+		return string(v), nil
+	}
+
+	buf, err := json.Marshal(data)
+	if err != nil {
+		return "", err
+	}
+	return string(buf), nil
+}
+
+func sseSingleLine(s string) string {
+	return strings.NewReplacer("\r", "", "\n", "").Replace(s)
+}
+
+// isStreaming 响应是否为流式输出（调用了 Context.SSE 或 Content-Type 为 text/event-stream）
+func isStreaming(c *gin.Context) bool {
+	if c.GetBool("streaming") {
+		return true
+	}
+	return strings.HasPrefix(c.Writer.Header().Get("Content-Type"), "text/event-stream")
+}
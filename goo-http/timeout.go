@@ -0,0 +1,330 @@
+package goohttp
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"google.golang.org/grpc/codes"
+)
+
+// 超时与过载错误码
+const (
+	TimeoutCodeTimeout   = 5040 // 请求处理超时
+	LoadShedCodeOverload = 5033 // 服务过载
+)
+
+var (
+	ErrRequestTimeout  = errors.New("请求处理超时")
+	ErrServiceOverload = errors.New("服务繁忙，请稍后重试")
+)
+
+var (
+	BizErrTimeout  = RegisterBizError(TimeoutCodeTimeout, "goohttp.timeout", http.StatusGatewayTimeout, codes.DeadlineExceeded)
+	BizErrOverload = RegisterBizError(LoadShedCodeOverload, "goohttp.overload", http.StatusServiceUnavailable, codes.Unavailable)
+)
+
+func init() {
+	RegisterMessages("zh-CN", map[string]string{
+		"goohttp.timeout":  ErrRequestTimeout.Error(),
+		"goohttp.overload": ErrServiceOverload.Error(),
+	})
+	RegisterMessages("en", map[string]string{
+		"goohttp.timeout":  "Request timeout",
+		"goohttp.overload": "Service is busy, please retry later",
+	})
+}
+
+var (
+	DefaultTimeoutConfig = &TimeoutConfig{
+		Timeout: 30 * time.Second,
+	}
+)
+
+type TimeoutConfig struct {
+	Timeout   time.Duration            // 默认超时时间（默认 30 秒），小于 0 时只对 Routes 中的路由生效
+	Routes    map[string]time.Duration // 按路由设置超时，如 "POST /api/export" 或 "/api/export"，小于 0 表示不限制（如 SSE、文件下载）
+	Error     *BizError                // 超时返回的业务错误（默认 BizErrTimeout，HTTP 504，业务码 5040）
+	OnTimeout func(ctx *Context)       // 超时回调（如记录日志），在处理函数返回后调用
+}
+
+// TimeoutMiddleware 请求超时中间件
+// 超时后取消 Request.Context() 并立即返回超时响应，处理函数之后的输出会被丢弃。
+// 处理函数需要将 ctx.Request.Context() 传给数据库、下游调用等，才能在超时后及时返回
+func TimeoutMiddleware(config *TimeoutConfig) gin.HandlerFunc {
+	if config == nil {
+		config = DefaultTimeoutConfig
+	}
+
+	timeout := config.Timeout
+	if timeout == 0 {
+		timeout = DefaultTimeoutConfig.Timeout
+	}
+
+	return func(c *gin.Context) {
+		ctx := &Context{Context: c}
+
+		d := timeout
+		if config.Routes != nil {
+			route := c.FullPath()
+			if v, ok := config.Routes[c.Request.Method+" "+route]; ok {
+				d = v
+			} else if v, ok := config.Routes[route]; ok {
+				d = v
+			}
+		}
+
+		if d <= 0 {
+			c.Next()
+			return
+		}
+
+		serveWithTimeout(ctx, d, config, c.Next)
+	}
+}
+
+// TimeoutHandler 路由级超时，包装处理函数
+//
+//	server.Post("/api/export", goohttp.TimeoutHandler(2*time.Minute, export))
+func TimeoutHandler(timeout time.Duration, handler HandlerFunc) HandlerFunc {
+	config := &TimeoutConfig{Timeout: timeout}
+	return func(ctx *Context) {
+		serveWithTimeout(ctx, timeout, config, func() {
+			handler(ctx)
+		})
+	}
+}
+
+func serveWithTimeout(ctx *Context, timeout time.Duration, config *TimeoutConfig, next func()) {
+	c := ctx.Context
+
+	reqCtx, cancel := context.WithTimeout(c.Request.Context(), timeout)
+	defer cancel()
+	c.Request = c.Request.WithContext(reqCtx)
+
+	bizErr := config.Error
+	if bizErr == nil {
+		bizErr = BizErrTimeout
+	}
+	// 超时响应在当前 goroutine 中生成，超时后不再访问 gin.Context
+	resp := bizErr.Response(ctx)
+	body, _ := json.Marshal(resp)
+
+	writer := &timeoutResponseWriter{
+		ResponseWriter: c.Writer,
+		header:         c.Writer.Header().Clone(),
+		buffer:         getBuffer(),
+		timeoutStatus:  bizErr.HTTPStatus,
+		timeoutBody:    body,
+	}
+	c.Writer = writer
+
+	done := make(chan struct{})
+	go func() {
+		select {
+		case <-reqCtx.Done():
+			if errors.Is(reqCtx.Err(), context.DeadlineExceeded) {
+				writer.timeout()
+			}
+		case <-done:
+		}
+	}()
+
+	defer func() {
+		close(done)
+
+		timedOut := writer.finish(reqCtx)
+		c.Writer = writer.ResponseWriter
+		putBuffer(writer.buffer)
+
+		if timedOut {
+			c.Set("response-code", resp.Code)
+			c.Error(ErrRequestTimeout)
+			c.Abort()
+			if config.OnTimeout != nil {
+				config.OnTimeout(ctx)
+			}
+		}
+	}()
+
+	next()
+}
+
+// 超时响应写入器
+// 处理函数使用独立的响应头并缓冲输出，超时后由监视 goroutine 输出超时响应，两者不会同时写入
+type timeoutResponseWriter struct {
+	gin.ResponseWriter
+	header   http.Header
+	buffer   *bytes.Buffer
+	mu       sync.Mutex
+	status   int
+	timedOut bool
+	finished bool
+	bypass   bool // 流式输出，不再缓冲
+
+	timeoutStatus int
+	timeoutBody   []byte
+}
+
+func (w *timeoutResponseWriter) Header() http.Header {
+	return w.header
+}
+
+func (w *timeoutResponseWriter) WriteHeader(statusCode int) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if w.timedOut {
+		return
+	}
+	if w.bypass {
+		w.ResponseWriter.WriteHeader(statusCode)
+		return
+	}
+	w.status = statusCode
+}
+
+func (w *timeoutResponseWriter) WriteHeaderNow() {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if w.bypass && !w.timedOut {
+		w.ResponseWriter.WriteHeaderNow()
+	}
+}
+
+func (w *timeoutResponseWriter) Write(data []byte) (int, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if w.timedOut {
+		return 0, http.ErrHandlerTimeout
+	}
+	if w.bypass {
+		return w.ResponseWriter.Write(data)
+	}
+	if w.status == 0 {
+		w.status = http.StatusOK
+	}
+	return w.buffer.Write(data)
+}
+
+func (w *timeoutResponseWriter) WriteString(s string) (int, error) {
+	return w.Write([]byte(s))
+}
+
+func (w *timeoutResponseWriter) Status() int {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if w.bypass || w.timedOut {
+		return w.ResponseWriter.Status()
+	}
+	if w.status == 0 {
+		return http.StatusOK
+	}
+	return w.status
+}
+
+func (w *timeoutResponseWriter) Size() int {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if w.bypass || w.timedOut {
+		return w.ResponseWriter.Size()
+	}
+	if w.status == 0 && w.buffer.Len() == 0 {
+		return -1
+	}
+	return w.buffer.Len()
+}
+
+func (w *timeoutResponseWriter) Written() bool {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if w.bypass || w.timedOut {
+		return w.ResponseWriter.Written()
+	}
+	return w.status != 0 || w.buffer.Len() > 0
+}
+
+// Flush 流式输出（SSE 等）已输出的内容无法撤回，超时后只取消 Request.Context()
+func (w *timeoutResponseWriter) Flush() {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if w.timedOut {
+		return
+	}
+	if !w.bypass {
+		w.bypass = true
+		w.writeBuffered()
+	}
+	w.ResponseWriter.Flush()
+}
+
+// timeout 输出超时响应，已开始流式输出或处理函数已返回时不输出
+func (w *timeoutResponseWriter) timeout() {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if w.bypass || w.finished {
+		return
+	}
+	w.writeTimeout()
+}
+
+func (w *timeoutResponseWriter) writeTimeout() {
+	w.timedOut = true
+
+	header := w.ResponseWriter.Header()
+	header.Set("Content-Type", "application/json; charset=utf-8")
+	// 设置 Content-Length，客户端无需等待处理函数返回即可读取完整响应
+	header.Set("Content-Length", strconv.Itoa(len(w.timeoutBody)))
+	w.ResponseWriter.WriteHeader(w.timeoutStatus)
+	w.ResponseWriter.Write(w.timeoutBody)
+	w.ResponseWriter.Flush()
+}
+
+// finish 处理函数返回后输出缓冲的响应，返回是否已超时
+// 处理函数可能在监视 goroutine 输出超时响应之前返回，此时以 Context 是否超时为准
+func (w *timeoutResponseWriter) finish(reqCtx context.Context) bool {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	w.finished = true
+	if !w.timedOut && !w.bypass && errors.Is(reqCtx.Err(), context.DeadlineExceeded) {
+		w.writeTimeout()
+	}
+	if w.timedOut {
+		return true
+	}
+	if !w.bypass {
+		w.writeBuffered()
+	}
+	return false
+}
+
+func (w *timeoutResponseWriter) writeBuffered() {
+	header := w.ResponseWriter.Header()
+	for key := range header {
+		delete(header, key)
+	}
+	for key, values := range w.header {
+		header[key] = values
+	}
+
+	if w.status != 0 {
+		w.ResponseWriter.WriteHeader(w.status)
+	}
+	if w.buffer.Len() > 0 {
+		w.ResponseWriter.Write(w.buffer.Bytes())
+	}
+}
@@ -0,0 +1,307 @@
+package goohttp
+
+import (
+	"crypto/sha256"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"os"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// 客户端证书校验方式
+const (
+	ClientAuthNone          = "none"            // 不请求客户端证书
+	ClientAuthRequest       = "request"         // 请求客户端证书但不校验，Context.ClientCertificate 不返回未校验的证书
+	ClientAuthVerifyIfGiven = "verify_if_given" // 客户端提供证书时校验，未提供也允许连接
+	ClientAuthRequire       = "require"         // 必须提供证书并通过 ClientCAFile 校验（mTLS）
+)
+
+var (
+	ErrInvalidCertificate = errors.New("证书无效")
+)
+
+var (
+	DefaultTLSConfig = &TLSConfig{
+		MinVersion:     "1.2",
+		ReloadInterval: 10 * time.Second,
+	}
+)
+
+type TLSConfig struct {
+	CertFile       string                               `yaml:"cert_file" json:"cert_file"`             // 服务端证书文件（PEM，可包含中间证书）
+	KeyFile        string                               `yaml:"key_file" json:"key_file"`               // 服务端私钥文件（PEM）
+	ClientCAFile   string                               `yaml:"client_ca_file" json:"client_ca_file"`   // 校验客户端证书的 CA 文件（PEM，可包含多个），设置后默认要求客户端证书
+	ClientAuth     string                               `yaml:"client_auth" json:"client_auth"`         // 客户端证书校验方式：none、request、verify_if_given、require（设置 ClientCAFile 时默认 require）
+	MinVersion     string                               `yaml:"min_version" json:"min_version"`         // 最低 TLS 版本：1.2（默认）、1.3
+	ReloadInterval time.Duration                        `yaml:"reload_interval" json:"reload_interval"` // 检查证书文件变更的间隔（默认 10 秒），小于 0 不检查
+	DisableHTTP2   bool                                 `yaml:"disable_http2" json:"disable_http2"`     // 禁用 HTTP/2，只使用 HTTP/1.1
+	VerifyClient   func(identity *ClientIdentity) error `yaml:"-" json:"-"`                             // 客户端证书通过 CA 校验后的额外校验（如只允许指定 CN），返回错误时握手失败
+	OnReload       func(err error)                      `yaml:"-" json:"-"`                             // 证书文件变更重新加载后的回调，加载失败时继续使用原证书
+}
+
+// ClientIdentity 通过校验的客户端证书身份
+type ClientIdentity struct {
+	CommonName     string
+	Organization   []string
+	DNSNames       []string
+	EmailAddresses []string
+	URIs           []string // 如 SPIFFE ID：spiffe://example.com/ns/default/sa/order
+	SerialNumber   string
+	Fingerprint    string // 证书 SHA-256 指纹（十六进制小写）
+	NotAfter       time.Time
+	Certificate    *x509.Certificate
+}
+
+func newClientIdentity(cert *x509.Certificate) *ClientIdentity {
+	sum := sha256.Sum256(cert.Raw)
+
+	identity := &ClientIdentity{
+		CommonName:     cert.Subject.CommonName,
+		Organization:   cert.Subject.Organization,
+		DNSNames:       cert.DNSNames,
+		EmailAddresses: cert.EmailAddresses,
+		SerialNumber:   cert.SerialNumber.String(),
+		Fingerprint:    hex.EncodeToString(sum[:]),
+		NotAfter:       cert.NotAfter,
+		Certificate:    cert,
+	}
+	for _, uri := range cert.URIs {
+		identity.URIs = append(identity.URIs, uri.String())
+	}
+	return identity
+}
+
+// ClientCertificate 获取通过校验的客户端证书，未使用 TLS、未提供证书或证书未经校验时返回 nil
+func (c *Context) ClientCertificate() *x509.Certificate {
+	if c.Request == nil || c.Request.TLS == nil {
+		return nil
+	}
+	chains := c.Request.TLS.VerifiedChains
+	if len(chains) == 0 || len(chains[0]) == 0 {
+		return nil
+	}
+	return chains[0][0]
+}
+
+// ClientIdentity 获取 mTLS 客户端身份，没有通过校验的客户端证书时返回 nil
+func (c *Context) ClientIdentity() *ClientIdentity {
+	cert := c.ClientCertificate()
+	if cert == nil {
+		return nil
+	}
+	return newClientIdentity(cert)
+}
+
+// CertReloader 证书加载器
+// 定期检查证书、私钥和客户端 CA 文件，文件变更后重新加载，新连接使用新证书，无需重启服务
+type CertReloader struct {
+	config   *TLSConfig
+	current  atomic.Pointer[tls.Config]
+	mu       sync.Mutex
+	stamps   map[string]fileStamp
+	stopCh   chan struct{}
+	stopOnce sync.Once
+	wg       sync.WaitGroup
+}
+
+type fileStamp struct {
+	modTime time.Time
+	size    int64
+}
+
+// NewCertReloader 加载证书，ReloadInterval 大于 0 时启动文件变更检查
+func NewCertReloader(config *TLSConfig) (*CertReloader, error) {
+	if config == nil {
+		config = DefaultTLSConfig
+	}
+
+	c := *config
+	if c.MinVersion == "" {
+		c.MinVersion = DefaultTLSConfig.MinVersion
+	}
+	if c.ReloadInterval == 0 {
+		c.ReloadInterval = DefaultTLSConfig.ReloadInterval
+	}
+	if c.ClientAuth == "" {
+		c.ClientAuth = ClientAuthNone
+		if c.ClientCAFile != "" {
+			c.ClientAuth = ClientAuthRequire
+		}
+	}
+
+	r := &CertReloader{
+		config: &c,
+		stopCh: make(chan struct{}),
+	}
+	if err := r.Reload(); err != nil {
+		return nil, err
+	}
+
+	if c.ReloadInterval > 0 {
+		r.wg.Add(1)
+		go r.watch(c.ReloadInterval)
+	}
+
+	return r, nil
+}
+
+// TLSConfig 用于 http.Server 的 TLS 配置，每次握手使用当前加载的证书
+func (r *CertReloader) TLSConfig() *tls.Config {
+	return &tls.Config{
+		GetConfigForClient: func(*tls.ClientHelloInfo) (*tls.Config, error) {
+			return r.current.Load(), nil
+		},
+		GetCertificate: func(*tls.ClientHelloInfo) (*tls.Certificate, error) {
+			return &r.current.Load().Certificates[0], nil
+		},
+	}
+}
+
+// Reload 重新加载证书，失败时继续使用原证书
+func (r *CertReloader) Reload() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	stamps := r.stat()
+
+	config, err := r.load()
+	if err != nil {
+		return err
+	}
+
+	r.current.Store(config)
+	r.stamps = stamps
+	return nil
+}
+
+// Stop 停止文件变更检查
+func (r *CertReloader) Stop() {
+	r.stopOnce.Do(func() {
+		close(r.stopCh)
+	})
+	r.wg.Wait()
+}
+
+func (r *CertReloader) load() (*tls.Config, error) {
+	cert, err := tls.LoadX509KeyPair(r.config.CertFile, r.config.KeyFile)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidCertificate, err)
+	}
+
+	config := &tls.Config{
+		Certificates: []tls.Certificate{cert},
+		NextProtos:   []string{"h2", "http/1.1"},
+		MinVersion:   tls.VersionTLS12,
+	}
+	if r.config.DisableHTTP2 {
+		config.NextProtos = []string{"http/1.1"}
+	}
+
+	switch r.config.MinVersion {
+	case "1.2":
+	case "1.3":
+		config.MinVersion = tls.VersionTLS13
+	default:
+		return nil, fmt.Errorf("%w: unsupported tls version %s", ErrInvalidCertificate, r.config.MinVersion)
+	}
+
+	switch r.config.ClientAuth {
+	case ClientAuthNone:
+		config.ClientAuth = tls.NoClientCert
+	case ClientAuthRequest:
+		config.ClientAuth = tls.RequestClientCert
+	case ClientAuthVerifyIfGiven:
+		config.ClientAuth = tls.VerifyClientCertIfGiven
+	case ClientAuthRequire:
+		config.ClientAuth = tls.RequireAndVerifyClientCert
+	default:
+		return nil, fmt.Errorf("%w: unsupported client auth %s", ErrInvalidCertificate, r.config.ClientAuth)
+	}
+
+	if r.config.ClientCAFile != "" {
+		data, err := os.ReadFile(r.config.ClientCAFile)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrInvalidCertificate, err)
+		}
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(data) {
+			return nil, fmt.Errorf("%w: no certificate in %s", ErrInvalidCertificate, r.config.ClientCAFile)
+		}
+		config.ClientCAs = pool
+	} else if config.ClientAuth == tls.VerifyClientCertIfGiven || config.ClientAuth == tls.RequireAndVerifyClientCert {
+		return nil, fmt.Errorf("%w: client ca file is required for client auth %s", ErrInvalidCertificate, r.config.ClientAuth)
+	}
+
+	if verify := r.config.VerifyClient; verify != nil {
+		config.VerifyConnection = func(state tls.ConnectionState) error {
+			if len(state.VerifiedChains) == 0 || len(state.VerifiedChains[0]) == 0 {
+				return nil
+			}
+			return verify(newClientIdentity(state.VerifiedChains[0][0]))
+		}
+	}
+
+	return config, nil
+}
+
+func (r *CertReloader) files() []string {
+	files := []string{r.config.CertFile, r.config.KeyFile}
+	if r.config.ClientCAFile != "" {
+		files = append(files, r.config.ClientCAFile)
+	}
+	return files
+}
+
+func (r *CertReloader) stat() map[string]fileStamp {
+	stamps := make(map[string]fileStamp)
+	for _, file := range r.files() {
+		if info, err := os.Stat(file); err == nil {
+			stamps[file] = fileStamp{modTime: info.ModTime(), size: info.Size()}
+		}
+	}
+	return stamps
+}
+
+func (r *CertReloader) changed() bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	stamps := r.stat()
+	if len(stamps) != len(r.stamps) {
+		return true
+	}
+	for file, stamp := range stamps {
+		if r.stamps[file] != stamp {
+			return true
+		}
+	}
+	return false
+}
+
+func (r *CertReloader) watch(interval time.Duration) {
+	defer r.wg.Done()
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			// 证书和私钥可能先后写入，加载失败时保留原文件状态，下次检查时重试
+			if !r.changed() {
+				continue
+			}
+			err := r.Reload()
+			if r.config.OnReload != nil {
+				r.config.OnReload(err)
+			}
+		case <-r.stopCh:
+			return
+		}
+	}
+}
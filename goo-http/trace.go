@@ -10,14 +10,24 @@ const (
 )
 
 func TraceMiddleware(traceIdHeader string) gin.HandlerFunc {
+	return traceMiddleware(traceIdHeader, nil)
+}
+
+// traceMiddleware generator 为空时使用 UUID
+func traceMiddleware(traceIdHeader string, generator func() string) gin.HandlerFunc {
 	if traceIdHeader == "" {
 		traceIdHeader = DefaultTraceIdHeader
 	}
+	if generator == nil {
+		generator = func() string {
+			return uuid.New().String()
+		}
+	}
 
 	return func(c *gin.Context) {
 		traceId := c.GetHeader(traceIdHeader)
 		if traceId == "" {
-			traceId = uuid.New().String()
+			traceId = generator()
 		}
 
 		ctx := &Context{Context: c}
@@ -0,0 +1,624 @@
+package goohttp
+
+import (
+	"context"
+	"crypto/md5"
+	"crypto/sha1"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"hash"
+	"io"
+	"mime"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// tus 协议
+const (
+	TusResumable  = "1.0.0"
+	TusExtensions = "creation,creation-with-upload,creation-defer-length,termination,checksum,expiration"
+	TusChecksums  = "sha1,md5,sha256,sm3"
+
+	tusContentType = "application/offset+octet-stream"
+)
+
+// 上传错误码
+const (
+	UploadCodeInvalidRequest   = 4007 // 上传请求无效（缺少或错误的 Upload-Length、Upload-Offset 等）
+	UploadCodeCompleted        = 4030 // 上传已完成，不能继续写入
+	UploadCodeNotFound         = 4040 // 上传不存在
+	UploadCodeOffsetMismatch   = 4091 // 上传偏移量不一致
+	UploadCodeExpired          = 4100 // 上传已过期
+	UploadCodeVersion          = 4120 // 不支持的 tus 版本
+	UploadCodeTooLarge         = 4130 // 超出文件大小限制
+	UploadCodeContentType      = 4150 // Content-Type 不是 application/offset+octet-stream
+	UploadCodeLocked           = 4230 // 上传正在写入
+	UploadCodeChecksumMismatch = 4600 // 校验和不一致
+	UploadCodeStoreFailure     = 5004 // 上传存储失败
+)
+
+// tus 协议定义的校验和不一致状态码
+const statusChecksumMismatch = 460
+
+var (
+	DefaultUploadConfig = &UploadConfig{
+		Expiration: 24 * time.Hour,
+	}
+)
+
+type UploadConfig struct {
+	Store      UploadStore                                // 上传存储（默认本地目录 {临时目录}/goohttp-uploads）
+	MaxSize    int64                                      // 最大文件大小，0 表示不限制
+	Expiration time.Duration                              // 未完成上传的过期时间（默认 24 小时），每次写入后顺延，小于 0 表示不过期
+	Location   func(ctx *Context, id string) string       // 上传地址（默认创建请求的路径 + "/" + id），网关改写路径时使用
+	PreCreate  func(ctx *Context, info *UploadInfo) error // 创建前回调（如校验权限、文件类型），返回错误时拒绝创建
+	OnComplete func(ctx *Context, info *UploadInfo) error // 上传完成回调（如移动文件、写入数据库），在最后一次 PATCH 请求中调用，每个上传只调用一次
+}
+
+// Uploader 断点续传上传（tus 1.0）
+// 客户端先 POST 创建上传，再通过 PATCH 分段写入，中断后 HEAD 查询已上传的偏移量并从该处继续
+type Uploader struct {
+	config  *UploadConfig
+	store   UploadStore
+	mu      sync.Mutex
+	writing map[string]bool // 正在写入的上传ID
+}
+
+// UploadRouter 注册上传路由，*Server 和 *RouterGroup 均已实现
+type UploadRouter interface {
+	Post(path string, handlers ...HandlerFunc) *Route
+	Head(path string, handlers ...HandlerFunc) *Route
+	Patch(path string, handlers ...HandlerFunc) *Route
+	Delete(path string, handlers ...HandlerFunc) *Route
+	Options(path string, handlers ...HandlerFunc) *Route
+}
+
+func NewUploader(config *UploadConfig) (*Uploader, error) {
+	if config == nil {
+		config = DefaultUploadConfig
+	}
+
+	c := *config
+	if c.Expiration == 0 {
+		c.Expiration = DefaultUploadConfig.Expiration
+	}
+	if c.Store == nil {
+		store, err := NewFileUploadStore(filepath.Join(os.TempDir(), "goohttp-uploads"))
+		if err != nil {
+			return nil, err
+		}
+		c.Store = store
+	}
+
+	return &Uploader{
+		config:  &c,
+		store:   c.Store,
+		writing: make(map[string]bool),
+	}, nil
+}
+
+// Store 上传存储
+func (u *Uploader) Store() UploadStore {
+	return u.store
+}
+
+// Register 注册上传路由
+//
+//	uploader.Register(server, "/files")
+//
+// 注册 POST /files（创建）、HEAD /files/:id（查询）、PATCH /files/:id（写入）、DELETE /files/:id（终止）和 OPTIONS
+func (u *Uploader) Register(router UploadRouter, path string) {
+	path = strings.TrimSuffix(path, "/")
+
+	router.Options(path, u.options)
+	router.Post(path, u.create)
+	router.Options(path+"/:id", u.options)
+	router.Head(path+"/:id", u.head)
+	router.Patch(path+"/:id", u.patch)
+	router.Delete(path+"/:id", u.terminate)
+	// 不支持 PATCH、DELETE 的客户端通过 X-HTTP-Method-Override 请求头发送
+	router.Post(path+"/:id", u.override)
+}
+
+// Cleanup 删除已过期的上传，返回删除的数量，可通过 goo-cron 定期执行
+func (u *Uploader) Cleanup(ctx context.Context) (int, error) {
+	ids, err := u.store.Expired(ctx, time.Now())
+	if err != nil {
+		return 0, err
+	}
+
+	count := 0
+	for _, id := range ids {
+		if !u.lock(id) {
+			continue
+		}
+		err := u.store.Delete(ctx, id)
+		u.unlock(id)
+
+		if err != nil && !errors.Is(err, ErrUploadNotFound) {
+			return count, err
+		}
+		count++
+	}
+	return count, nil
+}
+
+func (u *Uploader) lock(id string) bool {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+
+	if u.writing[id] {
+		return false
+	}
+	u.writing[id] = true
+	return true
+}
+
+func (u *Uploader) unlock(id string) {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	delete(u.writing, id)
+}
+
+func (u *Uploader) options(ctx *Context) {
+	header := ctx.Writer.Header()
+	header.Set("Tus-Resumable", TusResumable)
+	header.Set("Tus-Version", TusResumable)
+	header.Set("Tus-Extension", TusExtensions)
+	header.Set("Tus-Checksum-Algorithm", TusChecksums)
+	if u.config.MaxSize > 0 {
+		header.Set("Tus-Max-Size", strconv.FormatInt(u.config.MaxSize, 10))
+	}
+	ctx.Status(http.StatusNoContent)
+}
+
+// checkVersion 校验 Tus-Resumable 请求头
+func (u *Uploader) checkVersion(ctx *Context) bool {
+	ctx.Header("Tus-Resumable", TusResumable)
+	if ctx.GetHeader("Tus-Resumable") != TusResumable {
+		ctx.Header("Tus-Version", TusResumable)
+		ctx.Abort(http.StatusPreconditionFailed, UploadCodeVersion, "不支持的 tus 版本")
+		return false
+	}
+	return true
+}
+
+func (u *Uploader) create(ctx *Context) {
+	if !u.checkVersion(ctx) {
+		return
+	}
+
+	info := &UploadInfo{
+		Id:        strings.ReplaceAll(uuid.New().String(), "-", ""),
+		CreatedAt: time.Now(),
+	}
+
+	lengthHeader, deferHeader := ctx.GetHeader("Upload-Length"), ctx.GetHeader("Upload-Defer-Length")
+	switch {
+	case lengthHeader != "" && deferHeader == "":
+		size, err := strconv.ParseInt(lengthHeader, 10, 64)
+		if err != nil || size < 0 {
+			ctx.Abort(http.StatusBadRequest, UploadCodeInvalidRequest, "无效的 Upload-Length")
+			return
+		}
+		if u.config.MaxSize > 0 && size > u.config.MaxSize {
+			ctx.Abort(http.StatusRequestEntityTooLarge, UploadCodeTooLarge, "超出文件大小限制")
+			return
+		}
+		info.Size = size
+	case lengthHeader == "" && deferHeader == "1":
+		info.SizeDeferred = true
+	default:
+		ctx.Abort(http.StatusBadRequest, UploadCodeInvalidRequest, "需要 Upload-Length 或 Upload-Defer-Length")
+		return
+	}
+
+	metadata, err := parseUploadMetadata(ctx.GetHeader("Upload-Metadata"))
+	if err != nil {
+		ctx.Abort(http.StatusBadRequest, UploadCodeInvalidRequest, "无效的 Upload-Metadata")
+		return
+	}
+	info.Metadata = metadata
+
+	if u.config.Expiration > 0 {
+		info.ExpiresAt = info.CreatedAt.Add(u.config.Expiration)
+	}
+
+	if u.config.PreCreate != nil {
+		if err := u.config.PreCreate(ctx, info); err != nil {
+			ctx.Fail(err)
+			return
+		}
+	}
+
+	storeCtx := context.WithoutCancel(ctx.Request.Context())
+	if err := u.store.Create(storeCtx, info); err != nil {
+		ctx.Context.Error(err)
+		ctx.Abort(http.StatusInternalServerError, UploadCodeStoreFailure, "创建上传失败")
+		return
+	}
+
+	location := ctx.Request.URL.Path + "/" + info.Id
+	if u.config.Location != nil {
+		location = u.config.Location(ctx, info.Id)
+	}
+	ctx.Header("Location", location)
+
+	// creation-with-upload：创建请求中携带第一段数据
+	if isTusContent(ctx) && ctx.Request.ContentLength != 0 {
+		u.lock(info.Id)
+		defer u.unlock(info.Id)
+
+		if !u.write(ctx, info) {
+			return
+		}
+		ctx.Header("Upload-Offset", strconv.FormatInt(info.Offset, 10))
+	} else if !info.SizeDeferred && info.Size == 0 {
+		// 空文件创建后即完成
+		if !u.complete(ctx, info) {
+			return
+		}
+	}
+
+	setUploadExpires(ctx, info)
+	ctx.Status(http.StatusCreated)
+}
+
+// load 读取 URL 中的上传，不存在或过期时返回错误响应
+func (u *Uploader) load(ctx *Context) (*UploadInfo, bool) {
+	id := ctx.Param("id")
+	if !validUploadId(id) {
+		ctx.Abort(http.StatusNotFound, UploadCodeNotFound, ErrUploadNotFound.Error())
+		return nil, false
+	}
+
+	info, err := u.store.Get(ctx.Request.Context(), id)
+	if errors.Is(err, ErrUploadNotFound) {
+		ctx.Abort(http.StatusNotFound, UploadCodeNotFound, ErrUploadNotFound.Error())
+		return nil, false
+	}
+	if err != nil {
+		ctx.Context.Error(err)
+		ctx.Abort(http.StatusInternalServerError, UploadCodeStoreFailure, "读取上传信息失败")
+		return nil, false
+	}
+
+	if !info.Completed && !info.ExpiresAt.IsZero() && info.ExpiresAt.Before(time.Now()) {
+		ctx.Abort(http.StatusGone, UploadCodeExpired, "上传已过期")
+		return nil, false
+	}
+
+	return info, true
+}
+
+func (u *Uploader) head(ctx *Context) {
+	if !u.checkVersion(ctx) {
+		return
+	}
+
+	info, ok := u.load(ctx)
+	if !ok {
+		return
+	}
+
+	ctx.Header("Cache-Control", "no-store")
+	ctx.Header("Upload-Offset", strconv.FormatInt(info.Offset, 10))
+	if info.SizeDeferred {
+		ctx.Header("Upload-Defer-Length", "1")
+	} else {
+		ctx.Header("Upload-Length", strconv.FormatInt(info.Size, 10))
+	}
+	if len(info.Metadata) > 0 {
+		ctx.Header("Upload-Metadata", encodeUploadMetadata(info.Metadata))
+	}
+	setUploadExpires(ctx, info)
+
+	ctx.Status(http.StatusOK)
+}
+
+func (u *Uploader) patch(ctx *Context) {
+	if !u.checkVersion(ctx) {
+		return
+	}
+
+	if !isTusContent(ctx) {
+		ctx.Abort(http.StatusUnsupportedMediaType, UploadCodeContentType, "Content-Type 必须为 "+tusContentType)
+		return
+	}
+
+	offset, err := strconv.ParseInt(ctx.GetHeader("Upload-Offset"), 10, 64)
+	if err != nil || offset < 0 {
+		ctx.Abort(http.StatusBadRequest, UploadCodeInvalidRequest, "无效的 Upload-Offset")
+		return
+	}
+
+	id := ctx.Param("id")
+	if !u.lock(id) {
+		ctx.Abort(http.StatusLocked, UploadCodeLocked, "上传正在写入，请稍后重试")
+		return
+	}
+	defer u.unlock(id)
+
+	info, ok := u.load(ctx)
+	if !ok {
+		return
+	}
+
+	// 已完成的上传不再写入，也不重复调用 Finish 和 OnComplete；空请求按幂等重试处理
+	if info.Completed {
+		if ctx.Request.ContentLength != 0 {
+			ctx.Abort(http.StatusForbidden, UploadCodeCompleted, "上传已完成")
+			return
+		}
+		ctx.Header("Upload-Offset", strconv.FormatInt(info.Offset, 10))
+		ctx.Status(http.StatusNoContent)
+		return
+	}
+
+	if offset != info.Offset {
+		ctx.Header("Upload-Offset", strconv.FormatInt(info.Offset, 10))
+		ctx.Abort(http.StatusConflict, UploadCodeOffsetMismatch, ErrUploadOffsetMismatch.Error())
+		return
+	}
+
+	// creation-defer-length：在之后的 PATCH 请求中设置文件大小
+	if lengthHeader := ctx.GetHeader("Upload-Length"); lengthHeader != "" && info.SizeDeferred {
+		size, err := strconv.ParseInt(lengthHeader, 10, 64)
+		if err != nil || size < info.Offset {
+			ctx.Abort(http.StatusBadRequest, UploadCodeInvalidRequest, "无效的 Upload-Length")
+			return
+		}
+		if u.config.MaxSize > 0 && size > u.config.MaxSize {
+			ctx.Abort(http.StatusRequestEntityTooLarge, UploadCodeTooLarge, "超出文件大小限制")
+			return
+		}
+		info.Size = size
+		info.SizeDeferred = false
+	}
+
+	if !u.write(ctx, info) {
+		return
+	}
+
+	ctx.Header("Upload-Offset", strconv.FormatInt(info.Offset, 10))
+	setUploadExpires(ctx, info)
+	ctx.Status(http.StatusNoContent)
+}
+
+// write 写入请求体，完成时调用 Finish 和 OnComplete；失败时已输出错误响应
+func (u *Uploader) write(ctx *Context, info *UploadInfo) bool {
+	// 客户端断开时已接收的数据仍需保存
+	storeCtx := context.WithoutCancel(ctx.Request.Context())
+
+	limit := int64(-1)
+	if !info.SizeDeferred {
+		limit = info.Size - info.Offset
+	} else if u.config.MaxSize > 0 {
+		limit = u.config.MaxSize - info.Offset
+	}
+	if limit >= 0 && ctx.Request.ContentLength > limit {
+		ctx.Abort(http.StatusRequestEntityTooLarge, UploadCodeTooLarge, "超出文件大小限制")
+		return false
+	}
+
+	var body io.Reader = ctx.Request.Body
+	if limit >= 0 {
+		body = io.LimitReader(body, limit)
+	}
+
+	// checksum：校验通过后才写入，先缓存到临时文件
+	if checksum := ctx.GetHeader("Upload-Checksum"); checksum != "" {
+		f, ok := verifyUploadChecksum(ctx, checksum, body)
+		if !ok {
+			return false
+		}
+		defer func() {
+			f.Close()
+			os.Remove(f.Name())
+		}()
+		body = f
+	}
+
+	n, err := u.store.Write(storeCtx, info.Id, info.Offset, body)
+	info.Offset += n
+	if err != nil {
+		ctx.Context.Error(err)
+		if errors.Is(err, ErrUploadOffsetMismatch) {
+			ctx.Abort(http.StatusConflict, UploadCodeOffsetMismatch, ErrUploadOffsetMismatch.Error())
+		} else {
+			ctx.Abort(http.StatusInternalServerError, UploadCodeStoreFailure, "写入上传数据失败")
+		}
+		return false
+	}
+
+	if u.config.Expiration > 0 {
+		info.ExpiresAt = time.Now().Add(u.config.Expiration)
+	}
+	if err := u.store.Update(storeCtx, info); err != nil {
+		ctx.Context.Error(err)
+		ctx.Abort(http.StatusInternalServerError, UploadCodeStoreFailure, "保存上传信息失败")
+		return false
+	}
+
+	if info.SizeDeferred || info.Offset < info.Size {
+		return true
+	}
+	return u.complete(ctx, info)
+}
+
+// complete 合并上传数据并调用 OnComplete
+func (u *Uploader) complete(ctx *Context, info *UploadInfo) bool {
+	finished, err := u.store.Finish(context.WithoutCancel(ctx.Request.Context()), info.Id)
+	if err != nil {
+		ctx.Context.Error(err)
+		ctx.Abort(http.StatusInternalServerError, UploadCodeStoreFailure, "合并上传数据失败")
+		return false
+	}
+	*info = *finished
+
+	if u.config.OnComplete != nil {
+		if err := u.config.OnComplete(ctx, info); err != nil {
+			ctx.Fail(err)
+			return false
+		}
+	}
+
+	return true
+}
+
+func (u *Uploader) terminate(ctx *Context) {
+	if !u.checkVersion(ctx) {
+		return
+	}
+
+	id := ctx.Param("id")
+	if !u.lock(id) {
+		ctx.Abort(http.StatusLocked, UploadCodeLocked, "上传正在写入，请稍后重试")
+		return
+	}
+	defer u.unlock(id)
+
+	if !validUploadId(id) {
+		ctx.Abort(http.StatusNotFound, UploadCodeNotFound, ErrUploadNotFound.Error())
+		return
+	}
+
+	err := u.store.Delete(context.WithoutCancel(ctx.Request.Context()), id)
+	if errors.Is(err, ErrUploadNotFound) {
+		ctx.Abort(http.StatusNotFound, UploadCodeNotFound, ErrUploadNotFound.Error())
+		return
+	}
+	if err != nil {
+		ctx.Context.Error(err)
+		ctx.Abort(http.StatusInternalServerError, UploadCodeStoreFailure, "删除上传失败")
+		return
+	}
+
+	ctx.Status(http.StatusNoContent)
+}
+
+func (u *Uploader) override(ctx *Context) {
+	switch strings.ToUpper(ctx.GetHeader("X-HTTP-Method-Override")) {
+	case http.MethodPatch:
+		u.patch(ctx)
+	case http.MethodDelete:
+		u.terminate(ctx)
+	default:
+		ctx.Header("Allow", "HEAD, PATCH, DELETE, OPTIONS")
+		ctx.Abort(http.StatusMethodNotAllowed, UploadCodeInvalidRequest, "不支持的请求方法")
+	}
+}
+
+func isTusContent(ctx *Context) bool {
+	mediaType, _, _ := mime.ParseMediaType(ctx.GetHeader("Content-Type"))
+	return mediaType == tusContentType
+}
+
+func setUploadExpires(ctx *Context, info *UploadInfo) {
+	if !info.Completed && !info.ExpiresAt.IsZero() {
+		ctx.Header("Upload-Expires", info.ExpiresAt.UTC().Format(http.TimeFormat))
+	}
+}
+
+// verifyUploadChecksum 读取请求体到临时文件并校验 Upload-Checksum（"算法 Base64摘要"）
+func verifyUploadChecksum(ctx *Context, checksum string, body io.Reader) (*os.File, bool) {
+	algorithm, encoded, _ := strings.Cut(checksum, " ")
+
+	var h hash.Hash
+	switch algorithm {
+	case "sha1":
+		h = sha1.New()
+	case "md5":
+		h = md5.New()
+	case "sha256":
+		h = sha256.New()
+	case "sm3":
+		h = NewSM3()
+	default:
+		ctx.Abort(http.StatusBadRequest, UploadCodeInvalidRequest, "不支持的校验算法")
+		return nil, false
+	}
+
+	expected, err := base64.StdEncoding.DecodeString(encoded)
+	if err != nil {
+		ctx.Abort(http.StatusBadRequest, UploadCodeInvalidRequest, "无效的 Upload-Checksum")
+		return nil, false
+	}
+
+	f, err := os.CreateTemp("", "goohttp-chunk-*")
+	if err != nil {
+		ctx.Context.Error(err)
+		ctx.Abort(http.StatusInternalServerError, UploadCodeStoreFailure, "写入上传数据失败")
+		return nil, false
+	}
+
+	fail := func(status int, code int, message string) (*os.File, bool) {
+		f.Close()
+		os.Remove(f.Name())
+		ctx.Abort(status, code, message)
+		return nil, false
+	}
+
+	if _, err := io.Copy(io.MultiWriter(f, h), body); err != nil {
+		ctx.Context.Error(err)
+		return fail(http.StatusBadRequest, UploadCodeInvalidRequest, "读取上传数据失败")
+	}
+	if string(h.Sum(nil)) != string(expected) {
+		return fail(statusChecksumMismatch, UploadCodeChecksumMismatch, "校验和不一致")
+	}
+	if _, err := f.Seek(0, io.SeekStart); err != nil {
+		return fail(http.StatusInternalServerError, UploadCodeStoreFailure, "写入上传数据失败")
+	}
+
+	return f, true
+}
+
+// parseUploadMetadata 解析 Upload-Metadata："key base64(value),key2 base64(value2)"，值可以省略
+func parseUploadMetadata(header string) (map[string]string, error) {
+	metadata := make(map[string]string)
+	if header == "" {
+		return metadata, nil
+	}
+
+	for _, pair := range strings.Split(header, ",") {
+		key, encoded, _ := strings.Cut(strings.TrimSpace(pair), " ")
+		if key == "" || strings.ContainsAny(key, " \t") {
+			return nil, errors.New("invalid metadata key")
+		}
+
+		value, err := base64.StdEncoding.DecodeString(encoded)
+		if err != nil {
+			return nil, err
+		}
+		metadata[key] = string(value)
+	}
+
+	return metadata, nil
+}
+
+func encodeUploadMetadata(metadata map[string]string) string {
+	keys := make([]string, 0, len(metadata))
+	for key := range metadata {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	pairs := make([]string, 0, len(keys))
+	for _, key := range keys {
+		if metadata[key] == "" {
+			pairs = append(pairs, key)
+			continue
+		}
+		pairs = append(pairs, key+" "+base64.StdEncoding.EncodeToString([]byte(metadata[key])))
+	}
+	return strings.Join(pairs, ",")
+}
@@ -0,0 +1,516 @@
+package goohttp
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var (
+	ErrUploadNotFound       = errors.New("上传不存在")
+	ErrUploadOffsetMismatch = errors.New("上传偏移量不一致")
+)
+
+// UploadInfo 上传信息
+type UploadInfo struct {
+	Id           string            `json:"id"`            // 上传ID
+	Size         int64             `json:"size"`          // 文件大小，SizeDeferred 为 true 时未知
+	SizeDeferred bool              `json:"size_deferred"` // 创建时未指定文件大小（Upload-Defer-Length）
+	Offset       int64             `json:"offset"`        // 已上传的字节数
+	Metadata     map[string]string `json:"metadata"`      // 客户端元数据（Upload-Metadata，如 filename、filetype）
+	Storage      map[string]string `json:"storage"`       // 存储位置（FileUploadStore：path；MultipartUploadStore：key）
+	Completed    bool              `json:"completed"`     // 是否已完成
+	CreatedAt    time.Time         `json:"created_at"`    // 创建时间
+	ExpiresAt    time.Time         `json:"expires_at"`    // 过期时间，为零值时不过期
+}
+
+// UploadStore 上传存储
+// 同一上传的操作由 Uploader 加锁串行执行，存储不需要处理同一上传的并发写入
+type UploadStore interface {
+	// Create 创建上传，可设置 info.Storage
+	Create(ctx context.Context, info *UploadInfo) error
+	// Get 获取上传信息，不存在时返回 ErrUploadNotFound
+	Get(ctx context.Context, id string) (*UploadInfo, error)
+	// Write 从 offset 处写入，返回写入的字节数并更新 Offset；读取中断时需要保留已写入的部分
+	Write(ctx context.Context, id string, offset int64, src io.Reader) (int64, error)
+	// Update 保存上传信息（延迟设置的文件大小、过期时间）
+	Update(ctx context.Context, info *UploadInfo) error
+	// Finish 所有内容写入后调用，合并为最终文件并标记为已完成
+	Finish(ctx context.Context, id string) (*UploadInfo, error)
+	// Delete 删除上传及其数据
+	Delete(ctx context.Context, id string) error
+	// Expired 返回已过期的上传ID
+	Expired(ctx context.Context, now time.Time) ([]string, error)
+}
+
+// uploadDir 本地目录中的上传信息（{id}.info）
+type uploadDir string
+
+func (d uploadDir) path(id string, ext string) string {
+	return filepath.Join(string(d), id+ext)
+}
+
+func (d uploadDir) load(id string) (*UploadInfo, error) {
+	if !validUploadId(id) {
+		return nil, ErrUploadNotFound
+	}
+
+	data, err := os.ReadFile(d.path(id, ".info"))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, ErrUploadNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	info := &UploadInfo{}
+	if err := json.Unmarshal(data, info); err != nil {
+		return nil, fmt.Errorf("failed to decode upload info: %w", err)
+	}
+	return info, nil
+}
+
+func (d uploadDir) save(info *UploadInfo) error {
+	return d.writeJSON(d.path(info.Id, ".info"), info)
+}
+
+// writeJSON 先写入临时文件再重命名，避免进程退出时留下不完整的文件
+func (d uploadDir) writeJSON(name string, v any) error {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+
+	tmp := name + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o600); err != nil {
+		return err
+	}
+	return os.Rename(tmp, name)
+}
+
+func (d uploadDir) expired(now time.Time) ([]string, error) {
+	entries, err := os.ReadDir(string(d))
+	if err != nil {
+		return nil, err
+	}
+
+	var ids []string
+	for _, entry := range entries {
+		id, ok := strings.CutSuffix(entry.Name(), ".info")
+		if !ok {
+			continue
+		}
+		info, err := d.load(id)
+		if err != nil {
+			continue
+		}
+		if !info.ExpiresAt.IsZero() && info.ExpiresAt.Before(now) {
+			ids = append(ids, id)
+		}
+	}
+	return ids, nil
+}
+
+func (d uploadDir) remove(names ...string) error {
+	for _, name := range names {
+		if err := os.Remove(name); err != nil && !errors.Is(err, os.ErrNotExist) {
+			return err
+		}
+	}
+	return nil
+}
+
+// validUploadId 上传ID只允许字母、数字、- 和 _，避免路径穿越
+func validUploadId(id string) bool {
+	if id == "" || len(id) > 128 {
+		return false
+	}
+	for _, c := range id {
+		if !(c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= '0' && c <= '9' || c == '-' || c == '_') {
+			return false
+		}
+	}
+	return true
+}
+
+// FileUploadStore 本地磁盘存储
+// 数据保存在 {dir}/{id}.bin，上传信息保存在 {dir}/{id}.info，完成后文件路径为 info.Storage["path"]
+type FileUploadStore struct {
+	dir uploadDir
+}
+
+func NewFileUploadStore(dir string) (*FileUploadStore, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, fmt.Errorf("failed to create upload dir: %w", err)
+	}
+
+	abs, err := filepath.Abs(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	return &FileUploadStore{
+		dir: uploadDir(abs),
+	}, nil
+}
+
+func (s *FileUploadStore) Create(ctx context.Context, info *UploadInfo) error {
+	if !validUploadId(info.Id) {
+		return ErrUploadNotFound
+	}
+
+	name := s.dir.path(info.Id, ".bin")
+	f, err := os.OpenFile(name, os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0o600)
+	if err != nil {
+		return fmt.Errorf("failed to create upload file: %w", err)
+	}
+	f.Close()
+
+	info.Storage = map[string]string{"path": name}
+	return s.dir.save(info)
+}
+
+func (s *FileUploadStore) Get(ctx context.Context, id string) (*UploadInfo, error) {
+	return s.dir.load(id)
+}
+
+func (s *FileUploadStore) Write(ctx context.Context, id string, offset int64, src io.Reader) (int64, error) {
+	info, err := s.dir.load(id)
+	if err != nil {
+		return 0, err
+	}
+	if info.Offset != offset {
+		return 0, ErrUploadOffsetMismatch
+	}
+
+	f, err := os.OpenFile(s.dir.path(id, ".bin"), os.O_WRONLY, 0o600)
+	if err != nil {
+		return 0, fmt.Errorf("failed to open upload file: %w", err)
+	}
+	defer f.Close()
+
+	// 丢弃上次中断时可能残留的未记录数据
+	if err := f.Truncate(offset); err != nil {
+		return 0, err
+	}
+	if _, err := f.Seek(offset, io.SeekStart); err != nil {
+		return 0, err
+	}
+
+	n, copyErr := io.Copy(f, src)
+
+	info.Offset += n
+	if err := s.dir.save(info); err != nil {
+		return n, err
+	}
+
+	return n, copyErr
+}
+
+func (s *FileUploadStore) Update(ctx context.Context, info *UploadInfo) error {
+	return s.dir.save(info)
+}
+
+func (s *FileUploadStore) Finish(ctx context.Context, id string) (*UploadInfo, error) {
+	info, err := s.dir.load(id)
+	if err != nil {
+		return nil, err
+	}
+
+	info.Completed = true
+	if err := s.dir.save(info); err != nil {
+		return nil, err
+	}
+	return info, nil
+}
+
+// Delete 删除上传，OnComplete 中已移走的文件会被忽略
+func (s *FileUploadStore) Delete(ctx context.Context, id string) error {
+	if _, err := s.dir.load(id); err != nil {
+		return err
+	}
+	return s.dir.remove(s.dir.path(id, ".bin"), s.dir.path(id, ".info"))
+}
+
+func (s *FileUploadStore) Expired(ctx context.Context, now time.Time) ([]string, error) {
+	return s.dir.expired(now)
+}
+
+// MultipartUploader 对象存储分片上传
+// goooss.NewMultipartUploader、goocos.NewMultipartUploader 提供了阿里云 OSS、腾讯云 COS 的实现
+type MultipartUploader interface {
+	// InitiateMultipart 初始化分片上传，返回分片上传ID
+	InitiateMultipart(ctx context.Context, key string) (string, error)
+	// UploadPart 上传分片，返回分片的 ETag
+	UploadPart(ctx context.Context, key string, uploadId string, partNumber int, r io.Reader, size int64) (string, error)
+	// CompleteMultipart 完成分片上传，etags 按分片号（从 1 开始）排列
+	CompleteMultipart(ctx context.Context, key string, uploadId string, etags []string) error
+	// AbortMultipart 取消分片上传
+	AbortMultipart(ctx context.Context, key string, uploadId string) error
+}
+
+var (
+	DefaultMultipartUploadConfig = &MultipartUploadConfig{
+		Dir:      filepath.Join(os.TempDir(), "goohttp-uploads"),
+		PartSize: 5 << 20,
+	}
+)
+
+type MultipartUploadConfig struct {
+	Dir      string                        // 本地目录，保存上传信息和未达到分片大小的数据（默认 {临时目录}/goohttp-uploads）
+	PartSize int64                         // 分片大小（默认 5MB），不能小于对象存储的最小分片（OSS 100KB，COS 1MB）
+	KeyFunc  func(info *UploadInfo) string // 对象名（默认 uploads/{id}{文件扩展名}）
+}
+
+// MultipartUploadStore 对象存储分片上传
+// 客户端上传的数据先写入本地，达到分片大小后上传为一个分片，所有数据上传后合并为最终对象，对象名为 info.Storage["key"]。
+// 上传信息保存在本地目录，多实例部署时需要共享目录或按上传ID路由到同一实例
+type MultipartUploadStore struct {
+	uploader MultipartUploader
+	config   *MultipartUploadConfig
+	dir      uploadDir
+}
+
+// multipartState 分片上传进度（{id}.parts）
+type multipartState struct {
+	UploadId string   `json:"upload_id"`
+	ETags    []string `json:"etags"`
+	Uploaded int64    `json:"uploaded"` // 已上传到对象存储的字节数
+}
+
+func NewMultipartUploadStore(uploader MultipartUploader, config *MultipartUploadConfig) (*MultipartUploadStore, error) {
+	if config == nil {
+		config = DefaultMultipartUploadConfig
+	}
+
+	c := *config
+	if c.Dir == "" {
+		c.Dir = DefaultMultipartUploadConfig.Dir
+	}
+	if c.PartSize <= 0 {
+		c.PartSize = DefaultMultipartUploadConfig.PartSize
+	}
+	if c.KeyFunc == nil {
+		c.KeyFunc = defaultUploadKey
+	}
+
+	if err := os.MkdirAll(c.Dir, 0o755); err != nil {
+		return nil, fmt.Errorf("failed to create upload dir: %w", err)
+	}
+
+	return &MultipartUploadStore{
+		uploader: uploader,
+		config:   &c,
+		dir:      uploadDir(c.Dir),
+	}, nil
+}
+
+func defaultUploadKey(info *UploadInfo) string {
+	ext := path.Ext(info.Metadata["filename"])
+	if len(ext) > 16 || !validUploadId(strings.TrimPrefix(ext, ".")) {
+		ext = ""
+	}
+	return "uploads/" + info.Id + strings.ToLower(ext)
+}
+
+func (s *MultipartUploadStore) Create(ctx context.Context, info *UploadInfo) error {
+	if !validUploadId(info.Id) {
+		return ErrUploadNotFound
+	}
+
+	info.Storage = map[string]string{"key": s.config.KeyFunc(info)}
+	if err := s.saveState(info.Id, &multipartState{}); err != nil {
+		return err
+	}
+	return s.dir.save(info)
+}
+
+func (s *MultipartUploadStore) Get(ctx context.Context, id string) (*UploadInfo, error) {
+	return s.dir.load(id)
+}
+
+// partPath 下一个分片的本地数据，按分片号命名，分片上传后删除
+func (s *MultipartUploadStore) partPath(id string, partNumber int) string {
+	return s.dir.path(id, ".part"+strconv.Itoa(partNumber))
+}
+
+func (s *MultipartUploadStore) loadState(id string) (*multipartState, error) {
+	data, err := os.ReadFile(s.dir.path(id, ".parts"))
+	if err != nil {
+		return nil, err
+	}
+
+	state := &multipartState{}
+	if err := json.Unmarshal(data, state); err != nil {
+		return nil, fmt.Errorf("failed to decode multipart state: %w", err)
+	}
+	return state, nil
+}
+
+func (s *MultipartUploadStore) saveState(id string, state *multipartState) error {
+	return s.dir.writeJSON(s.dir.path(id, ".parts"), state)
+}
+
+func (s *MultipartUploadStore) Write(ctx context.Context, id string, offset int64, src io.Reader) (int64, error) {
+	info, err := s.dir.load(id)
+	if err != nil {
+		return 0, err
+	}
+	if info.Offset != offset {
+		return 0, ErrUploadOffsetMismatch
+	}
+
+	state, err := s.loadState(id)
+	if err != nil {
+		return 0, err
+	}
+
+	name := s.partPath(id, len(state.ETags)+1)
+	f, err := os.OpenFile(name, os.O_CREATE|os.O_WRONLY, 0o600)
+	if err != nil {
+		return 0, fmt.Errorf("failed to open part file: %w", err)
+	}
+
+	// 本地数据从上一个分片结束处开始，丢弃上次中断时可能残留的未记录数据
+	staged := info.Offset - state.Uploaded
+	if err := f.Truncate(staged); err != nil {
+		f.Close()
+		return 0, err
+	}
+	if _, err := f.Seek(staged, io.SeekStart); err != nil {
+		f.Close()
+		return 0, err
+	}
+
+	n, copyErr := io.Copy(f, src)
+	f.Close()
+
+	info.Offset += n
+	if err := s.dir.save(info); err != nil {
+		return n, err
+	}
+	if copyErr != nil {
+		return n, copyErr
+	}
+
+	if staged+n >= s.config.PartSize {
+		if err := s.uploadPart(ctx, info, state, staged+n); err != nil {
+			return n, err
+		}
+	}
+
+	return n, nil
+}
+
+// uploadPart 上传本地数据为下一个分片
+// 先保存进度再删除本地数据，进程在两者之间退出时残留的文件不会再被使用
+func (s *MultipartUploadStore) uploadPart(ctx context.Context, info *UploadInfo, state *multipartState, size int64) error {
+	key := info.Storage["key"]
+
+	if state.UploadId == "" {
+		uploadId, err := s.uploader.InitiateMultipart(ctx, key)
+		if err != nil {
+			return fmt.Errorf("failed to initiate multipart upload: %w", err)
+		}
+		state.UploadId = uploadId
+		if err := s.saveState(info.Id, state); err != nil {
+			return err
+		}
+	}
+
+	partNumber := len(state.ETags) + 1
+	name := s.partPath(info.Id, partNumber)
+
+	f, err := os.Open(name)
+	if err != nil {
+		return err
+	}
+	etag, err := s.uploader.UploadPart(ctx, key, state.UploadId, partNumber, io.LimitReader(f, size), size)
+	f.Close()
+	if err != nil {
+		return fmt.Errorf("failed to upload part %d: %w", partNumber, err)
+	}
+
+	state.ETags = append(state.ETags, etag)
+	state.Uploaded += size
+	if err := s.saveState(info.Id, state); err != nil {
+		return err
+	}
+
+	return s.dir.remove(name)
+}
+
+func (s *MultipartUploadStore) Update(ctx context.Context, info *UploadInfo) error {
+	return s.dir.save(info)
+}
+
+func (s *MultipartUploadStore) Finish(ctx context.Context, id string) (*UploadInfo, error) {
+	info, err := s.dir.load(id)
+	if err != nil {
+		return nil, err
+	}
+	state, err := s.loadState(id)
+	if err != nil {
+		return nil, err
+	}
+
+	// 最后一个分片可以小于分片大小；空文件也需要上传一个分片
+	if staged := info.Offset - state.Uploaded; staged > 0 || len(state.ETags) == 0 {
+		if staged == 0 {
+			if err := os.WriteFile(s.partPath(id, 1), nil, 0o600); err != nil {
+				return nil, err
+			}
+		}
+		if err := s.uploadPart(ctx, info, state, staged); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := s.uploader.CompleteMultipart(ctx, info.Storage["key"], state.UploadId, state.ETags); err != nil {
+		return nil, fmt.Errorf("failed to complete multipart upload: %w", err)
+	}
+
+	info.Completed = true
+	if err := s.dir.save(info); err != nil {
+		return nil, err
+	}
+	if err := s.dir.remove(s.dir.path(id, ".parts")); err != nil {
+		return nil, err
+	}
+
+	return info, nil
+}
+
+// Delete 删除上传，未完成的上传同时取消分片上传；已完成的只删除本地信息，不删除对象
+func (s *MultipartUploadStore) Delete(ctx context.Context, id string) error {
+	info, err := s.dir.load(id)
+	if err != nil {
+		return err
+	}
+
+	state, err := s.loadState(id)
+	if err == nil {
+		if state.UploadId != "" && !info.Completed {
+			if err := s.uploader.AbortMultipart(ctx, info.Storage["key"], state.UploadId); err != nil {
+				return fmt.Errorf("failed to abort multipart upload: %w", err)
+			}
+		}
+		if err := s.dir.remove(s.partPath(id, len(state.ETags)+1)); err != nil {
+			return err
+		}
+	}
+
+	return s.dir.remove(s.dir.path(id, ".parts"), s.dir.path(id, ".info"))
+}
+
+func (s *MultipartUploadStore) Expired(ctx context.Context, now time.Time) ([]string, error) {
+	return s.dir.expired(now)
+}
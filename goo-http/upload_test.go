@@ -0,0 +1,93 @@
+package goohttp
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+)
+
+func newUploadTestServer(t *testing.T, config *UploadConfig) *Server {
+	t.Helper()
+	gin.SetMode(gin.TestMode)
+
+	store, err := NewFileUploadStore(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	config.Store = store
+
+	uploader, err := NewUploader(config)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	server := New(WithEnableLog(false))
+	uploader.Register(server, "/files")
+	return server
+}
+
+func uploadRequest(t *testing.T, server *Server, method, path string, headers map[string]string, body string) *httptest.ResponseRecorder {
+	t.Helper()
+
+	req := httptest.NewRequest(method, path, strings.NewReader(body))
+	req.Header.Set("Tus-Resumable", TusResumable)
+	for k, v := range headers {
+		req.Header.Set(k, v)
+	}
+	w := httptest.NewRecorder()
+	server.ServeHTTP(w, req)
+	return w
+}
+
+// 完成后的空 PATCH（客户端重试）返回 204，不重复调用 OnComplete；携带数据的 PATCH 返回 403
+func TestUploaderPatchAfterComplete(t *testing.T) {
+	completed := 0
+	server := newUploadTestServer(t, &UploadConfig{
+		OnComplete: func(ctx *Context, info *UploadInfo) error {
+			completed++
+			return nil
+		},
+	})
+
+	w := uploadRequest(t, server, http.MethodPost, "/files", map[string]string{"Upload-Length": "5"}, "")
+	if w.Code != http.StatusCreated {
+		t.Fatalf("create status = %d, body = %s", w.Code, w.Body)
+	}
+	location := w.Header().Get("Location")
+
+	patch := func(offset int, body string) *httptest.ResponseRecorder {
+		return uploadRequest(t, server, http.MethodPatch, location, map[string]string{
+			"Content-Type":  tusContentType,
+			"Upload-Offset": strconv.Itoa(offset),
+		}, body)
+	}
+
+	if w := patch(0, "hello"); w.Code != http.StatusNoContent {
+		t.Fatalf("patch status = %d, body = %s", w.Code, w.Body)
+	}
+	if completed != 1 {
+		t.Fatalf("OnComplete calls = %d, want 1", completed)
+	}
+
+	w = patch(5, "")
+	if w.Code != http.StatusNoContent {
+		t.Fatalf("empty patch status = %d, body = %s", w.Code, w.Body)
+	}
+	if got := w.Header().Get("Upload-Offset"); got != "5" {
+		t.Fatalf("Upload-Offset = %q, want 5", got)
+	}
+	if completed != 1 {
+		t.Fatalf("OnComplete calls after retry = %d, want 1", completed)
+	}
+
+	if w := patch(5, "x"); w.Code != http.StatusForbidden {
+		t.Fatalf("non-empty patch status = %d, want %d", w.Code, http.StatusForbidden)
+	}
+	if completed != 1 {
+		t.Fatalf("OnComplete calls after write = %d, want 1", completed)
+	}
+}
@@ -0,0 +1,87 @@
+package goohttp
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"sync"
+
+	"github.com/gin-gonic/gin/binding"
+	"github.com/go-playground/locales/zh"
+	ut "github.com/go-playground/universal-translator"
+	"github.com/go-playground/validator/v10"
+	zhtranslations "github.com/go-playground/validator/v10/translations/zh"
+)
+
+var (
+	validatorOnce  sync.Once
+	validatorTrans ut.Translator
+)
+
+// setupValidator 为 gin 的校验器注册中文翻译
+// 字段名优先取 label 标签，其次取 json/form/uri/header 标签
+func setupValidator() {
+	validatorOnce.Do(func() {
+		v, ok := binding.Validator.Engine().(*validator.Validate)
+		if !ok {
+			return
+		}
+
+		v.RegisterTagNameFunc(func(field reflect.StructField) string {
+			if label := field.Tag.Get("label"); label != "" {
+				return label
+			}
+			for _, tag := range []string{"json", "form", "uri", "header"} {
+				name := strings.SplitN(field.Tag.Get(tag), ",", 2)[0]
+				if name != "" && name != "-" {
+					return name
+				}
+			}
+			return field.Name
+		})
+
+		locale := zh.New()
+		trans, _ := ut.New(locale, locale).GetTranslator("zh")
+		if err := zhtranslations.RegisterDefaultTranslations(v, trans); err != nil {
+			return
+		}
+
+		validatorTrans = trans
+	})
+}
+
+// ValidationMessages 将校验错误翻译为中文，key 为字段名
+func ValidationMessages(err error) map[string]string {
+	var errs validator.ValidationErrors
+	if !errors.As(err, &errs) {
+		return nil
+	}
+
+	setupValidator()
+
+	messages := make(map[string]string, len(errs))
+	for _, fe := range errs {
+		if validatorTrans != nil {
+			messages[fe.Field()] = fe.Translate(validatorTrans)
+		} else {
+			messages[fe.Field()] = fe.Error()
+		}
+	}
+
+	return messages
+}
+
+// firstValidationMessage 按字段顺序返回第一条校验错误
+func firstValidationMessage(err error) string {
+	var errs validator.ValidationErrors
+	if !errors.As(err, &errs) || len(errs) == 0 {
+		return ""
+	}
+
+	setupValidator()
+
+	if validatorTrans != nil {
+		return errs[0].Translate(validatorTrans)
+	}
+	return errs[0].Error()
+}
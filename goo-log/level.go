@@ -1,5 +1,10 @@
 package goolog
 
+import (
+	"fmt"
+	"strings"
+)
+
 type Level int
 type brush func(string) string
 
@@ -44,3 +49,14 @@ func newBrush(color string) brush {
 func Color(level Level) brush {
 	return colors[level]
 }
+
+// ParseLevel 解析日志级别名称（不区分大小写），如 "debug"、"INFO"
+func ParseLevel(text string) (Level, error) {
+	text = strings.ToUpper(strings.TrimSpace(text))
+	for level, name := range LevelText {
+		if name == text {
+			return level, nil
+		}
+	}
+	return 0, fmt.Errorf("unknown log level: %s", text)
+}
@@ -47,6 +47,20 @@ func (l *Logger) SetLevel(level Level) {
 	l.level = level
 }
 
+// Level 获取日志级别
+func (l *Logger) Level() Level {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.level
+}
+
+// TraceLevel 获取追踪级别
+func (l *Logger) TraceLevel() Level {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.traceLevel
+}
+
 // SetTraceLevel 设置追踪级别
 func (l *Logger) SetTraceLevel(level Level) {
 	l.mu.Lock()
@@ -0,0 +1,92 @@
+package goometrics
+
+// Counter 计数器，只增不减
+type Counter struct {
+	value atomicFloat
+}
+
+// Inc 加 1
+func (c *Counter) Inc() {
+	c.value.Add(1)
+}
+
+// Add 增加指定值，负数会被忽略
+func (c *Counter) Add(delta float64) {
+	if delta < 0 {
+		return
+	}
+	c.value.Add(delta)
+}
+
+// Value 当前值
+func (c *Counter) Value() float64 {
+	return c.value.Load()
+}
+
+// CounterVec 带标签的计数器
+type CounterVec struct {
+	vec *vec[*Counter]
+}
+
+// NewCounterVec 创建带标签的计数器
+func NewCounterVec(opts Opts, labelNames ...string) (*CounterVec, error) {
+	desc, err := newDesc(opts, labelNames)
+	if err != nil {
+		return nil, err
+	}
+
+	return &CounterVec{
+		vec: newVec(desc, func() *Counter { return &Counter{} }),
+	}, nil
+}
+
+// MustNewCounterVec 创建带标签的计数器，配置错误时 panic
+func MustNewCounterVec(opts Opts, labelNames ...string) *CounterVec {
+	v, err := NewCounterVec(opts, labelNames...)
+	mustNot(err)
+	return v
+}
+
+// GetMetricWithLabelValues 按标签值获取计数器
+func (v *CounterVec) GetMetricWithLabelValues(values ...string) (*Counter, error) {
+	return v.vec.get(values)
+}
+
+// WithLabelValues 按标签值获取计数器，标签数量不一致时 panic
+func (v *CounterVec) WithLabelValues(values ...string) *Counter {
+	c, err := v.vec.get(values)
+	mustNot(err)
+	return c
+}
+
+// DeleteLabelValues 删除指定标签值的计数器
+func (v *CounterVec) DeleteLabelValues(values ...string) bool {
+	return v.vec.delete(values)
+}
+
+// Reset 清空所有计数器
+func (v *CounterVec) Reset() {
+	v.vec.reset()
+}
+
+func (v *CounterVec) Names() []string {
+	return []string{v.vec.desc.name}
+}
+
+func (v *CounterVec) Collect() []*MetricFamily {
+	family := &MetricFamily{
+		Name: v.vec.desc.name,
+		Help: v.vec.desc.help,
+		Type: CounterType,
+	}
+
+	v.vec.each(func(labels []Label, c *Counter) {
+		family.Samples = append(family.Samples, Sample{
+			Name:   family.Name,
+			Labels: labels,
+			Value:  c.Value(),
+		})
+	})
+
+	return []*MetricFamily{family}
+}
@@ -0,0 +1,11 @@
+package goometrics
+
+import "errors"
+
+var (
+	ErrInvalidName       = errors.New("指标名称不合法")
+	ErrInvalidLabelName  = errors.New("标签名称不合法")
+	ErrLabelCount        = errors.New("标签值数量与标签名称数量不一致")
+	ErrAlreadyRegistered = errors.New("指标已注册")
+	ErrInvalidBuckets    = errors.New("直方图分桶必须递增")
+)
@@ -0,0 +1,139 @@
+package goometrics
+
+// Gauge 仪表盘，可增可减
+type Gauge struct {
+	value atomicFloat
+}
+
+func (g *Gauge) Set(v float64) {
+	g.value.Set(v)
+}
+
+func (g *Gauge) Inc() {
+	g.value.Add(1)
+}
+
+func (g *Gauge) Dec() {
+	g.value.Add(-1)
+}
+
+func (g *Gauge) Add(delta float64) {
+	g.value.Add(delta)
+}
+
+func (g *Gauge) Sub(delta float64) {
+	g.value.Add(-delta)
+}
+
+// Value 当前值
+func (g *Gauge) Value() float64 {
+	return g.value.Load()
+}
+
+// GaugeVec 带标签的仪表盘
+type GaugeVec struct {
+	vec *vec[*Gauge]
+}
+
+// NewGaugeVec 创建带标签的仪表盘
+func NewGaugeVec(opts Opts, labelNames ...string) (*GaugeVec, error) {
+	desc, err := newDesc(opts, labelNames)
+	if err != nil {
+		return nil, err
+	}
+
+	return &GaugeVec{
+		vec: newVec(desc, func() *Gauge { return &Gauge{} }),
+	}, nil
+}
+
+// MustNewGaugeVec 创建带标签的仪表盘，配置错误时 panic
+func MustNewGaugeVec(opts Opts, labelNames ...string) *GaugeVec {
+	v, err := NewGaugeVec(opts, labelNames...)
+	mustNot(err)
+	return v
+}
+
+// GetMetricWithLabelValues 按标签值获取仪表盘
+func (v *GaugeVec) GetMetricWithLabelValues(values ...string) (*Gauge, error) {
+	return v.vec.get(values)
+}
+
+// WithLabelValues 按标签值获取仪表盘，标签数量不一致时 panic
+func (v *GaugeVec) WithLabelValues(values ...string) *Gauge {
+	g, err := v.vec.get(values)
+	mustNot(err)
+	return g
+}
+
+// DeleteLabelValues 删除指定标签值的仪表盘
+func (v *GaugeVec) DeleteLabelValues(values ...string) bool {
+	return v.vec.delete(values)
+}
+
+// Reset 清空所有仪表盘
+func (v *GaugeVec) Reset() {
+	v.vec.reset()
+}
+
+func (v *GaugeVec) Names() []string {
+	return []string{v.vec.desc.name}
+}
+
+func (v *GaugeVec) Collect() []*MetricFamily {
+	family := &MetricFamily{
+		Name: v.vec.desc.name,
+		Help: v.vec.desc.help,
+		Type: GaugeType,
+	}
+
+	v.vec.each(func(labels []Label, g *Gauge) {
+		family.Samples = append(family.Samples, Sample{
+			Name:   family.Name,
+			Labels: labels,
+			Value:  g.Value(),
+		})
+	})
+
+	return []*MetricFamily{family}
+}
+
+// GaugeFunc 采集时调用函数取值的仪表盘，适合连接池大小等已有的统计值
+type GaugeFunc struct {
+	desc *desc
+	fn   func() float64
+}
+
+// NewGaugeFunc 创建函数仪表盘
+func NewGaugeFunc(opts Opts, fn func() float64) (*GaugeFunc, error) {
+	desc, err := newDesc(opts, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	return &GaugeFunc{desc: desc, fn: fn}, nil
+}
+
+// MustNewGaugeFunc 创建函数仪表盘，配置错误时 panic
+func MustNewGaugeFunc(opts Opts, fn func() float64) *GaugeFunc {
+	g, err := NewGaugeFunc(opts, fn)
+	mustNot(err)
+	return g
+}
+
+func (g *GaugeFunc) Names() []string {
+	return []string{g.desc.name}
+}
+
+func (g *GaugeFunc) Collect() []*MetricFamily {
+	return []*MetricFamily{{
+		Name: g.desc.name,
+		Help: g.desc.help,
+		Type: GaugeType,
+		Samples: []Sample{{
+			Name:   g.desc.name,
+			Labels: g.desc.labels(nil),
+			Value:  g.fn(),
+		}},
+	}}
+}
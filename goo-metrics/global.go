@@ -0,0 +1,41 @@
+package goometrics
+
+import "net/http"
+
+var (
+	defaultRegistry = NewRegistry()
+)
+
+func init() {
+	defaultRegistry.MustRegister(NewRuntimeCollector())
+}
+
+// Default 获取默认注册表（已注册 Go 运行时指标）
+func Default() *Registry {
+	return defaultRegistry
+}
+
+// Register 向默认注册表注册收集器
+func Register(c Collector) error {
+	return defaultRegistry.Register(c)
+}
+
+// MustRegister 向默认注册表注册收集器，失败时 panic
+func MustRegister(cs ...Collector) {
+	defaultRegistry.MustRegister(cs...)
+}
+
+// Unregister 从默认注册表注销收集器
+func Unregister(c Collector) bool {
+	return defaultRegistry.Unregister(c)
+}
+
+// Gather 收集默认注册表的所有指标
+func Gather() []*MetricFamily {
+	return defaultRegistry.Gather()
+}
+
+// Handler 默认注册表的指标输出接口
+func Handler() http.Handler {
+	return defaultRegistry.Handler()
+}
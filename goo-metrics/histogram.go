@@ -0,0 +1,187 @@
+package goometrics
+
+import (
+	"math"
+	"sort"
+	"strconv"
+	"sync/atomic"
+)
+
+var (
+	// DefBuckets 默认分桶（秒），适合 HTTP 请求耗时
+	DefBuckets = []float64{.005, .01, .025, .05, .1, .25, .5, 1, 2.5, 5, 10}
+)
+
+// LinearBuckets 线性分桶：start, start+width, ...
+func LinearBuckets(start float64, width float64, count int) []float64 {
+	buckets := make([]float64, count)
+	for i := range buckets {
+		buckets[i] = start + float64(i)*width
+	}
+	return buckets
+}
+
+// ExponentialBuckets 指数分桶：start, start*factor, ...
+func ExponentialBuckets(start float64, factor float64, count int) []float64 {
+	buckets := make([]float64, count)
+	for i := range buckets {
+		buckets[i] = start
+		start *= factor
+	}
+	return buckets
+}
+
+// Histogram 直方图
+type Histogram struct {
+	upperBounds []float64
+	counts      []atomic.Uint64
+	count       atomic.Uint64
+	sum         atomicFloat
+}
+
+func newHistogram(upperBounds []float64) *Histogram {
+	return &Histogram{
+		upperBounds: upperBounds,
+		counts:      make([]atomic.Uint64, len(upperBounds)),
+	}
+}
+
+// Observe 记录一次观测值
+func (h *Histogram) Observe(v float64) {
+	// 落入第一个上界不小于 v 的分桶，输出时再累加
+	if i := sort.SearchFloat64s(h.upperBounds, v); i < len(h.upperBounds) {
+		h.counts[i].Add(1)
+	}
+	h.sum.Add(v)
+	h.count.Add(1)
+}
+
+func (h *Histogram) samples(name string, labels []Label) []Sample {
+	samples := make([]Sample, 0, len(h.upperBounds)+3)
+
+	var cumulative uint64
+	for i, bound := range h.upperBounds {
+		cumulative += h.counts[i].Load()
+		samples = append(samples, Sample{
+			Name:   name + "_bucket",
+			Labels: withLabel(labels, "le", formatFloat(bound)),
+			Value:  float64(cumulative),
+		})
+	}
+
+	count := h.count.Load()
+	samples = append(samples,
+		Sample{Name: name + "_bucket", Labels: withLabel(labels, "le", "+Inf"), Value: float64(count)},
+		Sample{Name: name + "_sum", Labels: labels, Value: h.sum.Load()},
+		Sample{Name: name + "_count", Labels: labels, Value: float64(count)},
+	)
+
+	return samples
+}
+
+// HistogramVec 带标签的直方图
+type HistogramVec struct {
+	vec *vec[*Histogram]
+}
+
+// NewHistogramVec 创建带标签的直方图，buckets 为空时使用 DefBuckets
+func NewHistogramVec(opts Opts, buckets []float64, labelNames ...string) (*HistogramVec, error) {
+	// le 为分桶标签，不能作为标签或固定标签
+	for _, name := range labelNames {
+		if name == "le" {
+			return nil, ErrInvalidLabelName
+		}
+	}
+	if _, ok := opts.ConstLabels["le"]; ok {
+		return nil, ErrInvalidLabelName
+	}
+
+	desc, err := newDesc(opts, labelNames)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(buckets) == 0 {
+		buckets = DefBuckets
+	}
+	upperBounds := make([]float64, 0, len(buckets))
+	for i, bound := range buckets {
+		if i > 0 && bound <= buckets[i-1] {
+			return nil, ErrInvalidBuckets
+		}
+		// +Inf 分桶总是输出，无需重复
+		if math.IsInf(bound, 1) {
+			continue
+		}
+		upperBounds = append(upperBounds, bound)
+	}
+
+	return &HistogramVec{
+		vec: newVec(desc, func() *Histogram { return newHistogram(upperBounds) }),
+	}, nil
+}
+
+// MustNewHistogramVec 创建带标签的直方图，配置错误时 panic
+func MustNewHistogramVec(opts Opts, buckets []float64, labelNames ...string) *HistogramVec {
+	v, err := NewHistogramVec(opts, buckets, labelNames...)
+	mustNot(err)
+	return v
+}
+
+// GetMetricWithLabelValues 按标签值获取直方图
+func (v *HistogramVec) GetMetricWithLabelValues(values ...string) (*Histogram, error) {
+	return v.vec.get(values)
+}
+
+// WithLabelValues 按标签值获取直方图，标签数量不一致时 panic
+func (v *HistogramVec) WithLabelValues(values ...string) *Histogram {
+	h, err := v.vec.get(values)
+	mustNot(err)
+	return h
+}
+
+// DeleteLabelValues 删除指定标签值的直方图
+func (v *HistogramVec) DeleteLabelValues(values ...string) bool {
+	return v.vec.delete(values)
+}
+
+// Reset 清空所有直方图
+func (v *HistogramVec) Reset() {
+	v.vec.reset()
+}
+
+func (v *HistogramVec) Names() []string {
+	return []string{v.vec.desc.name}
+}
+
+func (v *HistogramVec) Collect() []*MetricFamily {
+	family := &MetricFamily{
+		Name: v.vec.desc.name,
+		Help: v.vec.desc.help,
+		Type: HistogramType,
+	}
+
+	v.vec.each(func(labels []Label, h *Histogram) {
+		family.Samples = append(family.Samples, h.samples(family.Name, labels)...)
+	})
+
+	return []*MetricFamily{family}
+}
+
+func withLabel(labels []Label, name string, value string) []Label {
+	result := make([]Label, 0, len(labels)+1)
+	result = append(result, labels...)
+	return append(result, Label{Name: name, Value: value})
+}
+
+func formatFloat(v float64) string {
+	switch {
+	case math.IsInf(v, 1):
+		return "+Inf"
+	case math.IsInf(v, -1):
+		return "-Inf"
+	case math.IsNaN(v):
+		return "NaN"
+	}
+	return strconv.FormatFloat(v, 'g', -1, 64)
+}
@@ -0,0 +1,84 @@
+package goometrics
+
+import (
+	"errors"
+	"math"
+	"strings"
+	"testing"
+)
+
+func TestHistogramBucketsAndSum(t *testing.T) {
+	h := MustNewHistogramVec(Opts{Name: "latency_seconds", Help: "耗时"}, []float64{0.1, 0.5, 1, math.Inf(1)}, "route")
+
+	// 等于上界的值落入该分桶（le 包含上界），超过最大上界的值只计入 +Inf
+	for _, v := range []float64{0.05, 0.1, 0.3, 1, 2} {
+		h.WithLabelValues("/users").Observe(v)
+	}
+
+	r := NewRegistry()
+	r.MustRegister(h)
+
+	var buf strings.Builder
+	r.WriteTo(&buf)
+
+	want := `# HELP latency_seconds 耗时
+# TYPE latency_seconds histogram
+latency_seconds_bucket{route="/users",le="0.1"} 2
+latency_seconds_bucket{route="/users",le="0.5"} 3
+latency_seconds_bucket{route="/users",le="1"} 4
+latency_seconds_bucket{route="/users",le="+Inf"} 5
+latency_seconds_sum{route="/users"} 3.45
+latency_seconds_count{route="/users"} 5
+`
+	if got := buf.String(); got != want {
+		t.Fatalf("output:\n%s\nwant:\n%s", got, want)
+	}
+}
+
+func TestHistogramDefaultBuckets(t *testing.T) {
+	h := MustNewHistogramVec(Opts{Name: "default_seconds"}, nil)
+	h.WithLabelValues().Observe(0.2)
+
+	families := h.Collect()
+	// DefBuckets 每个上界一个分桶，加上 +Inf、_sum、_count
+	if got, want := len(families[0].Samples), len(DefBuckets)+3; got != want {
+		t.Fatalf("samples = %d, want %d", got, want)
+	}
+}
+
+func TestHistogramInvalidConfig(t *testing.T) {
+	if _, err := NewHistogramVec(Opts{Name: "h"}, []float64{1, 1}); !errors.Is(err, ErrInvalidBuckets) {
+		t.Fatalf("buckets err = %v", err)
+	}
+	if _, err := NewHistogramVec(Opts{Name: "h"}, nil, "le"); !errors.Is(err, ErrInvalidLabelName) {
+		t.Fatalf("le label err = %v", err)
+	}
+	if _, err := NewHistogramVec(Opts{Name: "h", ConstLabels: map[string]string{"le": "1"}}, nil); !errors.Is(err, ErrInvalidLabelName) {
+		t.Fatalf("le const label err = %v", err)
+	}
+}
+
+func TestBucketHelpers(t *testing.T) {
+	if got := LinearBuckets(1, 2, 3); got[0] != 1 || got[1] != 3 || got[2] != 5 {
+		t.Fatalf("LinearBuckets = %v", got)
+	}
+	if got := ExponentialBuckets(1, 10, 3); got[0] != 1 || got[1] != 10 || got[2] != 100 {
+		t.Fatalf("ExponentialBuckets = %v", got)
+	}
+}
+
+func TestFormatFloat(t *testing.T) {
+	tests := map[float64]string{
+		1:            "1",
+		0.005:        "0.005",
+		1e21:         "1e+21",
+		math.Inf(1):  "+Inf",
+		math.Inf(-1): "-Inf",
+		math.NaN():   "NaN",
+	}
+	for v, want := range tests {
+		if got := formatFloat(v); got != want {
+			t.Errorf("formatFloat(%v) = %q, want %q", v, got, want)
+		}
+	}
+}
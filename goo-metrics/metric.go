@@ -0,0 +1,240 @@
+package goometrics
+
+import (
+	"fmt"
+	"math"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+	"sync/atomic"
+)
+
+// MetricType 指标类型
+type MetricType string
+
+const (
+	CounterType   MetricType = "counter"
+	GaugeType     MetricType = "gauge"
+	HistogramType MetricType = "histogram"
+	UntypedType   MetricType = "untyped"
+)
+
+var (
+	metricNameRegexp = regexp.MustCompile(`^[a-zA-Z_:][a-zA-Z0-9_:]*$`)
+	labelNameRegexp  = regexp.MustCompile(`^[a-zA-Z_][a-zA-Z0-9_]*$`)
+)
+
+// Label 标签
+type Label struct {
+	Name  string
+	Value string
+}
+
+// Sample 采样值，Name 为完整的指标名称（直方图包含 _bucket/_sum/_count 后缀）
+type Sample struct {
+	Name   string
+	Labels []Label
+	Value  float64
+}
+
+// MetricFamily 同名指标的集合
+type MetricFamily struct {
+	Name    string
+	Help    string
+	Type    MetricType
+	Samples []Sample
+}
+
+// Collector 指标收集器，其他模块实现该接口即可注册到 Registry
+type Collector interface {
+	// Names 收集器输出的指标名称，用于注册时检查重复
+	Names() []string
+	// Collect 收集指标
+	Collect() []*MetricFamily
+}
+
+// Opts 指标配置
+type Opts struct {
+	Namespace   string            // 命名空间，如 goohttp
+	Subsystem   string            // 子系统
+	Name        string            // 名称
+	Help        string            // 说明
+	ConstLabels map[string]string // 固定标签
+}
+
+// FullName 拼接完整的指标名称
+func (o Opts) FullName() string {
+	var parts []string
+	for _, part := range []string{o.Namespace, o.Subsystem, o.Name} {
+		if part != "" {
+			parts = append(parts, part)
+		}
+	}
+	return strings.Join(parts, "_")
+}
+
+// desc 指标描述
+type desc struct {
+	name        string
+	help        string
+	labelNames  []string
+	constLabels []Label
+}
+
+func newDesc(opts Opts, labelNames []string) (*desc, error) {
+	name := opts.FullName()
+	if !metricNameRegexp.MatchString(name) {
+		return nil, fmt.Errorf("%w: %s", ErrInvalidName, name)
+	}
+
+	for _, label := range labelNames {
+		if !labelNameRegexp.MatchString(label) || strings.HasPrefix(label, "__") {
+			return nil, fmt.Errorf("%w: %s", ErrInvalidLabelName, label)
+		}
+	}
+
+	constLabels := make([]Label, 0, len(opts.ConstLabels))
+	for k, v := range opts.ConstLabels {
+		if !labelNameRegexp.MatchString(k) {
+			return nil, fmt.Errorf("%w: %s", ErrInvalidLabelName, k)
+		}
+		constLabels = append(constLabels, Label{Name: k, Value: v})
+	}
+	sort.Slice(constLabels, func(i, j int) bool {
+		return constLabels[i].Name < constLabels[j].Name
+	})
+
+	return &desc{
+		name:        name,
+		help:        opts.Help,
+		labelNames:  append([]string(nil), labelNames...),
+		constLabels: constLabels,
+	}, nil
+}
+
+func (d *desc) labels(values []string) []Label {
+	labels := make([]Label, 0, len(d.constLabels)+len(values))
+	labels = append(labels, d.constLabels...)
+	for i, name := range d.labelNames {
+		labels = append(labels, Label{Name: name, Value: values[i]})
+	}
+	return labels
+}
+
+// atomicFloat 原子浮点数
+type atomicFloat struct {
+	bits atomic.Uint64
+}
+
+func (f *atomicFloat) Add(delta float64) {
+	for {
+		old := f.bits.Load()
+		if f.bits.CompareAndSwap(old, math.Float64bits(math.Float64frombits(old)+delta)) {
+			return
+		}
+	}
+}
+
+func (f *atomicFloat) Set(v float64) {
+	f.bits.Store(math.Float64bits(v))
+}
+
+func (f *atomicFloat) Load() float64 {
+	return math.Float64frombits(f.bits.Load())
+}
+
+// vec 按标签值保存子指标
+type vec[T any] struct {
+	desc     *desc
+	newChild func() T
+	children map[string]*vecChild[T]
+	mu       sync.RWMutex
+}
+
+type vecChild[T any] struct {
+	values []string
+	metric T
+}
+
+func newVec[T any](desc *desc, newChild func() T) *vec[T] {
+	return &vec[T]{
+		desc:     desc,
+		newChild: newChild,
+		children: make(map[string]*vecChild[T]),
+	}
+}
+
+func (v *vec[T]) get(values []string) (T, error) {
+	if len(values) != len(v.desc.labelNames) {
+		var zero T
+		return zero, fmt.Errorf("%w: %s 需要 %d 个，实际 %d 个", ErrLabelCount, v.desc.name, len(v.desc.labelNames), len(values))
+	}
+
+	key := strings.Join(values, "\xff")
+
+	v.mu.RLock()
+	child, ok := v.children[key]
+	v.mu.RUnlock()
+	if ok {
+		return child.metric, nil
+	}
+
+	v.mu.Lock()
+	defer v.mu.Unlock()
+
+	if child, ok = v.children[key]; ok {
+		return child.metric, nil
+	}
+
+	child = &vecChild[T]{
+		values: append([]string(nil), values...),
+		metric: v.newChild(),
+	}
+	v.children[key] = child
+
+	return child.metric, nil
+}
+
+func (v *vec[T]) delete(values []string) bool {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+
+	key := strings.Join(values, "\xff")
+	if _, ok := v.children[key]; !ok {
+		return false
+	}
+	delete(v.children, key)
+
+	return true
+}
+
+func (v *vec[T]) reset() {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	v.children = make(map[string]*vecChild[T])
+}
+
+// each 按标签值排序遍历，保证输出稳定
+func (v *vec[T]) each(fn func(labels []Label, metric T)) {
+	v.mu.RLock()
+	children := make([]*vecChild[T], 0, len(v.children))
+	for _, child := range v.children {
+		children = append(children, child)
+	}
+	v.mu.RUnlock()
+
+	sort.Slice(children, func(i, j int) bool {
+		return strings.Join(children[i].values, "\xff") < strings.Join(children[j].values, "\xff")
+	})
+
+	for _, child := range children {
+		fn(v.desc.labels(child.values), child.metric)
+	}
+}
+
+func mustNot(err error) {
+	if err != nil {
+		panic(err)
+	}
+}
@@ -0,0 +1,178 @@
+package goometrics
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// ContentType Prometheus 文本格式
+const ContentType = "text/plain; version=0.0.4; charset=utf-8"
+
+// Registry 指标注册表
+type Registry struct {
+	collectors []Collector
+	names      map[string]Collector
+	mu         sync.RWMutex
+}
+
+func NewRegistry() *Registry {
+	return &Registry{
+		names: make(map[string]Collector),
+	}
+}
+
+// Register 注册收集器，指标名称重复时返回 ErrAlreadyRegistered
+func (r *Registry) Register(c Collector) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	names := c.Names()
+	for _, name := range names {
+		if _, exists := r.names[name]; exists {
+			return fmt.Errorf("%w: %s", ErrAlreadyRegistered, name)
+		}
+	}
+
+	for _, name := range names {
+		r.names[name] = c
+	}
+	r.collectors = append(r.collectors, c)
+
+	return nil
+}
+
+// MustRegister 注册收集器，失败时 panic
+func (r *Registry) MustRegister(cs ...Collector) {
+	for _, c := range cs {
+		mustNot(r.Register(c))
+	}
+}
+
+// Unregister 注销收集器
+func (r *Registry) Unregister(c Collector) bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for i, collector := range r.collectors {
+		if collector != c {
+			continue
+		}
+
+		for _, name := range c.Names() {
+			delete(r.names, name)
+		}
+		r.collectors = append(r.collectors[:i], r.collectors[i+1:]...)
+
+		return true
+	}
+
+	return false
+}
+
+// Lookup 按指标名称获取已注册的收集器
+func (r *Registry) Lookup(name string) (Collector, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	c, ok := r.names[name]
+	return c, ok
+}
+
+// Gather 收集所有指标，按名称排序
+func (r *Registry) Gather() []*MetricFamily {
+	r.mu.RLock()
+	collectors := append([]Collector(nil), r.collectors...)
+	r.mu.RUnlock()
+
+	var families []*MetricFamily
+	for _, c := range collectors {
+		families = append(families, c.Collect()...)
+	}
+
+	sort.SliceStable(families, func(i, j int) bool {
+		return families[i].Name < families[j].Name
+	})
+
+	return families
+}
+
+// WriteTo 以 Prometheus 文本格式输出所有指标
+func (r *Registry) WriteTo(w io.Writer) (int64, error) {
+	bw := bufio.NewWriter(w)
+	cw := &countWriter{w: bw}
+
+	for _, family := range r.Gather() {
+		writeFamily(cw, family)
+	}
+
+	if err := bw.Flush(); err != nil {
+		return cw.n, err
+	}
+
+	return cw.n, nil
+}
+
+// Handler 指标输出接口
+func (r *Registry) Handler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		w.Header().Set("Content-Type", ContentType)
+		w.Header().Set("Cache-Control", "no-store")
+		r.WriteTo(w)
+	})
+}
+
+func writeFamily(w io.Writer, family *MetricFamily) {
+	if family.Help != "" {
+		fmt.Fprintf(w, "# HELP %s %s\n", family.Name, escapeHelp(family.Help))
+	}
+
+	typ := family.Type
+	if typ == "" {
+		typ = UntypedType
+	}
+	fmt.Fprintf(w, "# TYPE %s %s\n", family.Name, typ)
+
+	for _, sample := range family.Samples {
+		io.WriteString(w, sample.Name)
+		if len(sample.Labels) > 0 {
+			io.WriteString(w, "{")
+			for i, label := range sample.Labels {
+				if i > 0 {
+					io.WriteString(w, ",")
+				}
+				fmt.Fprintf(w, `%s="%s"`, label.Name, escapeLabelValue(label.Value))
+			}
+			io.WriteString(w, "}")
+		}
+		fmt.Fprintf(w, " %s\n", formatFloat(sample.Value))
+	}
+}
+
+var (
+	helpReplacer       = strings.NewReplacer(`\`, `\\`, "\n", `\n`)
+	labelValueReplacer = strings.NewReplacer(`\`, `\\`, "\n", `\n`, `"`, `\"`)
+)
+
+func escapeHelp(s string) string {
+	return helpReplacer.Replace(s)
+}
+
+func escapeLabelValue(s string) string {
+	return labelValueReplacer.Replace(s)
+}
+
+type countWriter struct {
+	w io.Writer
+	n int64
+}
+
+func (w *countWriter) Write(p []byte) (int, error) {
+	n, err := w.w.Write(p)
+	w.n += int64(n)
+	return n, err
+}
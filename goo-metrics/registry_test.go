@@ -0,0 +1,126 @@
+package goometrics
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestRegistryWriteToExpositionFormat(t *testing.T) {
+	r := NewRegistry()
+
+	requests := MustNewCounterVec(Opts{
+		Namespace:   "goohttp",
+		Name:        "requests_total",
+		Help:        "请求数",
+		ConstLabels: map[string]string{"service": "api"},
+	}, "method", "code")
+	requests.WithLabelValues("POST", "500").Inc()
+	requests.WithLabelValues("GET", "200").Add(2)
+	requests.WithLabelValues("GET", "200").Add(-1) // 负数忽略
+
+	inflight := MustNewGaugeVec(Opts{Name: "inflight"})
+	inflight.WithLabelValues().Set(3)
+	inflight.WithLabelValues().Dec()
+
+	pool := MustNewGaugeFunc(Opts{Name: "pool_size", Help: "连接池大小"}, func() float64 { return 1.5 })
+
+	r.MustRegister(requests, inflight, pool)
+
+	var buf strings.Builder
+	n, err := r.WriteTo(&buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if int(n) != buf.Len() {
+		t.Fatalf("WriteTo n = %d, written %d", n, buf.Len())
+	}
+
+	// 按指标名称排序，标签值排序，固定标签在前
+	want := `# HELP goohttp_requests_total 请求数
+# TYPE goohttp_requests_total counter
+goohttp_requests_total{service="api",method="GET",code="200"} 2
+goohttp_requests_total{service="api",method="POST",code="500"} 1
+# TYPE inflight gauge
+inflight 2
+# HELP pool_size 连接池大小
+# TYPE pool_size gauge
+pool_size 1.5
+`
+	if got := buf.String(); got != want {
+		t.Fatalf("output:\n%s\nwant:\n%s", got, want)
+	}
+}
+
+func TestRegistryEscaping(t *testing.T) {
+	r := NewRegistry()
+
+	c := MustNewCounterVec(Opts{Name: "escape_total", Help: "line1\nback\\slash \"quoted\""}, "path")
+	c.WithLabelValues("a\"b\\c\nd").Inc()
+	r.MustRegister(c)
+
+	var buf strings.Builder
+	r.WriteTo(&buf)
+
+	// HELP 只转义反斜杠和换行，标签值还需转义双引号
+	want := `# HELP escape_total line1\nback\\slash "quoted"
+# TYPE escape_total counter
+escape_total{path="a\"b\\c\nd"} 1
+`
+	if got := buf.String(); got != want {
+		t.Fatalf("output:\n%s\nwant:\n%s", got, want)
+	}
+}
+
+func TestRegistryRejectsDuplicateNames(t *testing.T) {
+	r := NewRegistry()
+
+	first := MustNewCounterVec(Opts{Name: "dup_total"})
+	if err := r.Register(first); err != nil {
+		t.Fatal(err)
+	}
+	if err := r.Register(MustNewGaugeVec(Opts{Name: "dup_total"})); !errors.Is(err, ErrAlreadyRegistered) {
+		t.Fatalf("err = %v, want %v", err, ErrAlreadyRegistered)
+	}
+
+	if !r.Unregister(first) {
+		t.Fatal("Unregister returned false")
+	}
+	if err := r.Register(MustNewGaugeVec(Opts{Name: "dup_total"})); err != nil {
+		t.Fatalf("register after unregister: %v", err)
+	}
+}
+
+func TestInvalidNames(t *testing.T) {
+	if _, err := NewCounterVec(Opts{Name: "1bad"}); !errors.Is(err, ErrInvalidName) {
+		t.Fatalf("metric name err = %v", err)
+	}
+	if _, err := NewCounterVec(Opts{Name: "ok"}, "__reserved"); !errors.Is(err, ErrInvalidLabelName) {
+		t.Fatalf("label name err = %v", err)
+	}
+	if _, err := NewCounterVec(Opts{Name: "ok"}, "a"); err != nil {
+		t.Fatal(err)
+	}
+
+	c := MustNewCounterVec(Opts{Name: "ok"}, "a")
+	if _, err := c.GetMetricWithLabelValues("x", "y"); !errors.Is(err, ErrLabelCount) {
+		t.Fatalf("label count err = %v", err)
+	}
+}
+
+func TestRegistryHandler(t *testing.T) {
+	r := NewRegistry()
+	r.MustRegister(MustNewGaugeFunc(Opts{Name: "up"}, func() float64 { return 1 }))
+
+	w := httptest.NewRecorder()
+	r.Handler().ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+
+	if ct := w.Header().Get("Content-Type"); ct != ContentType {
+		t.Fatalf("Content-Type = %q", ct)
+	}
+	if !strings.Contains(w.Body.String(), "\nup 1\n") {
+		t.Fatalf("body = %s", w.Body)
+	}
+}
@@ -0,0 +1,67 @@
+package goometrics
+
+import (
+	"os"
+	"runtime"
+	"time"
+)
+
+// RuntimeCollector Go 运行时和进程指标
+type RuntimeCollector struct {
+	startTime float64
+}
+
+func NewRuntimeCollector() *RuntimeCollector {
+	return &RuntimeCollector{
+		startTime: float64(time.Now().UnixNano()) / 1e9,
+	}
+}
+
+func (c *RuntimeCollector) Names() []string {
+	return []string{
+		"go_goroutines",
+		"go_threads",
+		"go_info",
+		"go_memstats_alloc_bytes",
+		"go_memstats_heap_inuse_bytes",
+		"go_memstats_sys_bytes",
+		"go_gc_cycles_total",
+		"go_gc_pause_seconds_total",
+		"process_start_time_seconds",
+		"process_pid",
+	}
+}
+
+func (c *RuntimeCollector) Collect() []*MetricFamily {
+	var ms runtime.MemStats
+	runtime.ReadMemStats(&ms)
+
+	threads, _ := runtime.ThreadCreateProfile(nil)
+
+	gauge := func(name string, help string, value float64, labels ...Label) *MetricFamily {
+		return &MetricFamily{
+			Name:    name,
+			Help:    help,
+			Type:    GaugeType,
+			Samples: []Sample{{Name: name, Labels: labels, Value: value}},
+		}
+	}
+	counter := func(name string, help string, value float64) *MetricFamily {
+		family := gauge(name, help, value)
+		family.Type = CounterType
+		return family
+	}
+
+	return []*MetricFamily{
+		gauge("go_goroutines", "协程数量", float64(runtime.NumGoroutine())),
+		gauge("go_threads", "系统线程数量", float64(threads)),
+		gauge("go_info", "Go 版本", 1, Label{Name: "version", Value: runtime.Version()}),
+		gauge("go_memstats_alloc_bytes", "已分配且仍在使用的堆内存", float64(ms.Alloc)),
+		gauge("go_memstats_heap_inuse_bytes", "使用中的堆内存", float64(ms.HeapInuse)),
+		gauge("go_memstats_sys_bytes", "从系统申请的内存", float64(ms.Sys)),
+		counter("go_gc_cycles_total", "GC 次数", float64(ms.NumGC)),
+		counter("go_gc_pause_seconds_total", "GC 暂停总时间", float64(ms.PauseTotalNs)/1e9),
+		gauge("process_start_time_seconds", "进程启动时间（Unix 时间戳）", c.startTime),
+		gauge("process_pid", "进程 ID", float64(os.Getpid())),
+	}
+}
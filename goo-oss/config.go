@@ -1,6 +1,7 @@
 package goooss
 
 import (
+	"fmt"
 	"time"
 )
 
@@ -71,3 +72,35 @@ func (c *Config) getCredentials() (string, string, string) {
 	return c.AccessKeyID, c.AccessKeySecret, ""
 }
 
+// Format 打印配置时将 AccessKeySecret 替换为 ******，对所有格式化动词生效
+func (c Config) Format(f fmt.State, verb rune) {
+	type plain Config
+	redacted := plain(c)
+	if redacted.AccessKeySecret != "" {
+		redacted.AccessKeySecret = "******"
+	}
+	fmt.Fprintf(f, fmt.FormatString(f, verb), redacted)
+}
+
+// String 实现 fmt.Stringer
+func (c Config) String() string {
+	return fmt.Sprint(c)
+}
+
+// Format 打印配置时将 AccessKeySecret 和 SecurityToken 替换为 ******，对所有格式化动词生效
+func (c STSConfig) Format(f fmt.State, verb rune) {
+	type plain STSConfig
+	redacted := plain(c)
+	if redacted.AccessKeySecret != "" {
+		redacted.AccessKeySecret = "******"
+	}
+	if redacted.SecurityToken != "" {
+		redacted.SecurityToken = "******"
+	}
+	fmt.Fprintf(f, fmt.FormatString(f, verb), redacted)
+}
+
+// String 实现 fmt.Stringer
+func (c STSConfig) String() string {
+	return fmt.Sprint(c)
+}
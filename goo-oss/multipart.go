@@ -0,0 +1,67 @@
+package goooss
+
+import (
+	"context"
+	"io"
+
+	"github.com/aliyun/aliyun-oss-go-sdk/oss"
+)
+
+// MultipartUploader 分片上传适配器
+// 实现 goohttp.MultipartUploader，用于将断点续传的上传合并为 OSS 对象：
+//
+//	store, _ := goohttp.NewMultipartUploadStore(goooss.NewMultipartUploader(client), nil)
+type MultipartUploader struct {
+	client  *Client
+	options []oss.Option
+}
+
+// NewMultipartUploader 创建分片上传适配器，options 在初始化分片上传时使用（如 oss.ObjectACL、oss.ContentType）
+func NewMultipartUploader(client *Client, options ...oss.Option) *MultipartUploader {
+	return &MultipartUploader{
+		client:  client,
+		options: options,
+	}
+}
+
+func (u *MultipartUploader) imur(key string, uploadId string) oss.InitiateMultipartUploadResult {
+	return oss.InitiateMultipartUploadResult{
+		Bucket:   u.client.Bucket().BucketName,
+		Key:      key,
+		UploadID: uploadId,
+	}
+}
+
+// InitiateMultipart 初始化分片上传
+func (u *MultipartUploader) InitiateMultipart(ctx context.Context, key string) (string, error) {
+	result, err := u.client.InitiateMultipartUpload(ctx, key, u.options...)
+	if err != nil {
+		return "", err
+	}
+	return result.UploadID, nil
+}
+
+// UploadPart 上传分片
+func (u *MultipartUploader) UploadPart(ctx context.Context, key string, uploadId string, partNumber int, r io.Reader, size int64) (string, error) {
+	part, err := u.client.Bucket().UploadPart(u.imur(key, uploadId), r, size, partNumber)
+	if err != nil {
+		return "", err
+	}
+	return part.ETag, nil
+}
+
+// CompleteMultipart 完成分片上传
+func (u *MultipartUploader) CompleteMultipart(ctx context.Context, key string, uploadId string, etags []string) error {
+	parts := make([]oss.UploadPart, 0, len(etags))
+	for i, etag := range etags {
+		parts = append(parts, oss.UploadPart{PartNumber: i + 1, ETag: etag})
+	}
+
+	_, err := u.client.CompleteMultipartUpload(ctx, u.imur(key, uploadId), parts)
+	return err
+}
+
+// AbortMultipart 取消分片上传
+func (u *MultipartUploader) AbortMultipart(ctx context.Context, key string, uploadId string) error {
+	return u.client.AbortMultipartUpload(ctx, u.imur(key, uploadId))
+}
@@ -1,6 +1,7 @@
 package gooredis
 
 import (
+	"fmt"
 	"time"
 
 	"github.com/go-redis/redis/v8"
@@ -113,3 +114,18 @@ func (c *Config) toRedisOptions() *redis.Options {
 
 	return opts
 }
+
+// Format 打印配置时将密码替换为 ******，对所有格式化动词生效
+func (c Config) Format(f fmt.State, verb rune) {
+	type plain Config
+	redacted := plain(c)
+	if redacted.Password != "" {
+		redacted.Password = "******"
+	}
+	fmt.Fprintf(f, fmt.FormatString(f, verb), redacted)
+}
+
+// String 实现 fmt.Stringer
+func (c Config) String() string {
+	return fmt.Sprint(c)
+}
@@ -2,6 +2,7 @@ package gooredis
 
 import (
 	"context"
+	"sort"
 	"sync"
 
 	"github.com/go-redis/redis/v8"
@@ -62,6 +63,20 @@ func GetClient(name string) (*Client, error) {
 	return client, nil
 }
 
+// Names 获取所有已注册的客户端名称
+func Names() []string {
+	mu.RLock()
+	defer mu.RUnlock()
+
+	names := make([]string, 0, len(clients))
+	for name := range clients {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	return names
+}
+
 // Default 获取默认客户端
 func Default() (*Client, error) {
 	return GetClient(defaultName)